@@ -0,0 +1,79 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+)
+
+// LinearArray 将当前三角形沿delta方向线性阵列count份(含原件)
+func (h *Handler) LinearArray(count int, delta []float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if count < 1 || len(delta) != 3 {
+		h.error = fmt.Errorf("linear array: invalid count %d or delta %v", count, delta)
+		return h
+	}
+
+	h.Triangles = math_lib.LinearArray(h.Triangles, count, mat.NewVecDense(3, delta))
+	return h
+}
+
+// GridArray 将当前三角形按矩形网格阵列，counts[i]与deltas[i]分别为第i个方向的数量和间距向量
+func (h *Handler) GridArray(counts []int, deltas [][]float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if len(counts) != len(deltas) {
+		h.error = fmt.Errorf("grid array: %d counts but %d deltas", len(counts), len(deltas))
+		return h
+	}
+
+	vecs := make([]*mat.VecDense, len(deltas))
+	for i := range deltas {
+		if counts[i] < 1 || len(deltas[i]) != 3 {
+			h.error = fmt.Errorf("grid array: invalid count %d or delta %v", counts[i], deltas[i])
+			return h
+		}
+		vecs[i] = mat.NewVecDense(3, deltas[i])
+	}
+
+	h.Triangles = math_lib.GridArray(h.Triangles, counts, vecs)
+	return h
+}
+
+// PolarArray 将当前三角形绕过center、方向为axis的轴在angle弧度范围内环形阵列count份(含原件)
+func (h *Handler) PolarArray(count int, axis, center []float64, angle float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if count < 1 || len(axis) != 3 || len(center) != 3 {
+		h.error = fmt.Errorf("polar array: invalid count %d, axis %v or center %v", count, axis, center)
+		return h
+	}
+	axisVec := mat.NewVecDense(3, axis)
+	if mat.Norm(axisVec, 2) == 0 {
+		h.error = fmt.Errorf("polar array: zero-length axis %v", axis)
+		return h
+	}
+
+	h.Triangles = math_lib.PolarArray(h.Triangles, count, axisVec, mat.NewVecDense(3, center), angle)
+	return h
+}
+
+// InstanceArray 将当前三角形按4x4齐次变换列表复制，每个变换生成一份副本
+func (h *Handler) InstanceArray(transforms []*mat.Dense) *Handler {
+	if h.error != nil {
+		return h
+	}
+	for _, m := range transforms {
+		if r, c := m.Dims(); r != 4 || c != 4 {
+			h.error = fmt.Errorf("instance array: transform must be 4x4, got %dx%d", r, c)
+			return h
+		}
+	}
+
+	h.Triangles = math_lib.InstanceArray(h.Triangles, transforms)
+	return h
+}
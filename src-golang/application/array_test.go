@@ -0,0 +1,35 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPolarArray(t *testing.T) {
+	tri := &math_lib.Triangle{P: [3]*mat.VecDense{
+		mat.NewVecDense(3, []float64{1, 0, 0}),
+		mat.NewVecDense(3, []float64{2, 0, 0}),
+		mat.NewVecDense(3, []float64{1, 1, 0}),
+	}}
+
+	h := NewHandler()
+	h.Triangles = []*math_lib.Triangle{tri}
+	if err := h.PolarArray(4, []float64{0, 0, 1}, []float64{0, 0, 0}, 2*math.Pi).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Triangles) != 4 {
+		t.Errorf("got %d triangles, want 4", len(h.Triangles))
+	}
+
+	h = NewHandler()
+	h.Triangles = []*math_lib.Triangle{tri}
+	if err := h.PolarArray(4, []float64{0, 0, 0}, []float64{0, 0, 0}, math.Pi).Err(); err == nil {
+		t.Error("expected an error for a zero-length axis")
+	}
+	if len(h.Triangles) != 1 {
+		t.Errorf("failed array changed the triangles")
+	}
+}
@@ -0,0 +1,28 @@
+package application
+
+import "Geometric_Construction/math_lib"
+
+// Union 用当前三角形与other的并集替换当前三角形，两者都应为封闭且法向量朝外的网格
+func (h *Handler) Union(other []*math_lib.Triangle) *Handler {
+	return h.boolean(other, math_lib.BooleanUnion)
+}
+
+// Intersect 用当前三角形与other的交集替换当前三角形
+func (h *Handler) Intersect(other []*math_lib.Triangle) *Handler {
+	return h.boolean(other, math_lib.BooleanIntersection)
+}
+
+// Subtract 从当前三角形中减去other
+func (h *Handler) Subtract(other []*math_lib.Triangle) *Handler {
+	return h.boolean(other, math_lib.BooleanDifference)
+}
+
+// boolean 执行网格布尔运算
+func (h *Handler) boolean(other []*math_lib.Triangle, op math_lib.BooleanOp) *Handler {
+	if h.error != nil {
+		return h
+	}
+
+	h.Triangles = math_lib.MeshBoolean(h.Triangles, other, op)
+	return h
+}
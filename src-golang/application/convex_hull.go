@@ -0,0 +1,31 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ConvexHull 以当前所有三角形的顶点计算三维凸包，并用凸包替换当前三角形
+func (h *Handler) ConvexHull() *Handler {
+	if h.error != nil {
+		return h
+	}
+
+	points := make([]*mat.VecDense, 0, 3*len(h.Triangles))
+	for _, tri := range h.Triangles {
+		points = append(points, tri.P[:]...)
+	}
+
+	res := math_lib.ConvexHull(points)
+	if len(res) == 0 {
+		h.error = fmt.Errorf("convex hull: need at least 3 non-collinear vertices, got %d triangles", len(h.Triangles))
+		return h
+	}
+
+	h.Triangles = make([]*math_lib.Triangle, len(res))
+	for i := range res {
+		h.Triangles[i] = &res[i]
+	}
+	return h
+}
@@ -0,0 +1,48 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+)
+
+// Curvature 计算当前三角形组成的网格(坐标相同的顶点视为同一顶点)每个顶点的曲率写入curvature，并将该网格写入mesh，两者均可为nil
+func (h *Handler) Curvature(curvature *math_lib.MeshCurvature, mesh *math_lib.Mesh) *Handler {
+	if h.error != nil {
+		return h
+	}
+
+	m := math_lib.NewMesh(h.Triangles)
+	if curvature != nil {
+		*curvature = m.Curvature()
+	}
+	if mesh != nil {
+		*mesh = *m
+	}
+	return h
+}
+
+// SaveCurvaturePLY 计算当前网格的曲率并保存为PLY文件，顶点属性依次为法向量nx/ny/nz、
+// 平均曲率mean_curvature、高斯曲率gaussian_curvature与主曲率k1/k2，可在查看器中按属性着色
+func (h *Handler) SaveCurvaturePLY(filename string) *Handler {
+	var mesh math_lib.Mesh
+	var c math_lib.MeshCurvature
+	if h.Curvature(&c, &mesh); h.error != nil {
+		return h
+	}
+
+	normal := func(d int) []float64 {
+		res := make([]float64, len(c.Normals))
+		for i, n := range c.Normals {
+			res[i] = n.AtVec(d)
+		}
+		return res
+	}
+	err := SavePLY(&mesh, filename,
+		VertexAttribute{"nx", normal(0)}, VertexAttribute{"ny", normal(1)}, VertexAttribute{"nz", normal(2)},
+		VertexAttribute{"mean_curvature", c.Mean}, VertexAttribute{"gaussian_curvature", c.Gaussian},
+		VertexAttribute{"k1", c.K1}, VertexAttribute{"k2", c.K2})
+	if err != nil {
+		h.error = fmt.Errorf("save curvature ply: %w", err)
+	}
+	return h
+}
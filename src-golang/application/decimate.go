@@ -0,0 +1,62 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+)
+
+// Decimate 用二次误差度量的边折叠简化当前三角形组成的网格，直到面数不超过targetFaces或下一次折叠的误差超过maxError(0表示不限)
+// 边界保持不变；seams中每条折线(相邻两点为网格的一条边)与边界一样保留，例如纹理接缝；report不为nil时写入简化统计
+func (h *Handler) Decimate(targetFaces int, maxError float64, report *math_lib.DecimationReport, seams ...[][]float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if targetFaces < 0 || maxError < 0 || (targetFaces == 0 && maxError == 0) {
+		h.error = fmt.Errorf("decimate: invalid target faces %d or max error %v", targetFaces, maxError)
+		return h
+	}
+
+	mesh := math_lib.NewMesh(h.Triangles)
+	edges, err := polylineEdges(mesh, seams)
+	if err != nil {
+		h.error = fmt.Errorf("decimate: %w", err)
+		return h
+	}
+
+	r := mesh.Decimate(math_lib.DecimationOptions{TargetFaces: targetFaces, MaxError: maxError, Seams: edges})
+	if report != nil {
+		*report = r
+	}
+	h.Triangles = mesh.Triangles()
+	return h
+}
+
+// polylineEdges 将以坐标给出的折线转换为网格的边(顶点下标对)，折线上的点必须是网格的顶点
+func polylineEdges(mesh *math_lib.Mesh, polylines [][][]float64) ([][2]int, error) {
+	if len(polylines) == 0 {
+		return nil, nil
+	}
+	index := make(map[[3]float64]int, len(mesh.Vertices))
+	for i, v := range mesh.Vertices {
+		index[[3]float64{v.AtVec(0), v.AtVec(1), v.AtVec(2)}] = i
+	}
+
+	var edges [][2]int
+	for _, line := range polylines {
+		prev := -1
+		for _, p := range line {
+			if len(p) != 3 {
+				return nil, fmt.Errorf("polyline point must have 3 coordinates, got %v", p)
+			}
+			id, ok := index[[3]float64{p[0], p[1], p[2]}]
+			if !ok {
+				return nil, fmt.Errorf("polyline point %v is not a mesh vertex", p)
+			}
+			if prev >= 0 {
+				edges = append(edges, [2]int{prev, id})
+			}
+			prev = id
+		}
+	}
+	return edges, nil
+}
@@ -0,0 +1,116 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// GeodesicDistance 用热方法计算当前三角形组成的网格(坐标相同的顶点视为同一顶点)每个顶点到sources的测地距离写入distance，并将该网格写入mesh，两者均可为nil
+// sources中的点取网格上最近的顶点；与sources不连通的顶点距离为+Inf
+func (h *Handler) GeodesicDistance(sources [][]float64, distance *[]float64, mesh *math_lib.Mesh) *Handler {
+	if h.error != nil {
+		return h
+	}
+
+	m := math_lib.NewMesh(h.Triangles)
+	ids := make([]int, len(sources))
+	for i, p := range sources {
+		id, err := nearestVertex(m, p)
+		if err != nil {
+			h.error = fmt.Errorf("geodesic distance: %w", err)
+			return h
+		}
+		ids[i] = id
+	}
+	d, err := m.GeodesicDistance(ids)
+	if err != nil {
+		h.error = err
+		return h
+	}
+	if distance != nil {
+		*distance = d
+	}
+	if mesh != nil {
+		*mesh = *m
+	}
+	return h
+}
+
+// GeodesicPath 计算当前网格上从from到to(均取网格上最近的顶点)的测地线，将折线上的点写入path，折线长度写入length，两者均可为nil
+func (h *Handler) GeodesicPath(from, to []float64, path *[][]float64, length *float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+
+	mesh := math_lib.NewMesh(h.Triangles)
+	source, err := nearestVertex(mesh, from)
+	if err != nil {
+		h.error = fmt.Errorf("geodesic path: %w", err)
+		return h
+	}
+	target, err := nearestVertex(mesh, to)
+	if err != nil {
+		h.error = fmt.Errorf("geodesic path: %w", err)
+		return h
+	}
+	points, err := mesh.GeodesicPath(source, target)
+	if err != nil {
+		h.error = err
+		return h
+	}
+
+	polyline := make([][]float64, len(points))
+	total := 0.0
+	for i, p := range points {
+		polyline[i] = []float64{p.AtVec(0), p.AtVec(1), p.AtVec(2)}
+		if i > 0 {
+			total += mat.Norm(math_lib.SubVec(mat.NewVecDense(3, nil), p, points[i-1]), 2)
+		}
+	}
+	if path != nil {
+		*path = polyline
+	}
+	if length != nil {
+		*length = total
+	}
+	return h
+}
+
+// SaveGeodesicPLY 计算当前网格每个顶点到sources的测地距离并保存为PLY文件，顶点属性为geodesic_distance，不连通的顶点记为-1
+func (h *Handler) SaveGeodesicPLY(sources [][]float64, filename string) *Handler {
+	var mesh math_lib.Mesh
+	var distance []float64
+	if h.GeodesicDistance(sources, &distance, &mesh); h.error != nil {
+		return h
+	}
+
+	for i, d := range distance {
+		if math.IsInf(d, 1) {
+			distance[i] = -1
+		}
+	}
+	if err := SavePLY(&mesh, filename, VertexAttribute{"geodesic_distance", distance}); err != nil {
+		h.error = fmt.Errorf("save geodesic ply: %w", err)
+	}
+	return h
+}
+
+// nearestVertex 返回网格上离点p最近的顶点的下标
+func nearestVertex(mesh *math_lib.Mesh, p []float64) (int, error) {
+	if len(p) != 3 {
+		return 0, fmt.Errorf("point must have 3 coordinates, got %v", p)
+	}
+	if len(mesh.Vertices) == 0 {
+		return 0, fmt.Errorf("empty mesh")
+	}
+	best, bestDist := 0, math.Inf(1)
+	for i, v := range mesh.Vertices {
+		dx, dy, dz := v.AtVec(0)-p[0], v.AtVec(1)-p[1], v.AtVec(2)-p[2]
+		if d := dx*dx + dy*dy + dz*dz; d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,113 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// SaveGLB 将网格保存为二进制glTF 2.0 (GLB)文件，atlas不为nil时接缝处的顶点按纹理坐标拆分并写入TEXCOORD_0
+func SaveGLB(mesh *math_lib.Mesh, filename string, atlas *math_lib.UVAtlas) error {
+	if atlas != nil && len(atlas.FaceUVs) != len(mesh.Faces) {
+		return fmt.Errorf("save glb: atlas has %d faces for %d mesh faces", len(atlas.FaceUVs), len(mesh.Faces))
+	}
+
+	// 有纹理坐标时每个纹理坐标对应一个glTF顶点
+	vertex := make([]int, len(mesh.Vertices))
+	indices := make([]uint32, 0, 3*len(mesh.Faces))
+	for i := range vertex {
+		vertex[i] = i
+	}
+	if atlas != nil {
+		vertex = make([]int, len(atlas.UVs))
+		for i, f := range mesh.Faces {
+			for k, t := range atlas.FaceUVs[i] {
+				vertex[t] = f[k]
+			}
+		}
+	}
+	for i, f := range mesh.Faces {
+		corners := f
+		if atlas != nil {
+			corners = atlas.FaceUVs[i]
+		}
+		for _, v := range corners {
+			indices = append(indices, uint32(v))
+		}
+	}
+
+	var bin bytes.Buffer
+	lo := []float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	hi := []float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, v := range vertex {
+		for d := 0; d < 3; d++ {
+			x := float64(float32(mesh.Vertices[v].AtVec(d)))
+			lo[d], hi[d] = math.Min(lo[d], x), math.Max(hi[d], x)
+			binary.Write(&bin, binary.LittleEndian, float32(x))
+		}
+	}
+	positionLength := bin.Len()
+	binary.Write(&bin, binary.LittleEndian, indices)
+	indexLength := bin.Len() - positionLength
+
+	accessors := []map[string]interface{}{
+		{"bufferView": 0, "componentType": 5126, "count": len(vertex), "type": "VEC3", "min": lo, "max": hi},
+		{"bufferView": 1, "componentType": 5125, "count": len(indices), "type": "SCALAR"},
+	}
+	views := []map[string]interface{}{
+		{"buffer": 0, "byteOffset": 0, "byteLength": positionLength, "target": 34962},
+		{"buffer": 0, "byteOffset": positionLength, "byteLength": indexLength, "target": 34963},
+	}
+	attributes := map[string]int{"POSITION": 0}
+	if atlas != nil {
+		// glTF的纹理坐标原点位于左上角
+		offset := bin.Len()
+		for _, uv := range atlas.UVs {
+			binary.Write(&bin, binary.LittleEndian, [2]float32{float32(uv.AtVec(0)), float32(1 - uv.AtVec(1))})
+		}
+		accessors = append(accessors, map[string]interface{}{"bufferView": 2, "componentType": 5126, "count": len(atlas.UVs), "type": "VEC2"})
+		views = append(views, map[string]interface{}{"buffer": 0, "byteOffset": offset, "byteLength": bin.Len() - offset, "target": 34962})
+		attributes["TEXCOORD_0"] = 2
+	}
+
+	doc, err := json.Marshal(map[string]interface{}{
+		"asset":       map[string]string{"version": "2.0", "generator": "Gonum glTF Export"},
+		"scene":       0,
+		"scenes":      []map[string]interface{}{{"nodes": []int{0}}},
+		"nodes":       []map[string]interface{}{{"mesh": 0}},
+		"meshes":      []map[string]interface{}{{"primitives": []map[string]interface{}{{"attributes": attributes, "indices": 1}}}},
+		"buffers":     []map[string]interface{}{{"byteLength": bin.Len()}},
+		"bufferViews": views,
+		"accessors":   accessors,
+	})
+	if err != nil {
+		return err
+	}
+
+	// JSON块用空格、二进制块用零补齐到4字节
+	for len(doc)%4 != 0 {
+		doc = append(doc, ' ')
+	}
+	for bin.Len()%4 != 0 {
+		bin.WriteByte(0)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	binary.Write(w, binary.LittleEndian, [3]uint32{0x46546C67, 2, uint32(12 + 8 + len(doc) + 8 + bin.Len())})
+	binary.Write(w, binary.LittleEndian, [2]uint32{uint32(len(doc)), 0x4E4F534A})
+	w.Write(doc)
+	binary.Write(w, binary.LittleEndian, [2]uint32{uint32(bin.Len()), 0x004E4942})
+	w.Write(bin.Bytes())
+	return w.Flush()
+}
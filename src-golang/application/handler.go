@@ -6,3 +6,8 @@ type Handler struct {
 	error
 	Triangles []*math_lib.Triangle
 }
+
+// Err 返回处理链中遇到的第一个错误
+func (h *Handler) Err() error {
+	return h.error
+}
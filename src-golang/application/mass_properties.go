@@ -0,0 +1,34 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+)
+
+// MassProperties 计算当前三角形组成的封闭网格在均匀密度density下的质量属性并写入props(可为nil)，网格不封闭时返回错误
+func (h *Handler) MassProperties(density float64, props *math_lib.MassProperties) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if boundary := math_lib.NewMesh(h.Triangles).BoundaryEdges(); len(boundary) > 0 {
+		h.error = fmt.Errorf("mass properties: mesh is not closed (%d boundary edges)", len(boundary))
+		return h
+	}
+
+	if props != nil {
+		*props = math_lib.ComputeMassProperties(h.Triangles, density)
+	}
+	return h
+}
+
+// OrientedBoundingBox 计算当前三角形的有向包围盒并写入box(可为nil)
+func (h *Handler) OrientedBoundingBox(box *math_lib.OrientedBox) *Handler {
+	if h.error != nil {
+		return h
+	}
+
+	if box != nil {
+		*box = math_lib.OrientedBoundingBox(h.Triangles)
+	}
+	return h
+}
@@ -0,0 +1,42 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"bufio"
+	"fmt"
+	"os"
+)
+
+// SaveOBJ 将网格保存为Wavefront OBJ文件，atlas不为nil时同时写入纹理坐标(vt)
+func SaveOBJ(mesh *math_lib.Mesh, filename string, atlas *math_lib.UVAtlas) error {
+	if atlas != nil && len(atlas.FaceUVs) != len(mesh.Faces) {
+		return fmt.Errorf("save obj: atlas has %d faces for %d mesh faces", len(atlas.FaceUVs), len(mesh.Faces))
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	fmt.Fprintln(w, "# Gonum OBJ Export")
+	for _, p := range mesh.Vertices {
+		fmt.Fprintf(w, "v %g %g %g\n", p.AtVec(0), p.AtVec(1), p.AtVec(2))
+	}
+	if atlas == nil {
+		for _, f := range mesh.Faces {
+			fmt.Fprintf(w, "f %d %d %d\n", f[0]+1, f[1]+1, f[2]+1)
+		}
+		return w.Flush()
+	}
+
+	for _, uv := range atlas.UVs {
+		fmt.Fprintf(w, "vt %g %g\n", uv.AtVec(0), uv.AtVec(1))
+	}
+	for i, f := range mesh.Faces {
+		t := atlas.FaceUVs[i]
+		fmt.Fprintf(w, "f %d/%d %d/%d %d/%d\n", f[0]+1, t[0]+1, f[1]+1, t[1]+1, f[2]+1, t[2]+1)
+	}
+	return w.Flush()
+}
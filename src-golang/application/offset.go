@@ -0,0 +1,46 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+)
+
+// Offset 将当前三角形组成的网格(距离不超过包围盒对角线1e-9倍的顶点视为同一顶点)替换为其偏移曲面，resolution为包围盒最长边上的体素数
+// 封闭网格distance为正时向外、为负时向内偏移；开放网格(如零厚度的花瓣)替换为包围它、到它距离为|distance|的封闭曲面
+func (h *Handler) Offset(distance float64, resolution int) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if distance == 0 || resolution < 1 {
+		h.error = fmt.Errorf("offset: invalid distance %v or resolution %d", distance, resolution)
+		return h
+	}
+
+	res := math_lib.NewMesh(h.Triangles).Offset(distance, resolution)
+	if len(res) == 0 {
+		h.error = fmt.Errorf("offset: offset surface at distance %v is empty", distance)
+		return h
+	}
+	h.Triangles = res
+	return h
+}
+
+// Shell 将当前网格(顶点的合并方式与Offset相同)替换为可打印的实体壁：封闭网格与其偏移曲面(distance为负时向内、为正时向外)组成厚度|distance|的空心实体，
+// 开放网格替换为以其为中面、厚度为|distance|的封闭实体
+func (h *Handler) Shell(distance float64, resolution int) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if distance == 0 || resolution < 1 {
+		h.error = fmt.Errorf("shell: invalid distance %v or resolution %d", distance, resolution)
+		return h
+	}
+
+	res := math_lib.NewMesh(h.Triangles).Shell(distance, resolution)
+	if len(res) == 0 {
+		h.error = fmt.Errorf("shell: offset surface at distance %v is empty", distance)
+		return h
+	}
+	h.Triangles = res
+	return h
+}
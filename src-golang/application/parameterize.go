@@ -0,0 +1,65 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+)
+
+// Parameterize 计算当前三角形组成的网格(几乎重合的顶点视为同一顶点)的纹理坐标写入atlas，并将该网格写入mesh，两者均可为nil
+// seams中每条折线(相邻两点为网格的一条边)作为额外的接缝切开，与options.Seams合并
+func (h *Handler) Parameterize(options math_lib.ParameterizationOptions, atlas *math_lib.UVAtlas, mesh *math_lib.Mesh, seams ...[][]float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if options.MaxChartAngle < 0 || options.Margin < 0 || options.Margin >= 0.5 {
+		h.error = fmt.Errorf("parameterize: invalid max chart angle %v or margin %v", options.MaxChartAngle, options.Margin)
+		return h
+	}
+
+	m := math_lib.NewMesh(h.Triangles)
+	edges, err := polylineEdges(m, seams)
+	if err != nil {
+		h.error = fmt.Errorf("parameterize: %w", err)
+		return h
+	}
+	options.Seams = append(append([][2]int(nil), options.Seams...), edges...)
+
+	a, err := m.Parameterize(options)
+	if err != nil {
+		h.error = err
+		return h
+	}
+	if atlas != nil {
+		*atlas = *a
+	}
+	if mesh != nil {
+		*mesh = *m
+	}
+	return h
+}
+
+// SaveTexturedOBJ 计算当前网格的纹理坐标并保存为带vt的OBJ文件
+func (h *Handler) SaveTexturedOBJ(filename string, options math_lib.ParameterizationOptions, seams ...[][]float64) *Handler {
+	var mesh math_lib.Mesh
+	var atlas math_lib.UVAtlas
+	if h.Parameterize(options, &atlas, &mesh, seams...); h.error != nil {
+		return h
+	}
+	if err := SaveOBJ(&mesh, filename, &atlas); err != nil {
+		h.error = fmt.Errorf("save textured obj: %w", err)
+	}
+	return h
+}
+
+// SaveTexturedGLB 计算当前网格的纹理坐标并保存为带TEXCOORD_0的GLB文件
+func (h *Handler) SaveTexturedGLB(filename string, options math_lib.ParameterizationOptions, seams ...[][]float64) *Handler {
+	var mesh math_lib.Mesh
+	var atlas math_lib.UVAtlas
+	if h.Parameterize(options, &atlas, &mesh, seams...); h.error != nil {
+		return h
+	}
+	if err := SaveGLB(&mesh, filename, &atlas); err != nil {
+		h.error = fmt.Errorf("save textured glb: %w", err)
+	}
+	return h
+}
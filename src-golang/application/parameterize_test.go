@@ -0,0 +1,103 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// testQuad 返回由两个三角形组成的单位正方形及其纹理坐标
+func testQuad() (*math_lib.Mesh, *math_lib.UVAtlas) {
+	mesh := &math_lib.Mesh{Faces: [][3]int{{0, 1, 2}, {0, 2, 3}}}
+	atlas := &math_lib.UVAtlas{FaceUVs: mesh.Faces, Charts: [][]int{{0, 1}}}
+	for _, p := range [][2]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}} {
+		mesh.Vertices = append(mesh.Vertices, mat.NewVecDense(3, []float64{p[0], p[1], 0}))
+		atlas.UVs = append(atlas.UVs, mat.NewVecDense(2, []float64{p[0], p[1]}))
+	}
+	return mesh, atlas
+}
+
+func TestSaveOBJ(t *testing.T) {
+	mesh, atlas := testQuad()
+	filename := filepath.Join(t.TempDir(), "quad.obj")
+	if err := SaveOBJ(mesh, filename, atlas); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if strings.Count(text, "\nv ") != 4 || strings.Count(text, "\nvt ") != 4 || !strings.Contains(text, "f 1/1 3/3 4/4\n") {
+		t.Errorf("unexpected obj:\n%s", text)
+	}
+
+	atlas.FaceUVs = atlas.FaceUVs[:1]
+	if err := SaveOBJ(mesh, filename, atlas); err == nil {
+		t.Error("expected an error for an atlas with too few faces")
+	}
+}
+
+func TestSaveGLB(t *testing.T) {
+	mesh, atlas := testQuad()
+	filename := filepath.Join(t.TempDir(), "quad.glb")
+	if err := SaveGLB(mesh, filename, atlas); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binary.LittleEndian.Uint32(data[0:]) != 0x46546C67 || int(binary.LittleEndian.Uint32(data[8:])) != len(data) {
+		t.Fatalf("bad glb header")
+	}
+	length := binary.LittleEndian.Uint32(data[12:])
+	var doc struct {
+		Accessors []struct {
+			Count int    `json:"count"`
+			Type  string `json:"type"`
+		} `json:"accessors"`
+	}
+	if err := json.Unmarshal(data[20:20+length], &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Accessors) != 3 || doc.Accessors[0].Count != 4 || doc.Accessors[1].Count != 6 || doc.Accessors[2].Type != "VEC2" {
+		t.Errorf("accessors %+v", doc.Accessors)
+	}
+}
+
+func TestParameterizeMarchingCubes(t *testing.T) {
+	sphere := func(p *mat.VecDense) float64 { return mat.Norm(p, 2) - 1 }
+	h := NewHandler()
+	h.Triangles = math_lib.MarchingCubes(sphere, []float64{-1.3, -1.3, -1.3}, []float64{1.3, 1.3, 1.3}, []int{16, 16, 16})
+
+	var atlas math_lib.UVAtlas
+	var mesh math_lib.Mesh
+	if err := h.Parameterize(math_lib.ParameterizationOptions{Margin: 0.01}, &atlas, &mesh).Err(); err != nil {
+		t.Fatal(err)
+	}
+	// 相邻立方体之间的舍入裂缝被焊接后，球面只需切成两个圆盘
+	if len(atlas.Charts) != 2 {
+		t.Errorf("got %d charts, want 2", len(atlas.Charts))
+	}
+	for i, uv := range atlas.UVs {
+		if uv.AtVec(0) < 0 || uv.AtVec(0) > 1 || uv.AtVec(1) < 0 || uv.AtVec(1) > 1 {
+			t.Fatalf("uv %d = %v is outside the unit square", i, uv.RawVector().Data)
+		}
+	}
+	for i, f := range atlas.FaceUVs {
+		a, b, c := atlas.UVs[f[0]], atlas.UVs[f[1]], atlas.UVs[f[2]]
+		if (b.AtVec(0)-a.AtVec(0))*(c.AtVec(1)-a.AtVec(1))-(b.AtVec(1)-a.AtVec(1))*(c.AtVec(0)-a.AtVec(0)) <= 0 {
+			t.Fatalf("face %d is flipped in uv space", i)
+		}
+	}
+	if len(atlas.FaceUVs) != len(mesh.Faces) {
+		t.Errorf("atlas has %d faces, mesh has %d", len(atlas.FaceUVs), len(mesh.Faces))
+	}
+}
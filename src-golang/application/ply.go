@@ -0,0 +1,65 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+// VertexAttribute 随顶点保存到PLY文件中的一个标量属性，Values的长度必须等于网格的顶点数
+type VertexAttribute struct {
+	Name   string
+	Values []float64
+}
+
+// SavePLY 将网格及顶点属性保存为binary_little_endian格式的PLY文件，坐标与属性均为float类型
+func SavePLY(mesh *math_lib.Mesh, filename string, attributes ...VertexAttribute) error {
+	for _, a := range attributes {
+		if a.Name == "" || strings.ContainsAny(a.Name, " \t\r\n") {
+			return fmt.Errorf("save ply: invalid attribute name %q", a.Name)
+		}
+		if len(a.Values) != len(mesh.Vertices) {
+			return fmt.Errorf("save ply: attribute %s has %d values for %d vertices", a.Name, len(a.Values), len(mesh.Vertices))
+		}
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	fmt.Fprintf(w, "ply\nformat binary_little_endian 1.0\ncomment Gonum PLY Export\nelement vertex %d\n", len(mesh.Vertices))
+	fmt.Fprint(w, "property float x\nproperty float y\nproperty float z\n")
+	for _, a := range attributes {
+		fmt.Fprintf(w, "property float %s\n", a.Name)
+	}
+	fmt.Fprintf(w, "element face %d\nproperty list uchar int vertex_indices\nend_header\n", len(mesh.Faces))
+
+	buf := make([]byte, 4)
+	writeFloat := func(v float64) {
+		binary.LittleEndian.PutUint32(buf, math.Float32bits(float32(v)))
+		w.Write(buf)
+	}
+	for i, p := range mesh.Vertices {
+		for d := 0; d < 3; d++ {
+			writeFloat(p.AtVec(d))
+		}
+		for _, a := range attributes {
+			writeFloat(a.Values[i])
+		}
+	}
+	for _, f := range mesh.Faces {
+		w.WriteByte(3)
+		for _, v := range f {
+			binary.LittleEndian.PutUint32(buf, uint32(v))
+			w.Write(buf)
+		}
+	}
+	return w.Flush()
+}
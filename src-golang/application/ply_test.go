@@ -0,0 +1,40 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"path/filepath"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSavePLY(t *testing.T) {
+	mesh := &math_lib.Mesh{
+		Vertices: []*mat.VecDense{
+			mat.NewVecDense(3, []float64{0, 0, 0}),
+			mat.NewVecDense(3, []float64{1, 0, 0}),
+			mat.NewVecDense(3, []float64{0, 2, 0.5}),
+		},
+		Faces: [][3]int{{0, 1, 2}},
+	}
+	filename := filepath.Join(t.TempDir(), "mesh.ply")
+	if err := SavePLY(mesh, filename, VertexAttribute{"curvature", []float64{1, 2, 3}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 面元素位于顶点元素之后，LoadPLY只读取顶点坐标
+	points, _, err := LoadPLY(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 3 || !mat.Equal(points[2], mesh.Vertices[2]) {
+		t.Fatalf("read back %d points", len(points))
+	}
+
+	if err := SavePLY(mesh, filename, VertexAttribute{"bad name", []float64{1, 2, 3}}); err == nil {
+		t.Error("expected an error for an attribute name with spaces")
+	}
+	if err := SavePLY(mesh, filename, VertexAttribute{"short", []float64{1}}); err == nil {
+		t.Error("expected an error for a short attribute")
+	}
+}
@@ -0,0 +1,345 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ReconstructSurface 从点云重建三角网格并加入当前三角形，normals为nil时自动估计法向量
+func (h *Handler) ReconstructSurface(points, normals []*mat.VecDense, resolution int) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if len(points) < 3 || resolution < 1 {
+		h.error = fmt.Errorf("reconstruct surface: invalid point count %d or resolution %d", len(points), resolution)
+		return h
+	}
+	if normals != nil && len(normals) != len(points) {
+		h.error = fmt.Errorf("reconstruct surface: %d points but %d normals", len(points), len(normals))
+		return h
+	}
+
+	h.Triangles = append(h.Triangles, math_lib.ReconstructSurface(points, normals, resolution)...)
+	return h
+}
+
+// LoadXYZ 读取XYZ点云文件，每行为"x y z"或"x y z nx ny nz"，#开头的行为注释
+// 所有行都带法向量时返回法向量，否则normals为nil
+func LoadXYZ(filename string) (points, normals []*mat.VecDense, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	withNormals := true
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(strings.ReplaceAll(scanner.Text(), ",", " "))
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) < 3 {
+			return nil, nil, fmt.Errorf("%s:%d: expected at least 3 values, got %d", filename, line, len(fields))
+		}
+
+		values, err := parseFloats(fields[:min(len(fields), 6)])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %v", filename, line, err)
+		}
+		points = append(points, mat.NewVecDense(3, values[:3]))
+		if len(values) == 6 {
+			normals = append(normals, mat.NewVecDense(3, values[3:]))
+		} else {
+			withNormals = false
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if !withNormals {
+		normals = nil
+	}
+	return points, normals, nil
+}
+
+// LoadPLY 读取PLY文件中的顶点坐标及法向量(nx/ny/nz属性存在时)，支持ascii与binary_little_endian格式，顶点元素之前的其他元素被跳过
+func LoadPLY(filename string) (points, normals []*mat.VecDense, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	header, err := readPLYHeader(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	column := func(name string) int {
+		for i, p := range header.properties {
+			if p.name == name {
+				return i
+			}
+		}
+		return -1
+	}
+	xyz := [3]int{column("x"), column("y"), column("z")}
+	nxyz := [3]int{column("nx"), column("ny"), column("nz")}
+	if xyz[0] < 0 || xyz[1] < 0 || xyz[2] < 0 {
+		return nil, nil, fmt.Errorf("%s: vertex element has no x/y/z properties", filename)
+	}
+	withNormals := nxyz[0] >= 0 && nxyz[1] >= 0 && nxyz[2] >= 0
+
+	for _, element := range header.skipped {
+		for i := 0; i < element.count; i++ {
+			if err := skipPLYItem(reader, header, element); err != nil {
+				return nil, nil, fmt.Errorf("%s: %s %d: %v", filename, element.name, i, err)
+			}
+		}
+	}
+	for i := 0; i < header.vertices; i++ {
+		var values []float64
+		if header.binary {
+			values, err = readPLYBinaryVertex(reader, header.properties)
+		} else {
+			values, err = readPLYASCIIVertex(reader, len(header.properties))
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: vertex %d: %v", filename, i, err)
+		}
+
+		points = append(points, mat.NewVecDense(3, []float64{values[xyz[0]], values[xyz[1]], values[xyz[2]]}))
+		if withNormals {
+			normals = append(normals, mat.NewVecDense(3, []float64{values[nxyz[0]], values[nxyz[1]], values[nxyz[2]]}))
+		}
+	}
+	return points, normals, nil
+}
+
+// plyProperty PLY元素的一个属性，list不为空时为列表属性，list为长度的类型、kind为元素的类型
+type plyProperty struct {
+	name, kind, list string
+}
+
+// plyElement 位于顶点元素之前、读取顶点前需要跳过的元素
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+// plyHeader PLY文件头中与顶点相关的信息
+type plyHeader struct {
+	binary     bool
+	vertices   int
+	properties []plyProperty
+	skipped    []*plyElement
+}
+
+// readPLYHeader 解析PLY文件头直到end_header
+func readPLYHeader(r *bufio.Reader) (*plyHeader, error) {
+	header := &plyHeader{}
+	var skipping *plyElement
+	inVertex, seenVertex := false, false
+	for first := true; ; first = false {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("unexpected end of header: %v", err)
+		}
+		fields := strings.Fields(line)
+		if first {
+			if len(fields) != 1 || fields[0] != "ply" {
+				return nil, fmt.Errorf("not a PLY file")
+			}
+			continue
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "format":
+			if len(fields) < 2 || (fields[1] != "ascii" && fields[1] != "binary_little_endian") {
+				return nil, fmt.Errorf("unsupported format %q", strings.TrimSpace(line))
+			}
+			header.binary = fields[1] == "binary_little_endian"
+		case "element":
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("invalid element line %q", strings.TrimSpace(line))
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil || count < 0 {
+				return nil, fmt.Errorf("invalid %s count %q", fields[1], fields[2])
+			}
+
+			// 顶点元素之后的元素不影响顶点的读取，直接忽略
+			inVertex, skipping = false, nil
+			switch {
+			case seenVertex:
+			case fields[1] == "vertex":
+				inVertex, seenVertex = true, true
+				header.vertices = count
+			default:
+				skipping = &plyElement{name: fields[1], count: count}
+				header.skipped = append(header.skipped, skipping)
+			}
+		case "property":
+			property, err := parsePLYProperty(fields)
+			if err != nil {
+				return nil, err
+			}
+			if skipping != nil {
+				skipping.properties = append(skipping.properties, property)
+			} else if inVertex {
+				if property.list != "" {
+					return nil, fmt.Errorf("unsupported vertex property %q", strings.TrimSpace(line))
+				}
+				header.properties = append(header.properties, property)
+			}
+		case "end_header":
+			if !seenVertex {
+				return nil, fmt.Errorf("no vertex element")
+			}
+			return header, nil
+		}
+	}
+}
+
+// parsePLYProperty 解析"property <type> <name>"或"property list <count type> <type> <name>"
+func parsePLYProperty(fields []string) (plyProperty, error) {
+	var property plyProperty
+	switch {
+	case len(fields) == 3:
+		property = plyProperty{name: fields[2], kind: fields[1]}
+	case len(fields) == 5 && fields[1] == "list":
+		property = plyProperty{name: fields[4], kind: fields[3], list: fields[2]}
+		if plyTypeSize(property.list) == 0 {
+			return property, fmt.Errorf("unknown property type %q", property.list)
+		}
+	default:
+		return property, fmt.Errorf("invalid property line %q", strings.Join(fields, " "))
+	}
+	if plyTypeSize(property.kind) == 0 {
+		return property, fmt.Errorf("unknown property type %q", property.kind)
+	}
+	return property, nil
+}
+
+// plyTypeSize 返回PLY标量类型的字节数，未知类型返回0
+func plyTypeSize(kind string) int {
+	switch kind {
+	case "char", "uchar", "int8", "uint8":
+		return 1
+	case "short", "ushort", "int16", "uint16":
+		return 2
+	case "int", "uint", "float", "int32", "uint32", "float32":
+		return 4
+	case "double", "float64":
+		return 8
+	}
+	return 0
+}
+
+// readPLYASCIIVertex 读取一行ASCII顶点数据
+func readPLYASCIIVertex(r *bufio.Reader, count int) ([]float64, error) {
+	line, err := r.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return nil, err
+	}
+	fields := strings.Fields(line)
+	if len(fields) < count {
+		return nil, fmt.Errorf("expected %d values, got %d", count, len(fields))
+	}
+	return parseFloats(fields[:count])
+}
+
+// readPLYBinaryVertex 按属性类型读取一个小端序二进制顶点
+func readPLYBinaryVertex(r io.Reader, properties []plyProperty) ([]float64, error) {
+	values := make([]float64, len(properties))
+	for i, p := range properties {
+		v, err := readPLYBinaryScalar(r, p.kind)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// readPLYBinaryScalar 读取一个小端序二进制标量
+func readPLYBinaryScalar(r io.Reader, kind string) (float64, error) {
+	b := make([]byte, plyTypeSize(kind))
+	if _, err := io.ReadFull(r, b); err != nil {
+		return 0, err
+	}
+	switch kind {
+	case "char", "int8":
+		return float64(int8(b[0])), nil
+	case "uchar", "uint8":
+		return float64(b[0]), nil
+	case "short", "int16":
+		return float64(int16(binary.LittleEndian.Uint16(b))), nil
+	case "ushort", "uint16":
+		return float64(binary.LittleEndian.Uint16(b)), nil
+	case "int", "int32":
+		return float64(int32(binary.LittleEndian.Uint32(b))), nil
+	case "uint", "uint32":
+		return float64(binary.LittleEndian.Uint32(b)), nil
+	case "float", "float32":
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+}
+
+// skipPLYItem 跳过元素的一项数据：ASCII格式每项占一行，二进制格式按属性类型(列表属性先读长度)计算字节数
+func skipPLYItem(r *bufio.Reader, header *plyHeader, element *plyElement) error {
+	if !header.binary {
+		line, err := r.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return err
+		}
+		return nil
+	}
+
+	for _, p := range element.properties {
+		count := 1
+		if p.list != "" {
+			n, err := readPLYBinaryScalar(r, p.list)
+			if err != nil {
+				return err
+			}
+			if n < 0 {
+				return fmt.Errorf("negative list length %g", n)
+			}
+			count = int(n)
+		}
+		if _, err := r.Discard(count * plyTypeSize(p.kind)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseFloats 将字符串切片解析为浮点数
+func parseFloats(fields []string) ([]float64, error) {
+	values := make([]float64, len(fields))
+	for i, s := range fields {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", s)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
@@ -0,0 +1,70 @@
+package application
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestFile 在临时目录中写入文件并返回其路径
+func writeTestFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoadXYZ(t *testing.T) {
+	filename := writeTestFile(t, "cloud.xyz", []byte("# comment\n1 2 3 0 0 1\n4,5,6,0,1,0\n\n7 8 9 1 0 0\n"))
+	points, normals, err := LoadXYZ(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 3 || len(normals) != 3 {
+		t.Fatalf("got %d points and %d normals, want 3 and 3", len(points), len(normals))
+	}
+	if points[1].AtVec(2) != 6 || normals[1].AtVec(1) != 1 {
+		t.Errorf("second point %v with normal %v", points[1].RawVector().Data, normals[1].RawVector().Data)
+	}
+
+	// 有一行不带法向量时不返回法向量
+	filename = writeTestFile(t, "partial.xyz", []byte("1 2 3 0 0 1\n4 5 6\n"))
+	if points, normals, err = LoadXYZ(filename); err != nil || len(points) != 2 || normals != nil {
+		t.Errorf("got %d points, normals %v, error %v", len(points), normals, err)
+	}
+}
+
+func TestLoadPLY(t *testing.T) {
+	ascii := "ply\nformat ascii 1.0\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\n" +
+		"property float nx\nproperty float ny\nproperty float nz\nelement face 0\nproperty list uchar int vertex_indices\nend_header\n" +
+		"1 2 3 0 0 1\n4 5 6 1 0 0\n"
+	points, normals, err := LoadPLY(writeTestFile(t, "ascii.ply", []byte(ascii)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 || len(normals) != 2 || points[1].AtVec(0) != 4 || normals[1].AtVec(0) != 1 {
+		t.Fatalf("got points %v and normals %v", points, normals)
+	}
+
+	data := []byte("ply\nformat binary_little_endian 1.0\nelement vertex 2\nproperty double x\nproperty float y\nproperty short z\nend_header\n")
+	for _, v := range [][3]float64{{1.5, 2.5, -3}, {4, 5, 6}} {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v[0]))
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(float32(v[1])))
+		data = binary.LittleEndian.AppendUint16(data, uint16(int16(v[2])))
+	}
+	points, normals, err = LoadPLY(writeTestFile(t, "binary.ply", data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 || normals != nil || points[0].AtVec(1) != 2.5 || points[0].AtVec(2) != -3 {
+		t.Errorf("got points %v and normals %v", points, normals)
+	}
+
+	if _, _, err := LoadPLY(writeTestFile(t, "bad.ply", []byte("obj\n"))); err == nil {
+		t.Error("not a PLY file: expected an error")
+	}
+}
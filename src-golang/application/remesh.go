@@ -0,0 +1,22 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+)
+
+// Remesh 将当前三角形组成的网格(几乎重合的顶点视为同一顶点)重新网格化为边长接近targetLength的均匀三角形，iterations常取5~10
+func (h *Handler) Remesh(targetLength float64, iterations int) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if targetLength <= 0 || iterations < 0 {
+		h.error = fmt.Errorf("remesh: invalid target length %v or iterations %d", targetLength, iterations)
+		return h
+	}
+
+	mesh := math_lib.NewMesh(h.Triangles)
+	mesh.Remesh(targetLength, iterations)
+	h.Triangles = mesh.Triangles()
+	return h
+}
@@ -0,0 +1,26 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+)
+
+// Repair 修复当前三角形组成的网格: 合并距离不超过tolerance的顶点，删除退化面、重复面与非流形边上的面，
+// 统一方向并使法向量朝外，填充边数不超过maxHoleEdges的孔洞(0表示不限)；report不为nil时写入各步骤的修改数量
+func (h *Handler) Repair(tolerance float64, maxHoleEdges int, report *math_lib.RepairReport) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if tolerance < 0 || maxHoleEdges < 0 {
+		h.error = fmt.Errorf("repair: invalid tolerance %v or max hole edges %d", tolerance, maxHoleEdges)
+		return h
+	}
+
+	mesh := math_lib.NewMesh(h.Triangles)
+	r := mesh.Repair(tolerance, maxHoleEdges)
+	if report != nil {
+		*report = r
+	}
+	h.Triangles = mesh.Triangles()
+	return h
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+)
+
+// LaplacianSmooth 对当前三角形组成的网格(几乎重合的顶点视为同一顶点)进行拉普拉斯平滑
+// featureAngle大于0时锁定二面角超过featureAngle(弧度)的特征边与边界上的顶点
+func (h *Handler) LaplacianSmooth(weights math_lib.LaplacianWeights, lambda float64, iterations int, featureAngle float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if lambda <= 0 || lambda > 1 || iterations < 0 {
+		h.error = fmt.Errorf("laplacian smooth: invalid lambda %v or iterations %d", lambda, iterations)
+		return h
+	}
+
+	mesh := math_lib.NewMesh(h.Triangles)
+	mesh.LaplacianSmooth(weights, lambda, iterations, featureVertices(mesh, featureAngle))
+	h.Triangles = mesh.Triangles()
+	return h
+}
+
+// TaubinSmooth 对当前三角形组成的网格进行Taubin λ|μ平滑，要求 0 < lambda ≤ 1 且 mu < -lambda，常用 lambda = 0.5, mu = -0.53
+func (h *Handler) TaubinSmooth(weights math_lib.LaplacianWeights, lambda, mu float64, iterations int, featureAngle float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if lambda <= 0 || lambda > 1 || mu >= -lambda || iterations < 0 {
+		h.error = fmt.Errorf("taubin smooth: invalid lambda %v, mu %v or iterations %d", lambda, mu, iterations)
+		return h
+	}
+
+	mesh := math_lib.NewMesh(h.Triangles)
+	mesh.TaubinSmooth(weights, lambda, mu, iterations, featureVertices(mesh, featureAngle))
+	h.Triangles = mesh.Triangles()
+	return h
+}
+
+// BilateralSmooth 对当前三角形组成的网格进行双边法向量滤波，sigmaNormal为法向量差的高斯权重参数，常用0.3~0.5
+func (h *Handler) BilateralSmooth(normalIterations, vertexIterations int, sigmaNormal, featureAngle float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if normalIterations < 0 || vertexIterations < 0 || sigmaNormal <= 0 {
+		h.error = fmt.Errorf("bilateral smooth: invalid iterations %d, %d or sigma %v", normalIterations, vertexIterations, sigmaNormal)
+		return h
+	}
+
+	mesh := math_lib.NewMesh(h.Triangles)
+	mesh.BilateralSmooth(normalIterations, vertexIterations, sigmaNormal, featureVertices(mesh, featureAngle))
+	h.Triangles = mesh.Triangles()
+	return h
+}
+
+// featureVertices featureAngle大于0时返回特征边上的顶点，否则返回nil
+func featureVertices(mesh *math_lib.Mesh, featureAngle float64) []bool {
+	if featureAngle <= 0 {
+		return nil
+	}
+	return mesh.FeatureVertices(featureAngle)
+}
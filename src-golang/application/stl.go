@@ -2,10 +2,15 @@ package application
 
 import (
 	"Geometric_Construction/math_lib"
+	"bufio"
+	"bytes"
 	"encoding/binary"
+	"fmt"
 	"gonum.org/v1/gonum/mat"
 	"io"
+	"math"
 	"os"
+	"strings"
 )
 
 // SaveBinarySTL 将三角形切片保存为二进制STL文件
@@ -59,3 +64,75 @@ func writeVectorAsFloat32(w io.Writer, vec *mat.VecDense) error {
 	}
 	return nil
 }
+
+// LoadSTL 读取二进制或ASCII格式的STL文件，忽略文件中保存的法向量
+// 文件长度恰好等于 84 + 50×三角形数 时按二进制读取，否则按ASCII读取
+func LoadSTL(filename string) ([]*math_lib.Triangle, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) >= 84 {
+		count := int(binary.LittleEndian.Uint32(data[80:84]))
+		if len(data) == 84+50*count {
+			return parseBinarySTL(data[84:], count), nil
+		}
+	}
+	tris, err := parseASCIISTL(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return tris, nil
+}
+
+// parseBinarySTL 解析二进制STL的三角形记录
+func parseBinarySTL(data []byte, count int) []*math_lib.Triangle {
+	res := make([]*math_lib.Triangle, count)
+	for i := range res {
+		record := data[50*i : 50*i+50]
+		tri := &math_lib.Triangle{}
+		for j := range tri.P {
+			values := make([]float64, 3)
+			for k := range values {
+				offset := 12 + 12*j + 4*k
+				values[k] = float64(math.Float32frombits(binary.LittleEndian.Uint32(record[offset : offset+4])))
+			}
+			tri.P[j] = mat.NewVecDense(3, values)
+		}
+		res[i] = tri
+	}
+	return res
+}
+
+// parseASCIISTL 解析ASCII STL中的vertex行，每三个顶点组成一个三角形
+func parseASCIISTL(data []byte) ([]*math_lib.Triangle, error) {
+	var res []*math_lib.Triangle
+	var vertices []*mat.VecDense
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "vertex" {
+			continue
+		}
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: expected 3 coordinates, got %d", line, len(fields)-1)
+		}
+		values, err := parseFloats(fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		vertices = append(vertices, mat.NewVecDense(3, values))
+		if len(vertices) == 3 {
+			res = append(res, &math_lib.Triangle{P: [3]*mat.VecDense{vertices[0], vertices[1], vertices[2]}})
+			vertices = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(vertices) != 0 {
+		return nil, fmt.Errorf("incomplete facet with %d vertices", len(vertices))
+	}
+	return res, nil
+}
@@ -0,0 +1,35 @@
+package application
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestLoadSTL(t *testing.T) {
+	ascii := "solid s\n facet normal 0 0 1\n  outer loop\n   vertex 0 0 0\n   vertex 1 0 0\n   vertex 0 1 0\n  endloop\n endfacet\nendsolid s\n"
+	tris, err := LoadSTL(writeTestFile(t, "ascii.stl", []byte(ascii)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tris) != 1 || tris[0].P[1].AtVec(0) != 1 || tris[0].P[2].AtVec(1) != 1 {
+		t.Fatalf("got %d triangles", len(tris))
+	}
+
+	data := make([]byte, 84+50)
+	binary.LittleEndian.PutUint32(data[80:], 1)
+	for i, v := range []float32{0, 0, 1, 0, 0, 0, 2, 0, 0, 0, 3, 0} {
+		binary.LittleEndian.PutUint32(data[84+4*i:], math.Float32bits(v))
+	}
+	tris, err = LoadSTL(writeTestFile(t, "binary.stl", data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tris) != 1 || tris[0].P[1].AtVec(0) != 2 || tris[0].P[2].AtVec(1) != 3 {
+		t.Fatalf("got %d triangles", len(tris))
+	}
+
+	if _, err := LoadSTL(writeTestFile(t, "bad.stl", []byte("solid s\n vertex 0 0\nendsolid\n"))); err == nil {
+		t.Error("expected an error for a malformed file")
+	}
+}
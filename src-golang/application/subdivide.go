@@ -0,0 +1,28 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+)
+
+// Subdivide 对当前三角形组成的网格进行iterations次细分，creases中每条折线(相邻两点为网格的一条边)保持尖锐，边界同样保持尖锐
+func (h *Handler) Subdivide(scheme math_lib.SubdivisionScheme, iterations int, creases ...[][]float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if iterations < 0 {
+		h.error = fmt.Errorf("subdivide: invalid iterations %d", iterations)
+		return h
+	}
+
+	mesh := math_lib.NewMesh(h.Triangles)
+	edges, err := polylineEdges(mesh, creases)
+	if err != nil {
+		h.error = fmt.Errorf("subdivide: %w", err)
+		return h
+	}
+
+	mesh.Subdivide(scheme, iterations, edges)
+	h.Triangles = mesh.Triangles()
+	return h
+}
@@ -0,0 +1,35 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"fmt"
+)
+
+// ThickenParametricEquation 三角化参数曲面并沿其法向量 Fu×Fv 挤出厚度thickness(为负时反向)，边界处以侧壁封闭，生成的实体加入当前三角形
+func (h *Handler) ThickenParametricEquation(f func(u, v float64) (x, y, z float64), uRange, vRange []float64, divisions []int, thickness float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if thickness == 0 || len(divisions) != 2 || divisions[0] < 1 || divisions[1] < 1 {
+		h.error = fmt.Errorf("thicken parametric equation: invalid thickness %v or divisions %v", thickness, divisions)
+		return h
+	}
+
+	res := math_lib.ThickenParametricEquation(f, uRange, vRange, divisions, thickness)
+	h.Triangles = append(h.Triangles, res...)
+	return h
+}
+
+// Thicken 将当前三角形组成的开放曲面(几乎重合的顶点视为同一顶点)沿顶点法向量挤出厚度thickness(为负时反向)，边界处以侧壁封闭
+func (h *Handler) Thicken(thickness float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+	if thickness == 0 {
+		h.error = fmt.Errorf("thicken: invalid thickness %v", thickness)
+		return h
+	}
+
+	h.Triangles = math_lib.NewMesh(h.Triangles).Thicken(thickness, nil)
+	return h
+}
@@ -0,0 +1,19 @@
+package application
+
+import (
+	"Geometric_Construction/math_lib"
+	"math"
+	"testing"
+)
+
+func TestThicken(t *testing.T) {
+	cylinder := func(u, v float64) (x, y, z float64) { return math.Cos(u), math.Sin(u), v }
+	h := NewHandler().TriangulateParametricEquation(cylinder, []float64{0, 2 * math.Pi}, []float64{0, 1}, []int{64, 4})
+	if err := h.Thicken(0.1).Err(); err != nil {
+		t.Fatal(err)
+	}
+	// 接缝处的顶点被合并，不会在接缝处生成内部的侧壁
+	if r := math_lib.NewMesh(h.Triangles).Validate(); !r.Watertight || r.Components != 1 || r.Genus != 1 {
+		t.Errorf("report %+v", r)
+	}
+}
@@ -2,6 +2,7 @@ package application
 
 import (
 	"Geometric_Construction/math_lib"
+	"fmt"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -36,3 +37,33 @@ func (h *Handler) Transform(f func(*mat.VecDense) float64, st, ed []float64, N [
 
 	return h
 }
+
+// CapPolygon 对三维空间中的平面多边形(可带洞)进行三角剖分并加入当前三角形，用于封闭截面或扫掠端面
+func (h *Handler) CapPolygon(polygon [][]float64, holes ...[][]float64) *Handler {
+	if h.error != nil {
+		return h
+	}
+
+	rings := make([][]*mat.VecDense, 0, len(holes)+1)
+	for _, ring := range append([][][]float64{polygon}, holes...) {
+		vecs := make([]*mat.VecDense, len(ring))
+		for i, p := range ring {
+			if len(p) != 3 {
+				h.error = fmt.Errorf("cap polygon: vertex must have 3 coordinates, got %v", p)
+				return h
+			}
+			vecs[i] = mat.NewVecDense(3, p)
+		}
+		rings = append(rings, vecs)
+	}
+
+	res, err := math_lib.PlanarEarClippingTriangulation(rings[0], rings[1:]...)
+	if err != nil {
+		h.error = err
+		return h
+	}
+	for i := range res {
+		h.Triangles = append(h.Triangles, &res[i])
+	}
+	return h
+}
@@ -0,0 +1,15 @@
+package application
+
+import "Geometric_Construction/math_lib"
+
+// Validate 检查当前三角形组成的网格(坐标相同的顶点视为同一顶点)并将结果写入report(可为nil)，不修改当前三角形
+func (h *Handler) Validate(report *math_lib.ValidationReport) *Handler {
+	if h.error != nil {
+		return h
+	}
+
+	if report != nil {
+		*report = math_lib.NewMesh(h.Triangles).Validate()
+	}
+	return h
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"Geometric_Construction/application"
+	"Geometric_Construction/math_lib"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// runCommand 执行命令行子命令:
+//
+//	validate <file.stl>  检查网格并以JSON格式输出结果
+func runCommand(args []string) error {
+	switch args[0] {
+	case "validate":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: validate <file.stl>")
+		}
+		tris, err := application.LoadSTL(args[1])
+		if err != nil {
+			return err
+		}
+
+		var report math_lib.ValidationReport
+		h := application.NewHandler()
+		h.Triangles = tris
+		if err := h.Validate(&report).Err(); err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
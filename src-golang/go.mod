@@ -2,4 +2,4 @@ module Geometric_Construction
 
 go 1.24.6
 
-require gonum.org/v1/gonum v0.16.0 // indirect
+require gonum.org/v1/gonum v0.16.0
@@ -3,9 +3,19 @@ package main
 import (
 	"Geometric_Construction/application"
 	"Geometric_Construction/example_library"
+	"fmt"
+	"os"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	//// 创建隐函数控制器
 	//implicitCtrl := controller.NewImplicitFunctionController()
 	//
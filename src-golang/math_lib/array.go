@@ -0,0 +1,61 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// InstanceArray 按变换列表复制三角形集合，每个变换生成一份副本
+func InstanceArray(triangles []*Triangle, transforms []*mat.Dense) []*Triangle {
+	res := make([]*Triangle, 0, len(triangles)*len(transforms))
+	for _, m := range transforms {
+		res = append(res, TransformTriangles(m, triangles)...)
+	}
+	return res
+}
+
+// LinearArray 线性阵列: 沿delta方向复制count份(含原件)，第k份偏移k*delta
+func LinearArray(triangles []*Triangle, count int, delta *mat.VecDense) []*Triangle {
+	transforms := make([]*mat.Dense, 0, count)
+	for k := 0; k < count; k++ {
+		transforms = append(transforms, TranslateMatrix(ScaleVec2(float64(k), delta)))
+	}
+	return InstanceArray(triangles, transforms)
+}
+
+// GridArray 矩形网格阵列: counts[i]为第i个方向的数量，deltas[i]为该方向的间距向量
+func GridArray(triangles []*Triangle, counts []int, deltas []*mat.VecDense) []*Triangle {
+	total := 1
+	for _, c := range counts {
+		total *= c
+	}
+
+	transforms := make([]*mat.Dense, 0, total)
+	for i := 0; i < total; i++ {
+		offset := mat.NewVecDense(3, nil)
+		for idx, d := i, 0; d < len(counts); d++ {
+			k := idx % counts[d]
+			idx /= counts[d]
+			offset.AddScaledVec(offset, float64(k), deltas[d])
+		}
+		transforms = append(transforms, TranslateMatrix(offset))
+	}
+	return InstanceArray(triangles, transforms)
+}
+
+// PolarArray 环形阵列: 绕过center、方向为axis的轴在angle弧度范围内均匀复制count份(含原件)
+// angle为整圆时副本均分圆周，否则首尾副本分别位于0和angle处
+func PolarArray(triangles []*Triangle, count int, axis, center *mat.VecDense, angle float64) []*Triangle {
+	step := 0.0
+	if math.Abs(angle) >= 2*math.Pi-1e-9 {
+		step = angle / float64(count)
+	} else if count > 1 {
+		step = angle / float64(count-1)
+	}
+
+	transforms := make([]*mat.Dense, 0, count)
+	for k := 0; k < count; k++ {
+		transforms = append(transforms, RotateMatrix(axis, center, float64(k)*step))
+	}
+	return InstanceArray(triangles, transforms)
+}
@@ -0,0 +1,58 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func vec3(x, y, z float64) *mat.VecDense {
+	return mat.NewVecDense(3, []float64{x, y, z})
+}
+
+// sameVec 判断两个三维点是否在tolerance内重合
+func sameVec(a, b *mat.VecDense, tolerance float64) bool {
+	return math.Abs(a.AtVec(0)-b.AtVec(0)) <= tolerance && math.Abs(a.AtVec(1)-b.AtVec(1)) <= tolerance && math.Abs(a.AtVec(2)-b.AtVec(2)) <= tolerance
+}
+
+func TestLinearArray(t *testing.T) {
+	shared := vec3(0, 0, 0)
+	tris := []*Triangle{
+		{[3]*mat.VecDense{shared, vec3(1, 0, 0), vec3(0, 1, 0)}},
+		{[3]*mat.VecDense{shared, vec3(0, 1, 0), vec3(-1, 0, 0)}},
+	}
+	res := LinearArray(tris, 3, vec3(2, 0, 1))
+	if len(res) != 6 {
+		t.Fatalf("got %d triangles, want 6", len(res))
+	}
+	for k := 0; k < 3; k++ {
+		a, b := res[2*k], res[2*k+1]
+		if !sameVec(a.P[0], vec3(2*float64(k), 0, float64(k)), 1e-12) {
+			t.Errorf("copy %d starts at %v", k, a.P[0].RawVector().Data)
+		}
+		if a.P[0] != b.P[0] {
+			t.Errorf("copy %d does not share its common vertex", k)
+		}
+	}
+}
+
+func TestPolarArray(t *testing.T) {
+	tri := &Triangle{[3]*mat.VecDense{vec3(1, 0, 0), vec3(2, 0, 0), vec3(1, 1, 0)}}
+	res := PolarArray([]*Triangle{tri}, 4, vec3(0, 0, 1), vec3(0, 0, 0), 2*math.Pi)
+	if len(res) != 4 {
+		t.Fatalf("got %d triangles, want 4", len(res))
+	}
+	want := []*mat.VecDense{vec3(1, 0, 0), vec3(0, 1, 0), vec3(-1, 0, 0), vec3(0, -1, 0)}
+	for k, r := range res {
+		if !sameVec(r.P[0], want[k], 1e-12) {
+			t.Errorf("copy %d starts at %v, want %v", k, r.P[0].RawVector().Data, want[k].RawVector().Data)
+		}
+	}
+
+	// 非整圆时首尾副本位于0和angle处
+	res = PolarArray([]*Triangle{tri}, 3, vec3(0, 0, 2), vec3(0, 0, 0), math.Pi)
+	if !sameVec(res[2].P[0], vec3(-1, 0, 0), 1e-12) {
+		t.Errorf("last copy starts at %v", res[2].P[0].RawVector().Data)
+	}
+}
@@ -0,0 +1,161 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"sort"
+)
+
+// aabb 轴对齐包围盒
+type aabb struct {
+	lo, hi [3]float64
+}
+
+// pointsBox 计算若干点的包围盒
+func pointsBox(points ...*mat.VecDense) aabb {
+	b := aabb{
+		lo: [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)},
+		hi: [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)},
+	}
+	for _, p := range points {
+		for d := 0; d < 3; d++ {
+			b.lo[d], b.hi[d] = math.Min(b.lo[d], p.AtVec(d)), math.Max(b.hi[d], p.AtVec(d))
+		}
+	}
+	return b
+}
+
+// union 返回同时包含两个包围盒的最小包围盒
+func (b aabb) union(o aabb) aabb {
+	for d := 0; d < 3; d++ {
+		b.lo[d], b.hi[d] = math.Min(b.lo[d], o.lo[d]), math.Max(b.hi[d], o.hi[d])
+	}
+	return b
+}
+
+// overlaps 判断两个包围盒是否相交，边界接触也视为相交
+func (b aabb) overlaps(o aabb) bool {
+	for d := 0; d < 3; d++ {
+		if b.lo[d] > o.hi[d] || o.lo[d] > b.hi[d] {
+			return false
+		}
+	}
+	return true
+}
+
+// boxTree 静态包围盒层次树，用于查找与给定包围盒相交的元素
+type boxTree struct {
+	boxes []aabb
+	nodes []boxNode
+	root  int
+}
+
+// boxNode 包围盒树的节点，叶节点的left/right为-1，item为元素下标
+type boxNode struct {
+	box         aabb
+	left, right int
+	item        int
+}
+
+// newBoxTree 构造包围盒树，每层沿包围盒中心分布最广的坐标轴按中位数划分
+func newBoxTree(boxes []aabb) *boxTree {
+	t := &boxTree{boxes: boxes, nodes: make([]boxNode, 0, 2*len(boxes)), root: -1}
+	ids := make([]int, len(boxes))
+	for i := range ids {
+		ids[i] = i
+	}
+	if len(ids) > 0 {
+		t.root = t.build(ids)
+	}
+	return t
+}
+
+// build 递归构造子树，返回子树根节点的下标
+func (t *boxTree) build(ids []int) int {
+	idx := len(t.nodes)
+	t.nodes = append(t.nodes, boxNode{box: t.boxes[ids[0]], left: -1, right: -1, item: ids[0]})
+	if len(ids) == 1 {
+		return idx
+	}
+
+	center := func(i, d int) float64 { return t.boxes[i].lo[d] + t.boxes[i].hi[d] }
+	axis, spread := 0, -1.0
+	for d := 0; d < 3; d++ {
+		lo, hi := center(ids[0], d), center(ids[0], d)
+		for _, i := range ids[1:] {
+			lo, hi = math.Min(lo, center(i, d)), math.Max(hi, center(i, d))
+		}
+		if hi-lo > spread {
+			axis, spread = d, hi-lo
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return center(ids[i], axis) < center(ids[j], axis) })
+
+	mid := len(ids) / 2
+	left, right := t.build(ids[:mid]), t.build(ids[mid:])
+	t.nodes[idx].left, t.nodes[idx].right = left, right
+	t.nodes[idx].box = t.nodes[left].box.union(t.nodes[right].box)
+	return idx
+}
+
+// query 对每个包围盒与box相交的元素调用visit
+func (t *boxTree) query(box aabb, visit func(item int)) {
+	if t.root < 0 {
+		return
+	}
+	stack := []int{t.root}
+	for len(stack) > 0 {
+		node := t.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if !node.box.overlaps(box) {
+			continue
+		}
+		if node.left < 0 {
+			visit(node.item)
+			continue
+		}
+		stack = append(stack, node.left, node.right)
+	}
+}
+
+// distance2 返回点p到包围盒距离的平方，点在盒内时为0
+func (b aabb) distance2(p [3]float64) float64 {
+	d2 := 0.0
+	for d := 0; d < 3; d++ {
+		if p[d] < b.lo[d] {
+			d2 += (b.lo[d] - p[d]) * (b.lo[d] - p[d])
+		} else if p[d] > b.hi[d] {
+			d2 += (p[d] - b.hi[d]) * (p[d] - b.hi[d])
+		}
+	}
+	return d2
+}
+
+// nearest 返回到点p距离最近的元素与该距离的平方，dist2计算点p到元素的距离平方；树为空时返回-1
+// 先访问较近的子树，包围盒距离不小于当前最优值的子树被剪去
+func (t *boxTree) nearest(p [3]float64, dist2 func(item int) float64) (int, float64) {
+	best, bestDist := -1, math.Inf(1)
+	if t.root < 0 {
+		return best, bestDist
+	}
+	stack := []int{t.root}
+	for len(stack) > 0 {
+		node := t.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if node.box.distance2(p) >= bestDist {
+			continue
+		}
+		if node.left < 0 {
+			if d := dist2(node.item); d < bestDist {
+				best, bestDist = node.item, d
+			}
+			continue
+		}
+		near, far := node.left, node.right
+		if t.nodes[far].box.distance2(p) < t.nodes[near].box.distance2(p) {
+			near, far = far, near
+		}
+		stack = append(stack, far, near)
+	}
+	return best, bestDist
+}
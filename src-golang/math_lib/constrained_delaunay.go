@@ -0,0 +1,462 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// 约束边类型: 环边界决定区域内外，强制线段只要求出现在结果中
+const (
+	constraintRing = iota + 1
+	constraintSegment
+)
+
+// ConstrainedDelaunay 约束Delaunay三角剖分，只使用点的x/y分量
+// boundaries为外边界多边形，holes为孔洞多边形(均无需闭合，方向任意)，segments为必须出现在结果中的内部线段
+// 区域按奇偶规则确定: 从无穷远处出发每跨过一条边界或孔洞边，内外翻转一次；没有任何环时保留凸包内全部三角形
+// 相交的约束会在交点处分割
+func ConstrainedDelaunay(boundaries, holes [][]*mat.VecDense, segments []Edge) []Triangle {
+	t := newConstrainedTriangulation(boundaries, holes, segments)
+	if t == nil {
+		return []Triangle{}
+	}
+	return t.insideTriangles()
+}
+
+// newConstrainedTriangulation 建立约束三角剖分并标记各三角形所在区域，点数不足或全部共线时返回nil
+func newConstrainedTriangulation(boundaries, holes [][]*mat.VecDense, segments []Edge) *triangulation {
+	var points []*mat.VecDense
+	for _, ring := range append(append([][]*mat.VecDense{}, boundaries...), holes...) {
+		points = append(points, ring...)
+	}
+	for _, seg := range segments {
+		points = append(points, seg[0], seg[1])
+	}
+
+	t := newTriangulation(points)
+	if t == nil {
+		return nil
+	}
+	t.constraints = make(map[[2]int]int)
+
+	index := make(map[[2]float64]int, len(t.points))
+	for i, p := range t.points {
+		index[[2]float64{p.AtVec(0), p.AtVec(1)}] = i
+	}
+	lookup := func(p *mat.VecDense) int {
+		return index[[2]float64{p.AtVec(0), p.AtVec(1)}]
+	}
+
+	hasRing := false
+	for _, ring := range append(append([][]*mat.VecDense{}, boundaries...), holes...) {
+		for i := range ring {
+			a, b := lookup(ring[i]), lookup(ring[(i+1)%len(ring)])
+			if a != b {
+				t.insertSegment(a, b, constraintRing)
+				hasRing = true
+			}
+		}
+	}
+	for _, seg := range segments {
+		if a, b := lookup(seg[0]), lookup(seg[1]); a != b {
+			t.insertSegment(a, b, constraintSegment)
+		}
+	}
+
+	t.classifyRegions(hasRing)
+	return t
+}
+
+// addConstraint 记录约束边，环边界优先于普通线段
+func (t *triangulation) addConstraint(a, b, kind int) {
+	key := edgeKey(a, b)
+	if old, ok := t.constraints[key]; !ok || kind < old {
+		t.constraints[key] = kind
+	}
+}
+
+// trianglesAround 返回以顶点a为顶点的所有三角形(含幽灵三角形)，按逆时针顺序排列
+func (t *triangulation) trianglesAround(a int) []int {
+	start := t.vertexTri[a]
+	if t.tris[start].dead || vertexSlot(t.tris[start].v, a) < 0 {
+		for idx := range t.tris {
+			if !t.tris[idx].dead && vertexSlot(t.tris[idx].v, a) >= 0 {
+				start = idx
+				break
+			}
+		}
+		t.vertexTri[a] = start
+	}
+
+	res := []int{start}
+	for cur := start; ; {
+		i := vertexSlot(t.tris[cur].v, a)
+		cur = t.tris[cur].n[(i+1)%3]
+		if cur == start {
+			return res
+		}
+		res = append(res, cur)
+	}
+}
+
+// vertexSlot 返回顶点在三角形中的位置，不存在时返回-1
+func vertexSlot(v [3]int, a int) int {
+	for i := 0; i < 3; i++ {
+		if v[i] == a {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertSegment 将线段ab作为约束边恢复到三角剖分中
+// 删除线段穿过的三角形，两侧的伪多边形分别重新进行Delaunay三角化；线段经过其他顶点或与已有约束相交时分段处理
+func (t *triangulation) insertSegment(a, b, kind int) {
+	// 在a周围找到线段ab穿出的三角形
+	var (
+		start       = -1
+		left, right int
+	)
+	for _, idx := range t.trianglesAround(a) {
+		if t.isGhost(idx) {
+			continue
+		}
+		v := t.tris[idx].v
+		i := vertexSlot(v, a)
+		u, w := v[(i+1)%3], v[(i+2)%3]
+		if u == b || w == b {
+			t.addConstraint(a, b, kind)
+			return
+		}
+
+		ou, ow := t.orient(a, b, u), t.orient(a, b, w)
+		if ou == 0 && t.sameDirection(a, b, u) {
+			t.insertSegment(a, u, kind)
+			t.insertSegment(u, b, kind)
+			return
+		}
+		if ow == 0 && t.sameDirection(a, b, w) {
+			t.insertSegment(a, w, kind)
+			t.insertSegment(w, b, kind)
+			return
+		}
+		if ou < 0 && ow > 0 {
+			start, right, left = idx, u, w
+			break
+		}
+	}
+	if start < 0 {
+		return
+	}
+
+	// 沿线段行走，记录穿过的三角形以及两侧的顶点链
+	var (
+		crossed    = []int{start}
+		leftChain  = []int{a, left}
+		rightChain = []int{a, right}
+		cur        = start
+	)
+	for {
+		if t.isConstrained(left, right) {
+			x := t.splitCrossing(a, b, left, right)
+			t.insertSegment(a, x, kind)
+			t.insertSegment(x, b, kind)
+			return
+		}
+
+		v := t.tris[cur].v
+		cur = t.tris[cur].n[vertexSlot(v, oppositeSlotVertex(v, left, right))]
+		crossed = append(crossed, cur)
+		o := oppositeSlotVertex(t.tris[cur].v, left, right)
+
+		if o == b {
+			break
+		}
+		switch side := t.orient(a, b, o); {
+		case side > 0:
+			left = o
+			leftChain = append(leftChain, o)
+		case side < 0:
+			right = o
+			rightChain = append(rightChain, o)
+		default:
+			// 线段经过顶点o: 先恢复ao，再继续处理ob
+			t.recoverSegment(crossed, append(leftChain, o), append(rightChain, o))
+			t.addConstraint(a, o, kind)
+			t.insertSegment(o, b, kind)
+			return
+		}
+	}
+
+	t.recoverSegment(crossed, append(leftChain, b), append(rightChain, b))
+	t.addConstraint(a, b, kind)
+}
+
+// oppositeSlotVertex 返回三角形中除a、b外的第三个顶点
+func oppositeSlotVertex(v [3]int, a, b int) int {
+	for _, p := range v {
+		if p != a && p != b {
+			return p
+		}
+	}
+	return -1
+}
+
+// sameDirection 判断与ab共线的点p是否位于从a出发朝向b的一侧
+func (t *triangulation) sameDirection(a, b, p int) bool {
+	pa, pb, pp := t.points[a], t.points[b], t.points[p]
+	return (pb.AtVec(0)-pa.AtVec(0))*(pp.AtVec(0)-pa.AtVec(0))+(pb.AtVec(1)-pa.AtVec(1))*(pp.AtVec(1)-pa.AtVec(1)) > 0
+}
+
+// recoverSegment 删除线段穿过的三角形，分别三角化两侧以线段端点为首尾的顶点链
+func (t *triangulation) recoverSegment(crossed, leftChain, rightChain []int) {
+	t.stamp++
+	for _, idx := range crossed {
+		t.tris[idx].mark = t.stamp
+	}
+
+	created := make([][3]int, 0, len(crossed))
+	created = t.triangulatePseudoPolygon(leftChain, created)
+	created = t.triangulatePseudoPolygon(rightChain, created)
+
+	// 线段不穿过约束边，被删除的三角形属于同一区域
+	region := t.tris[crossed[0]].region
+	for _, idx := range t.replaceTriangles(crossed, created) {
+		t.tris[idx].region = region
+	}
+}
+
+// triangulatePseudoPolygon 对以chain首尾为底边、中间顶点位于底边同侧的伪多边形做Delaunay三角化 (Anglada)
+func (t *triangulation) triangulatePseudoPolygon(chain []int, res [][3]int) [][3]int {
+	if len(chain) < 3 {
+		return res
+	}
+
+	a, b := chain[0], chain[len(chain)-1]
+	ci := 1
+	for i := 2; i < len(chain)-1; i++ {
+		p, q, r := a, b, chain[ci]
+		if t.orient(p, q, r) < 0 {
+			p, q = q, p
+		}
+		if InCircle(t.points[p], t.points[q], t.points[r], t.points[chain[i]]) > 0 {
+			ci = i
+		}
+	}
+
+	res = t.triangulatePseudoPolygon(chain[:ci+1], res)
+	res = t.triangulatePseudoPolygon(chain[ci:], res)
+
+	c := chain[ci]
+	if t.orient(a, b, c) > 0 {
+		return append(res, [3]int{a, b, c})
+	}
+	return append(res, [3]int{b, a, c})
+}
+
+// splitCrossing 在线段ab与约束边cd的交点处插入新顶点，并将cd拆分为两段约束
+func (t *triangulation) splitCrossing(a, b, c, d int) int {
+	pa, pb, pc, pd := t.points[a], t.points[b], t.points[c], t.points[d]
+	r := mat.NewVecDense(3, nil)
+	r.SubVec(pb, pa)
+	s := mat.NewVecDense(3, nil)
+	s.SubVec(pd, pc)
+	denom := r.AtVec(0)*s.AtVec(1) - r.AtVec(1)*s.AtVec(0)
+	u := ((pc.AtVec(0)-pa.AtVec(0))*s.AtVec(1) - (pc.AtVec(1)-pa.AtVec(1))*s.AtVec(0)) / denom
+
+	x := mat.NewVecDense(3, nil)
+	x.AddScaledVec(pa, u, r)
+	return t.addPointOnEdge(x, c, d)
+}
+
+// addPointOnEdge 将新顶点p作为边ab上的点插入，返回其下标
+func (t *triangulation) addPointOnEdge(p *mat.VecDense, a, b int) int {
+	t.points = append(t.points, p)
+	t.vertexTri = append(t.vertexTri, t.vertexTri[a])
+	idx := len(t.points) - 1
+	tri, e := t.edgeTriangle(a, b)
+	t.legalize(idx, t.splitEdge(tri, e, idx))
+	return idx
+}
+
+// addPoint 向约束三角剖分中插入新顶点，返回其下标
+func (t *triangulation) addPoint(p *mat.VecDense) int {
+	t.points = append(t.points, p)
+	t.vertexTri = append(t.vertexTri, t.last)
+	idx := len(t.points) - 1
+	t.insertFlip(idx)
+	return idx
+}
+
+// insertFlip 以分裂加翻转(Lawson)的方式插入点p，翻转不跨越约束边，因此在约束附近也始终得到合法的三角剖分
+// p位于凸包外部时退化为Bowyer-Watson插入
+func (t *triangulation) insertFlip(p int) {
+	start := t.locate(p)
+	if t.isGhost(start) {
+		t.insert(p)
+		return
+	}
+
+	tri := t.tris[start]
+	for e := 0; e < 3; e++ {
+		if t.orient(tri.v[(e+1)%3], tri.v[(e+2)%3], p) == 0 {
+			t.legalize(p, t.splitEdge(start, e, p))
+			return
+		}
+	}
+
+	t.stamp++
+	t.tris[start].mark = t.stamp
+	created := make([][3]int, 0, 3)
+	for e := 0; e < 3; e++ {
+		created = append(created, [3]int{tri.v[(e+1)%3], tri.v[(e+2)%3], p})
+	}
+	ids := t.replaceTriangles([]int{start}, created)
+	for _, idx := range ids {
+		t.tris[idx].region = tri.region
+	}
+	t.legalize(p, ids)
+}
+
+// splitEdge 将点p视为位于三角形idx的第e条边ab上，把边两侧的三角形各分为两个；ab为约束边时拆分为ap、pb两段约束
+// 即使p因舍入误差略微偏离ab，拓扑上也不会产生横跨ab的狭长三角形
+func (t *triangulation) splitEdge(idx, e, p int) []int {
+	if t.isGhost(idx) { // 从有限三角形一侧处理凸包边
+		nb := t.tris[idx].n[e]
+		e = vertexSlot(t.tris[nb].v, oppositeSlotVertex(t.tris[nb].v, t.tris[idx].v[0], t.tris[idx].v[1]))
+		idx = nb
+	}
+	tri := t.tris[idx]
+	c, a, b := tri.v[e], tri.v[(e+1)%3], tri.v[(e+2)%3]
+	nb := tri.n[e]
+	d := oppositeSlotVertex(t.tris[nb].v, a, b)
+
+	t.stamp++
+	t.tris[idx].mark = t.stamp
+	t.tris[nb].mark = t.stamp
+	created := [][3]int{{c, a, p}, {c, p, b}}
+	if d == ghostVertex {
+		created = append(created, [3]int{b, p, ghostVertex}, [3]int{p, a, ghostVertex})
+	} else {
+		created = append(created, [3]int{d, b, p}, [3]int{d, p, a})
+	}
+	regions := []int{tri.region, tri.region, t.tris[nb].region, t.tris[nb].region}
+
+	ids := t.replaceTriangles([]int{idx, nb}, created)
+	for i, id := range ids {
+		t.tris[id].region = regions[i]
+	}
+
+	if kind, ok := t.constraints[edgeKey(a, b)]; ok {
+		delete(t.constraints, edgeKey(a, b))
+		t.addConstraint(a, p, kind)
+		t.addConstraint(p, b, kind)
+	}
+	return ids
+}
+
+// edgeTriangle 返回以有向边a->b为边的三角形及该边的序号，不存在时返回-1
+func (t *triangulation) edgeTriangle(a, b int) (int, int) {
+	for _, idx := range t.trianglesAround(a) {
+		v := t.tris[idx].v
+		if i := vertexSlot(v, a); v[(i+1)%3] == b {
+			return idx, (i + 2) % 3
+		}
+	}
+	return -1, -1
+}
+
+// legalize 对点p所对的非约束边进行Delaunay翻转，直到所有相关边都满足空外接圆性质
+func (t *triangulation) legalize(p int, stack []int) {
+	for len(stack) > 0 {
+		idx := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		tri := t.tris[idx]
+		i := vertexSlot(tri.v, p)
+		if tri.dead || i < 0 || t.isGhost(idx) {
+			continue
+		}
+		a, b := tri.v[(i+1)%3], tri.v[(i+2)%3]
+		nb := tri.n[i]
+		if t.isGhost(nb) || t.isConstrained(a, b) {
+			continue
+		}
+		d := oppositeSlotVertex(t.tris[nb].v, a, b)
+		if InCircle(t.points[p], t.points[a], t.points[b], t.points[d]) <= 0 {
+			continue
+		}
+
+		t.stamp++
+		t.tris[idx].mark = t.stamp
+		t.tris[nb].mark = t.stamp
+		ids := t.replaceTriangles([]int{idx, nb}, [][3]int{{p, a, d}, {p, d, b}})
+		for _, id := range ids {
+			t.tris[id].region = tri.region
+		}
+		stack = append(stack, ids...)
+	}
+}
+
+// classifyRegions 按奇偶规则计算每个三角形的区域深度；没有环边界时所有有限三角形均视为内部
+func (t *triangulation) classifyRegions(hasRing bool) {
+	for idx := range t.tris {
+		t.tris[idx].region = -1
+		if !hasRing && !t.isGhost(idx) {
+			t.tris[idx].region = 1
+		}
+	}
+
+	var layer []int
+	for idx := range t.tris {
+		if !t.tris[idx].dead && t.isGhost(idx) {
+			t.tris[idx].region = 0
+			layer = append(layer, idx)
+		}
+	}
+	if !hasRing {
+		return
+	}
+
+	for d := 0; len(layer) > 0; d++ {
+		var next []int
+		for i := 0; i < len(layer); i++ {
+			tri := t.tris[layer[i]]
+			for e, nb := range tri.n {
+				if t.tris[nb].region >= 0 {
+					continue
+				}
+				if t.constraints[edgeKey(tri.v[(e+1)%3], tri.v[(e+2)%3])] == constraintRing {
+					next = append(next, nb)
+					continue
+				}
+				t.tris[nb].region = d
+				layer = append(layer, nb)
+			}
+		}
+
+		layer = layer[:0]
+		for _, idx := range next {
+			if t.tris[idx].region < 0 {
+				t.tris[idx].region = d + 1
+				layer = append(layer, idx)
+			}
+		}
+	}
+}
+
+// isInside 判断三角形是否为位于区域内部的有限三角形
+func (t *triangulation) isInside(idx int) bool {
+	tri := t.tris[idx]
+	return !tri.dead && tri.v[2] != ghostVertex && tri.region%2 == 1
+}
+
+// insideTriangles 返回位于区域内部的有限三角形
+func (t *triangulation) insideTriangles() []Triangle {
+	res := make([]Triangle, 0, len(t.tris))
+	for idx, tri := range t.tris {
+		if t.isInside(idx) {
+			res = append(res, Triangle{[3]*mat.VecDense{t.points[tri.v[0]], t.points[tri.v[1]], t.points[tri.v[2]]}})
+		}
+	}
+	return res
+}
@@ -0,0 +1,57 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// triangleArea2D 返回三角形集合在xy平面上的有向面积之和
+func triangleArea2D(tris []Triangle) float64 {
+	area := 0.0
+	for _, tri := range tris {
+		area += Orient2D(tri.P[0], tri.P[1], tri.P[2]) / 2
+	}
+	return area
+}
+
+// crossesSegment 判断线段pq与线段ab是否在内部相交
+func crossesSegment(p, q, a, b *mat.VecDense) bool {
+	return Orient2D(a, b, p)*Orient2D(a, b, q) < 0 && Orient2D(p, q, a)*Orient2D(p, q, b) < 0
+}
+
+func TestConstrainedDelaunayHole(t *testing.T) {
+	outer := []*mat.VecDense{vec3(0, 0, 0), vec3(4, 0, 0), vec3(4, 4, 0), vec3(0, 4, 0)}
+	hole := []*mat.VecDense{vec3(1, 1, 0), vec3(1, 3, 0), vec3(3, 3, 0), vec3(3, 1, 0)}
+	tris := ConstrainedDelaunay([][]*mat.VecDense{outer}, [][]*mat.VecDense{hole}, nil)
+	if area := triangleArea2D(tris); math.Abs(area-12) > 1e-12 {
+		t.Errorf("area %g, want 12", area)
+	}
+	for _, tri := range tris {
+		c := vec3((tri.P[0].AtVec(0)+tri.P[1].AtVec(0)+tri.P[2].AtVec(0))/3, (tri.P[0].AtVec(1)+tri.P[1].AtVec(1)+tri.P[2].AtVec(1))/3, 0)
+		if c.AtVec(0) > 1 && c.AtVec(0) < 3 && c.AtVec(1) > 1 && c.AtVec(1) < 3 {
+			t.Errorf("triangle %v lies in the hole", tri)
+		}
+	}
+}
+
+func TestConstrainedDelaunaySegments(t *testing.T) {
+	// 凹多边形与两条相交的强制线段
+	outer := []*mat.VecDense{vec3(0, 0, 0), vec3(10, 0, 0), vec3(10, 10, 0), vec3(5, 1, 0), vec3(0, 10, 0)}
+	segments := []Edge{{vec3(1, 0.5, 0), vec3(9, 1.5, 0)}, {vec3(2, 2, 0), vec3(2, 0.2, 0)}}
+	tris := ConstrainedDelaunay([][]*mat.VecDense{outer}, nil, segments)
+	if area := triangleArea2D(tris); math.Abs(area-55) > 1e-9 {
+		t.Errorf("area %g, want 55", area)
+	}
+	for _, tri := range tris {
+		for e := 0; e < 3; e++ {
+			p, q := tri.P[e], tri.P[(e+1)%3]
+			for _, s := range segments {
+				if crossesSegment(p, q, s[0], s[1]) {
+					t.Fatalf("edge %v-%v crosses segment %v-%v", p.RawVector().Data, q.RawVector().Data, s[0].RawVector().Data, s[1].RawVector().Data)
+				}
+			}
+		}
+	}
+}
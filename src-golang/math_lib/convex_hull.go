@@ -0,0 +1,277 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// hullFace 凸包上的三角形面，顶点逆时针排列使法向量指向外侧，n[i]为与顶点v[i]相对的边另一侧的面
+type hullFace struct {
+	v       [3]int
+	n       [3]int
+	outside []int // 位于该面外侧、尚未加入凸包的点
+	dead    bool
+	mark    int
+}
+
+// quickHull 三维Quickhull的状态
+type quickHull struct {
+	points []*mat.VecDense
+	faces  []hullFace
+	stamp  int
+}
+
+// ConvexHull 使用Quickhull计算点集的三维凸包，返回法向量指向外侧的封闭三角网格
+// 可见性使用精确的Orient3D判断；点集共面时返回正反两面重合的平面凸多边形，共线或点数不足时返回空
+func ConvexHull(points []*mat.VecDense) []Triangle {
+	q := &quickHull{points: uniquePoints3D(points)}
+	if len(q.points) < 3 {
+		return []Triangle{}
+	}
+
+	a, b, c, ok := q.initialTriangle()
+	if !ok {
+		return []Triangle{}
+	}
+	d := q.farthestFromPlane(a, b, c)
+	if Orient3D(q.points[a], q.points[b], q.points[c], q.points[d]) == 0 {
+		return q.planarHull(a, b, c)
+	}
+
+	q.initTetrahedron(a, b, c, d)
+	for i := 0; i < len(q.faces); i++ {
+		if !q.faces[i].dead && len(q.faces[i].outside) > 0 {
+			q.addPoint(i)
+		}
+	}
+	return q.triangles()
+}
+
+// initialTriangle 选取各坐标轴上的极值点中距离最远的两点，以及离这两点所在直线最远的点
+func (q *quickHull) initialTriangle() (a, b, c int, ok bool) {
+	var extremes []int
+	for d := 0; d < 3; d++ {
+		lo, hi := 0, 0
+		for i, p := range q.points {
+			if p.AtVec(d) < q.points[lo].AtVec(d) {
+				lo = i
+			}
+			if p.AtVec(d) > q.points[hi].AtVec(d) {
+				hi = i
+			}
+		}
+		extremes = append(extremes, lo, hi)
+	}
+
+	best := -1.0
+	for _, i := range extremes {
+		for _, j := range extremes {
+			if d := squaredDistance(q.points[i], q.points[j]); d > best {
+				a, b, best = i, j, d
+			}
+		}
+	}
+	if a == b {
+		return 0, 0, 0, false
+	}
+
+	ab := SubVec(mat.NewVecDense(3, nil), q.points[b], q.points[a])
+	c, best = -1, -1.0
+	for i, p := range q.points {
+		if collinear3D(q.points[a], q.points[b], p) {
+			continue
+		}
+		ap := SubVec(mat.NewVecDense(3, nil), p, q.points[a])
+		if d := mat.Norm(Cross2(ab, ap), 2); d > best {
+			c, best = i, d
+		}
+	}
+	return a, b, c, c >= 0
+}
+
+// farthestFromPlane 返回离平面abc最远的点
+func (q *quickHull) farthestFromPlane(a, b, c int) int {
+	res, best := a, 0.0
+	for i, p := range q.points {
+		if d := math.Abs(Orient3D(q.points[a], q.points[b], q.points[c], p)); d > best {
+			res, best = i, d
+		}
+	}
+	return res
+}
+
+// initTetrahedron 以四个不共面的点建立初始四面体，并将其余点分配到可见的面
+func (q *quickHull) initTetrahedron(a, b, c, d int) {
+	if Orient3D(q.points[a], q.points[b], q.points[c], q.points[d]) < 0 {
+		a, b = b, a
+	}
+
+	v := [4]int{a, b, c, d}
+	ids := make([]int, 4)
+	for i, f := range tetFaces {
+		ids[i] = q.newFace([3]int{v[f[0]], v[f[2]], v[f[1]]}) // 反转四面体的面使法向量指向外侧
+	}
+	q.linkFaces(ids)
+
+	for i := range q.points {
+		if i != a && i != b && i != c && i != d {
+			q.assign(i, ids)
+		}
+	}
+}
+
+// newFace 添加一个面
+func (q *quickHull) newFace(v [3]int) int {
+	q.faces = append(q.faces, hullFace{v: v, n: [3]int{-1, -1, -1}})
+	return len(q.faces) - 1
+}
+
+// linkFaces 为一组新面中尚未设置邻接的边互相建立邻接关系
+func (q *quickHull) linkFaces(ids []int) {
+	edges := make(map[[2]int][2]int, 3*len(ids))
+	for _, id := range ids {
+		v := q.faces[id].v
+		for e := 0; e < 3; e++ {
+			if q.faces[id].n[e] == -1 {
+				edges[[2]int{v[(e+1)%3], v[(e+2)%3]}] = [2]int{id, e}
+			}
+		}
+	}
+	for key, val := range edges {
+		if other, ok := edges[[2]int{key[1], key[0]}]; ok {
+			q.faces[val[0]].n[val[1]] = other[0]
+		}
+	}
+}
+
+// visible 判断点p是否严格位于面的外侧
+func (q *quickHull) visible(f, p int) bool {
+	v := q.faces[f].v
+	return Orient3D(q.points[v[0]], q.points[v[1]], q.points[v[2]], q.points[p]) > 0
+}
+
+// assign 将点p分配给第一个可以看到它的面，位于所有面内侧的点被丢弃
+func (q *quickHull) assign(p int, faces []int) {
+	for _, f := range faces {
+		if q.visible(f, p) {
+			q.faces[f].outside = append(q.faces[f].outside, p)
+			return
+		}
+	}
+}
+
+// addPoint 将面f外侧最远的点加入凸包: 删除所有可见面，以该点连接可见区域的边界(地平线)
+func (q *quickHull) addPoint(f int) {
+	face := q.faces[f]
+	p, best := -1, -1.0
+	for _, i := range face.outside {
+		if d := Orient3D(q.points[face.v[0]], q.points[face.v[1]], q.points[face.v[2]], q.points[i]); d > best {
+			p, best = i, d
+		}
+	}
+
+	q.stamp++
+	q.faces[f].mark = q.stamp
+	visibleFaces := []int{f}
+	for i := 0; i < len(visibleFaces); i++ {
+		for _, nb := range q.faces[visibleFaces[i]].n {
+			if q.faces[nb].mark != q.stamp && q.visible(nb, p) {
+				q.faces[nb].mark = q.stamp
+				visibleFaces = append(visibleFaces, nb)
+			}
+		}
+	}
+
+	// 地平线上的每条边与p组成新面，新面保持与相邻不可见面一致的定向
+	var created []int
+	for _, idx := range visibleFaces {
+		for e := 0; e < 3; e++ {
+			nb := q.faces[idx].n[e]
+			if q.faces[nb].mark == q.stamp {
+				continue
+			}
+			v := q.faces[idx].v
+			id := q.newFace([3]int{v[(e+1)%3], v[(e+2)%3], p})
+			q.faces[id].n[2] = nb
+			for k := 0; k < 3; k++ {
+				if q.faces[nb].n[k] == idx {
+					q.faces[nb].n[k] = id
+				}
+			}
+			created = append(created, id)
+		}
+	}
+	q.linkFaces(created)
+
+	for _, idx := range visibleFaces {
+		q.faces[idx].dead = true
+		for _, i := range q.faces[idx].outside {
+			if i != p {
+				q.assign(i, created)
+			}
+		}
+		q.faces[idx].outside = nil
+	}
+}
+
+// triangles 返回凸包的所有面
+func (q *quickHull) triangles() []Triangle {
+	res := make([]Triangle, 0, len(q.faces))
+	for _, f := range q.faces {
+		if !f.dead {
+			res = append(res, Triangle{[3]*mat.VecDense{q.points[f.v[0]], q.points[f.v[1]], q.points[f.v[2]]}})
+		}
+	}
+	return res
+}
+
+// planarHull 点集共面时在其所在平面内计算二维凸包，以扇形三角化并输出正反两面
+func (q *quickHull) planarHull(a, b, c int) []Triangle {
+	ab := SubVec(mat.NewVecDense(3, nil), q.points[b], q.points[a])
+	ac := SubVec(mat.NewVecDense(3, nil), q.points[c], q.points[a])
+	frame := newPlaneFrame(q.points[a], Normalize(Cross2(ab, ac)))
+
+	origin := make(map[*mat.VecDense]*mat.VecDense, len(q.points))
+	polygon := ConvexHull2D(frame.projectAll(q.points, origin))
+
+	res := make([]Triangle, 0, 2*len(polygon))
+	for i := 1; i+1 < len(polygon); i++ {
+		p0, p1, p2 := origin[polygon[0]], origin[polygon[i]], origin[polygon[i+1]]
+		res = append(res, Triangle{[3]*mat.VecDense{p0, p1, p2}}, Triangle{[3]*mat.VecDense{p0, p2, p1}})
+	}
+	return res
+}
+
+// ConvexHull2D 使用单调链方法计算点集在xy平面上的凸包，返回逆时针排列的凸包顶点，不包含共线的点
+func ConvexHull2D(points []*mat.VecDense) []*mat.VecDense {
+	sorted := uniquePoints(points)
+	if len(sorted) < 3 {
+		return sorted
+	}
+
+	// 依次构造下凸链与上凸链，只保留严格左转的顶点
+	hull := make([]*mat.VecDense, 0, 2*len(sorted))
+	build := func(pts []*mat.VecDense) {
+		start := len(hull)
+		for _, p := range pts {
+			for len(hull) >= start+2 && Orient2D(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		hull = hull[:len(hull)-1] // 链的终点是另一条链的起点
+	}
+	build(sorted)
+	reversed := make([]*mat.VecDense, len(sorted))
+	for i, p := range sorted {
+		reversed[len(sorted)-1-i] = p
+	}
+	build(reversed)
+	return hull
+}
+
+// squaredDistance 计算两点距离的平方
+func squaredDistance(a, b *mat.VecDense) float64 {
+	d := SubVec(mat.NewVecDense(3, nil), a, b)
+	return mat.Dot(d, d)
+}
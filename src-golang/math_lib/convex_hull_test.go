@@ -0,0 +1,58 @@
+package math_lib
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestConvexHull(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var points []*mat.VecDense
+	for i := 0; i < 500; i++ {
+		points = append(points, vec3(rng.Float64(), rng.Float64(), rng.Float64()))
+	}
+	for i := 0; i < 8; i++ {
+		points = append(points, vec3(float64(i&1), float64(i>>1&1), float64(i>>2&1)))
+	}
+	// 面上的共面点不应成为凸包顶点
+	points = append(points, vec3(0.5, 0.5, 0), vec3(0.5, 0, 0.5), vec3(1, 0.25, 0.75))
+
+	hull := ConvexHull(points)
+	area, volume := 0.0, 0.0
+	for _, tri := range hull {
+		n := Cross2(SubVec(mat.NewVecDense(3, nil), tri.P[1], tri.P[0]), SubVec(mat.NewVecDense(3, nil), tri.P[2], tri.P[0]))
+		area += mat.Norm(n, 2) / 2
+		volume += mat.Dot(tri.P[0], n) / 6
+		for _, p := range points {
+			if Orient3D(tri.P[0], tri.P[1], tri.P[2], p) > 0 {
+				t.Fatalf("point %v lies outside hull face %v", p.RawVector().Data, tri)
+			}
+		}
+	}
+	if math.Abs(area-6) > 1e-9 || math.Abs(volume-1) > 1e-9 {
+		t.Errorf("hull area %g and volume %g, want 6 and 1", area, volume)
+	}
+}
+
+func TestConvexHull2D(t *testing.T) {
+	points := []*mat.VecDense{vec3(0, 0, 0), vec3(1, 0, 0), vec3(2, 0, 0), vec3(2, 2, 0), vec3(1, 1, 0), vec3(0, 2, 0), vec3(0, 1, 0), vec3(2, 2, 0)}
+	hull := ConvexHull2D(points)
+	want := []*mat.VecDense{vec3(0, 0, 0), vec3(2, 0, 0), vec3(2, 2, 0), vec3(0, 2, 0)}
+	if len(hull) != len(want) {
+		t.Fatalf("got %d hull vertices, want %d", len(hull), len(want))
+	}
+	start := 0
+	for i, p := range hull {
+		if sameVec(p, want[0], 0) {
+			start = i
+		}
+	}
+	for i, p := range want {
+		if !sameVec(hull[(start+i)%len(hull)], p, 0) {
+			t.Errorf("hull vertex %d is %v, want %v", i, hull[(start+i)%len(hull)].RawVector().Data, p.RawVector().Data)
+		}
+	}
+}
@@ -9,106 +9,347 @@ import (
 // Edge 表示边，包含两个点
 type Edge [2]*mat.VecDense
 
-// Delaunay 执行Delaunay三角剖分
+// ghostVertex 表示无穷远点，凸包的每条边与它组成一个幽灵三角形，用来代替超级三角形
+const ghostVertex = -1
+
+// dtTriangle 三角剖分中的三角形，顶点逆时针排列，n[i]为与顶点v[i]相对的边(v[i+1], v[i+2])另一侧的三角形
+// 幽灵三角形的无穷远点固定在v[2]，其有限边v[0]->v[1]的左侧为凸包外部
+type dtTriangle struct {
+	v      [3]int
+	n      [3]int
+	dead   bool
+	mark   int
+	region int // 约束三角剖分中的区域深度，奇数表示位于区域内部
+}
+
+// triangulation 基于幽灵三角形的二维增量Delaunay三角剖分，只使用点的x/y分量
+type triangulation struct {
+	points      []*mat.VecDense
+	tris        []dtTriangle
+	free        []int
+	vertexTri   []int          // 每个顶点所在的任意一个三角形
+	constraints map[[2]int]int // 约束边及其类型，插入点时空腔不跨越约束边
+	last        int
+	stamp       int
+}
+
+// Delaunay 执行Delaunay三角剖分，返回逆时针排列的三角形
+// 重复点只保留第一个，全部共线时返回空
 func Delaunay(points []*mat.VecDense) []Triangle {
-	// 第一步：按x坐标排序点集
-	sort.Slice(points, func(i, j int) bool {
-		if points[i].At(0, 0) != points[j].At(0, 0) {
-			return points[i].At(0, 0) < points[j].At(0, 0)
+	t := newTriangulation(points)
+	if t == nil {
+		return []Triangle{}
+	}
+	return t.finiteTriangles()
+}
+
+// newTriangulation 对去重后的点集进行三角剖分，点数不足或全部共线时返回nil
+func newTriangulation(points []*mat.VecDense) *triangulation {
+	t := &triangulation{points: uniquePoints(points)}
+	if len(t.points) < 3 {
+		return nil
+	}
+	t.vertexTri = make([]int, len(t.points))
+
+	// 按字典序插入，相邻插入的点在空间上也相邻，定位时的行走路径较短
+	k := 2
+	for k < len(t.points) && t.orient(0, 1, k) == 0 {
+		k++
+	}
+	if k == len(t.points) {
+		return nil
+	}
+	t.initTriangle(0, 1, k)
+
+	for i := 2; i < len(t.points); i++ {
+		if i != k {
+			t.insert(i)
 		}
-		return points[i].At(1, 0) < points[j].At(1, 0)
+	}
+	return t
+}
+
+// uniquePoints 按字典序排序并移除坐标相同的点，不修改输入切片
+func uniquePoints(points []*mat.VecDense) []*mat.VecDense {
+	sorted := make([]*mat.VecDense, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].AtVec(0) != sorted[j].AtVec(0) {
+			return sorted[i].AtVec(0) < sorted[j].AtVec(0)
+		}
+		return sorted[i].AtVec(1) < sorted[j].AtVec(1)
 	})
 
-	// 第二步：计算超级三角形
-	maxPoint := points[0]
-	minPoint := points[0]
+	res := make([]*mat.VecDense, 0, len(sorted))
+	for _, p := range sorted {
+		if len(res) > 0 && pointsEqual(res[len(res)-1], p) {
+			continue
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+// initTriangle 以三个不共线的点建立初始三角形及其三个幽灵三角形
+func (t *triangulation) initTriangle(a, b, c int) {
+	if t.orient(a, b, c) < 0 {
+		a, b = b, a
+	}
+
+	first := t.newTriangle([3]int{a, b, c})
+	created := []int{first}
+	for e := 0; e < 3; e++ {
+		v := t.tris[first].v
+		created = append(created, t.newTriangle([3]int{v[(e+2)%3], v[(e+1)%3], ghostVertex}))
+	}
+	t.linkTriangles(created)
+	t.last = first
+}
+
+// newTriangle 分配一个三角形，优先复用已删除的位置
+func (t *triangulation) newTriangle(v [3]int) int {
+	tri := dtTriangle{v: v, n: [3]int{-1, -1, -1}}
+	idx := len(t.tris)
+	if len(t.free) > 0 {
+		idx = t.free[len(t.free)-1]
+		t.free = t.free[:len(t.free)-1]
+		t.tris[idx] = tri
+	} else {
+		t.tris = append(t.tris, tri)
+	}
 
-	for i := 1; i < len(points); i++ {
-		if points[i].At(0, 0) > maxPoint.At(0, 0) || (points[i].At(0, 0) == maxPoint.At(0, 0) && points[i].At(1, 0) > maxPoint.At(1, 0)) {
-			maxPoint = points[i]
+	for _, p := range v {
+		if p != ghostVertex {
+			t.vertexTri[p] = idx
 		}
-		if points[i].At(0, 0) < minPoint.At(0, 0) || (points[i].At(0, 0) == minPoint.At(0, 0) && points[i].At(1, 0) < minPoint.At(1, 0)) {
-			minPoint = points[i]
+	}
+	return idx
+}
+
+// deleteTriangle 删除三角形并回收位置
+func (t *triangulation) deleteTriangle(idx int) {
+	t.tris[idx].dead = true
+	t.free = append(t.free, idx)
+}
+
+// linkTriangles 为一组新三角形中尚未设置邻接的边互相建立邻接关系
+func (t *triangulation) linkTriangles(ids []int) {
+	edges := make(map[[2]int][2]int, 3*len(ids))
+	for _, id := range ids {
+		v := t.tris[id].v
+		for e := 0; e < 3; e++ {
+			if t.tris[id].n[e] == -1 {
+				edges[[2]int{v[(e+1)%3], v[(e+2)%3]}] = [2]int{id, e}
+			}
+		}
+	}
+	for key, val := range edges {
+		if other, ok := edges[[2]int{key[1], key[0]}]; ok {
+			t.tris[val[0]].n[val[1]] = other[0]
 		}
 	}
+}
 
-	lengthX := maxPoint.At(0, 0) - minPoint.At(0, 0)
-	lengthY := maxPoint.At(1, 0) - minPoint.At(1, 0)
+// isGhost 判断三角形是否为幽灵三角形
+func (t *triangulation) isGhost(idx int) bool {
+	return t.tris[idx].v[2] == ghostVertex
+}
 
-	supertriangle := Triangle{
-		[3]*mat.VecDense{
-			mat.NewVecDense(3, []float64{minPoint.At(0, 0) - lengthX - 2, minPoint.At(1, 0) - 2, 0}),
-			mat.NewVecDense(3, []float64{maxPoint.At(0, 0) + lengthX + 2, minPoint.At(1, 0) - 2, 0}),
-			mat.NewVecDense(3, []float64{(maxPoint.At(0, 0) + minPoint.At(0, 0)) / 2, maxPoint.At(1, 0) + lengthY + 2, 0}),
-		},
+// orient 对点的下标调用Orient2D
+func (t *triangulation) orient(a, b, c int) float64 {
+	return Orient2D(t.points[a], t.points[b], t.points[c])
+}
+
+// inConflict 判断点p是否在三角形的外接圆内，幽灵三角形的外接圆为其有限边左侧的开半平面加上该边的开线段
+func (t *triangulation) inConflict(idx, p int) bool {
+	v := t.tris[idx].v
+	if v[2] != ghostVertex {
+		return InCircle(t.points[v[0]], t.points[v[1]], t.points[v[2]], t.points[p]) > 0
 	}
 
-	triTemp := []Triangle{supertriangle}
-	triAns := []Triangle{}
-	edgeBuffer := []Edge{}
+	o := t.orient(v[0], v[1], p)
+	if o != 0 {
+		return o > 0
+	}
+	return strictlyBetween(t.points[v[0]], t.points[v[1]], t.points[p])
+}
 
-	// 第三步：逐点插入
-	for _, point := range points {
-		edgeBuffer = []Edge{}
+// strictlyBetween 判断与线段ab共线的点p是否位于线段内部
+func strictlyBetween(a, b, p *mat.VecDense) bool {
+	d := 0
+	if a.AtVec(0) == b.AtVec(0) {
+		d = 1
+	}
+	lo, hi := math.Min(a.AtVec(d), b.AtVec(d)), math.Max(a.AtVec(d), b.AtVec(d))
+	return p.AtVec(d) > lo && p.AtVec(d) < hi
+}
 
-		for j := 0; j < len(triTemp); j++ {
-			triangle := triTemp[j]
-			center, R := circumcircle(triangle.P[0], triangle.P[1], triangle.P[2])
+// locate 从上次插入的位置出发行走，找到一个外接圆包含点p的三角形
+func (t *triangulation) locate(p int) int {
+	cur := t.last
+	if t.tris[cur].dead {
+		cur = t.anyAlive()
+	}
+	if t.isGhost(cur) {
+		cur = t.tris[cur].n[2]
+	}
 
-			// 检查点是否在外接圆右侧
-			if point.At(0, 0) > center.At(0, 0)+R {
-				triAns = append(triAns, triangle)
-				triTemp = append(triTemp[:j], triTemp[j+1:]...)
-				j--
-				continue
+	for steps := 0; steps < 4*len(t.tris)+16; steps++ {
+		v := t.tris[cur].v
+		moved := false
+		for i := 0; i < 3; i++ {
+			e := (i + steps) % 3
+			if t.orient(v[(e+1)%3], v[(e+2)%3], p) < 0 {
+				cur = t.tris[cur].n[e]
+				moved = true
+				break
 			}
+		}
+		if !moved || t.isGhost(cur) {
+			return cur
+		}
+	}
 
-			// 计算点到圆心的距离
-			dx := point.At(0, 0) - center.At(0, 0)
-			dy := point.At(1, 0) - center.At(1, 0)
-			distance := math.Sqrt(dx*dx + dy*dy)
+	// 行走未收敛时退化为线性查找
+	for idx := range t.tris {
+		if !t.tris[idx].dead && t.inConflict(idx, p) {
+			return idx
+		}
+	}
+	return cur
+}
 
-			// 如果点在圆内
-			if distance < R {
-				// 添加三角形的三条边到边缘缓冲区
-				edgeBuffer = append(edgeBuffer, Edge{triangle.P[0], triangle.P[1]})
-				edgeBuffer = append(edgeBuffer, Edge{triangle.P[1], triangle.P[2]})
-				edgeBuffer = append(edgeBuffer, Edge{triangle.P[2], triangle.P[0]})
+// anyAlive 返回任意一个未删除的三角形
+func (t *triangulation) anyAlive() int {
+	for idx := range t.tris {
+		if !t.tris[idx].dead {
+			return idx
+		}
+	}
+	return -1
+}
 
-				triTemp = append(triTemp[:j], triTemp[j+1:]...)
-				j--
+// insert 使用Bowyer-Watson方法插入点p: 删除外接圆包含p的三角形，以p连接空腔边界
+func (t *triangulation) insert(p int) {
+	t.retriangulateCavity(p, t.cavity(t.locate(p), p))
+}
+
+// cavity 从start出发沿邻接关系搜索所有与点p冲突的三角形
+func (t *triangulation) cavity(start, p int) []int {
+	t.stamp++
+	t.tris[start].mark = t.stamp
+	res := []int{start}
+	for i := 0; i < len(res); i++ {
+		tri := t.tris[res[i]]
+		for e, nb := range tri.n {
+			if t.tris[nb].mark == t.stamp || t.isConstrained(tri.v[(e+1)%3], tri.v[(e+2)%3]) || !t.inConflict(nb, p) {
+				continue
 			}
+			t.tris[nb].mark = t.stamp
+			res = append(res, nb)
 		}
+	}
+	return res
+}
 
-		// 移除重复的边
-		edgeBuffer = removeDuplicateEdges(edgeBuffer)
+// retriangulateCavity 删除空腔内的三角形，并用点p与空腔边界的每条边组成新三角形
+func (t *triangulation) retriangulateCavity(p int, cavity []int) {
+	created := make([][3]int, 0, len(cavity)+2)
+	regions := make([]int, 0, len(cavity)+2)
+	for _, idx := range cavity {
+		tri := t.tris[idx]
+		for e := 0; e < 3; e++ {
+			if t.tris[tri.n[e]].mark == t.stamp {
+				continue
+			}
 
-		// 为每条边创建新的三角形
-		for _, edge := range edgeBuffer {
-			triTemp = append(triTemp, Triangle{[3]*mat.VecDense{edge[0], edge[1], point}})
+			a, b := tri.v[(e+1)%3], tri.v[(e+2)%3]
+			v := [3]int{a, b, p}
+			if a == ghostVertex { // 保持无穷远点位于v[2]
+				v = [3]int{b, p, ghostVertex}
+			} else if b == ghostVertex {
+				v = [3]int{p, a, ghostVertex}
+			}
+			created = append(created, v)
+			regions = append(regions, tri.region)
 		}
 	}
 
-	// 第四步：添加剩余的三角形
-	triAns = append(triAns, triTemp...)
+	// 空腔不跨越区域边界，新三角形继承其所在边界边原三角形的区域
+	for i, idx := range t.replaceTriangles(cavity, created) {
+		t.tris[idx].region = regions[i]
+	}
+}
+
+// replaceTriangles 用一组新三角形替换已用当前stamp标记的区域，新三角形必须恰好覆盖该区域，返回新三角形的下标
+func (t *triangulation) replaceTriangles(removed []int, created [][3]int) []int {
+	type outside struct{ idx, a, b int }
 
-	// 第五步：移除包含超级三角形顶点的三角形
-	finalTriangles := []Triangle{}
-	for _, triangle := range triAns {
-		keep := true
-		for _, vertex := range triangle.P {
-			if vertex.At(0, 0) < minPoint.At(0, 0) || vertex.At(1, 0) < minPoint.At(1, 0) ||
-				vertex.At(0, 0) > maxPoint.At(0, 0) || vertex.At(1, 0) > maxPoint.At(1, 0) {
-				keep = false
-				break
+	boundary := make(map[[2]int]outside, len(removed)+2)
+	for _, idx := range removed {
+		tri := t.tris[idx]
+		for e := 0; e < 3; e++ {
+			if t.tris[tri.n[e]].mark != t.stamp {
+				a, b := tri.v[(e+1)%3], tri.v[(e+2)%3]
+				boundary[[2]int{a, b}] = outside{tri.n[e], b, a}
 			}
 		}
-		if keep {
-			finalTriangles = append(finalTriangles, triangle)
+	}
+	for _, idx := range removed {
+		t.deleteTriangle(idx)
+	}
+
+	ids := make([]int, 0, len(created))
+	for _, v := range created {
+		idx := t.newTriangle(v)
+		for e := 0; e < 3; e++ {
+			if out, ok := boundary[[2]int{v[(e+1)%3], v[(e+2)%3]}]; ok {
+				t.tris[idx].n[e] = out.idx
+				t.replaceNeighbor(out.idx, out.a, out.b, idx)
+			}
 		}
+		ids = append(ids, idx)
 	}
+	t.linkTriangles(ids)
+	t.last = ids[0]
+	return ids
+}
 
-	return finalTriangles
+// isConstrained 判断边ab是否为约束边
+func (t *triangulation) isConstrained(a, b int) bool {
+	_, ok := t.constraints[edgeKey(a, b)]
+	return ok
+}
+
+// edgeKey 返回与方向无关的边键
+func edgeKey(a, b int) [2]int {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int{a, b}
+}
+
+// replaceNeighbor 将三角形idx中边a->b另一侧的邻接三角形设为nb
+func (t *triangulation) replaceNeighbor(idx, a, b, nb int) {
+	v := t.tris[idx].v
+	for e := 0; e < 3; e++ {
+		if v[(e+1)%3] == a && v[(e+2)%3] == b {
+			t.tris[idx].n[e] = nb
+			return
+		}
+	}
+}
+
+// finiteTriangles 返回所有有限三角形
+func (t *triangulation) finiteTriangles() []Triangle {
+	res := make([]Triangle, 0, len(t.tris))
+	for _, tri := range t.tris {
+		if tri.dead || tri.v[2] == ghostVertex {
+			continue
+		}
+		res = append(res, Triangle{[3]*mat.VecDense{t.points[tri.v[0]], t.points[tri.v[1]], t.points[tri.v[2]]}})
+	}
+	return res
 }
 
 // circumcircle 计算三点外接圆的圆心和半径
@@ -134,41 +375,6 @@ func circumcircle(a, b, c *mat.VecDense) (center *mat.VecDense, radius float64)
 	return center, radius
 }
 
-// removeDuplicateEdges 移除重复的边
-func removeDuplicateEdges(edges []Edge) []Edge {
-	// 排序边
-	sort.Slice(edges, func(i, j int) bool {
-		if edges[i][0].At(0, 0) != edges[j][0].At(0, 0) {
-			return edges[i][0].At(0, 0) < edges[j][0].At(0, 0)
-		}
-		if edges[i][0].At(1, 0) != edges[j][0].At(1, 0) {
-			return edges[i][0].At(1, 0) < edges[j][0].At(1, 0)
-		}
-		if edges[i][1].At(0, 0) != edges[j][1].At(0, 0) {
-			return edges[i][1].At(0, 0) < edges[j][1].At(0, 0)
-		}
-		return edges[i][1].At(1, 0) < edges[j][1].At(1, 0)
-	})
-
-	// 移除重复的边
-	result := []Edge{}
-	for i := 0; i < len(edges); i++ {
-		if i < len(edges)-1 && edgesEqual(edges[i], edges[i+1]) {
-			i++ // 跳过下一个边（重复的）
-			continue
-		}
-		result = append(result, edges[i])
-	}
-
-	return result
-}
-
-// edgesEqual 检查两条边是否相等
-func edgesEqual(e1, e2 Edge) bool {
-	return (pointsEqual(e1[0], e2[0]) && pointsEqual(e1[1], e2[1])) ||
-		(pointsEqual(e1[0], e2[1]) && pointsEqual(e1[1], e2[0]))
-}
-
 // pointsEqual 检查两个点是否相等
 func pointsEqual(p1, p2 *mat.VecDense) bool {
 	return p1.At(0, 0) == p2.At(0, 0) && p1.At(1, 0) == p2.At(1, 0)
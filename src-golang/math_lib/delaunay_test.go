@@ -0,0 +1,68 @@
+package math_lib
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestOrient2DNearlyCollinear(t *testing.T) {
+	a, b := vec3(0, 0, 0), vec3(1, 1, 0)
+	if s := Orient2D(a, b, vec3(0.1, 0.1, 0)); s != 0 {
+		t.Errorf("point on the line: got %g", s)
+	}
+	if s := Orient2D(a, b, vec3(0.1, math.Nextafter(0.1, 1), 0)); s <= 0 {
+		t.Errorf("point just above the line: got %g", s)
+	}
+	if s := Orient2D(a, b, vec3(0.1, math.Nextafter(0.1, 0), 0)); s >= 0 {
+		t.Errorf("point just below the line: got %g", s)
+	}
+}
+
+// checkDelaunay 检查三角形均为逆时针、外接圆内不含其他点，并且三角形数满足 2n - 2 - h
+func checkDelaunay(t *testing.T, points []*mat.VecDense, tris []Triangle, hull int) {
+	t.Helper()
+	if want := 2*len(points) - 2 - hull; len(tris) != want {
+		t.Errorf("got %d triangles, want %d", len(tris), want)
+	}
+	for _, tri := range tris {
+		if Orient2D(tri.P[0], tri.P[1], tri.P[2]) <= 0 {
+			t.Fatalf("triangle %v is not counterclockwise", tri)
+		}
+		for _, p := range points {
+			if InCircle(tri.P[0], tri.P[1], tri.P[2], p) > 0 {
+				t.Fatalf("point %v lies inside the circumcircle of %v", p.RawVector().Data, tri)
+			}
+		}
+	}
+}
+
+func TestDelaunayCocircularGrid(t *testing.T) {
+	var points []*mat.VecDense
+	for i := 0; i < 6; i++ {
+		for j := 0; j < 6; j++ {
+			points = append(points, vec3(float64(i), float64(j), 0))
+		}
+	}
+	tris := Delaunay(points)
+	checkDelaunay(t, points, tris, 20)
+
+	area := 0.0
+	for _, tri := range tris {
+		area += Orient2D(tri.P[0], tri.P[1], tri.P[2]) / 2
+	}
+	if math.Abs(area-25) > 1e-12 {
+		t.Errorf("triangles cover area %g, want 25", area)
+	}
+}
+
+func TestDelaunayRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	points := []*mat.VecDense{vec3(-1, -1, 0), vec3(2, -1, 0), vec3(2, 2, 0), vec3(-1, 2, 0)}
+	for i := 0; i < 300; i++ {
+		points = append(points, vec3(rng.Float64(), rng.Float64(), 0))
+	}
+	checkDelaunay(t, points, Delaunay(points), 4)
+}
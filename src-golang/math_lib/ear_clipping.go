@@ -1,57 +1,552 @@
 package math_lib
 
 import (
+	"fmt"
 	"gonum.org/v1/gonum/mat"
+	"math"
+	"sort"
 )
 
-// isEar 检查三角形是否是"耳朵"
-func isEar(a, b, c *mat.VecDense, polygon []*mat.VecDense) bool {
-	if Cross2D(a, b, c) < 0 { // 如果不是凸顶点
-		return false
+// hashThreshold 顶点数超过该值时使用z-order曲线索引加速耳朵检测
+const hashThreshold = 80
+
+// earNode 耳切法中的多边形顶点，prev/next为多边形环上的相邻顶点，prevZ/nextZ为按z-order排序的相邻顶点
+type earNode struct {
+	i            int // 顶点在earClipper.vertices中的下标
+	x, y         float64
+	z            uint32
+	prev, next   *earNode
+	prevZ, nextZ *earNode
+	steiner      bool // 只有一个点的洞，不参与共线点过滤
+}
+
+// earClipper 耳切法的状态，minX/minY/invSize用于计算z-order，invSize为0时不使用索引
+type earClipper struct {
+	vertices   []*mat.VecDense
+	triangles  []Triangle
+	minX, minY float64
+	invSize    float64
+	useHash    bool
+}
+
+// EarClippingTriangulation 执行耳切法三角剖分，自动识别外边界与洞的绕向，洞通过桥接边并入外边界
+// 使用z-order曲线索引检测耳朵，只使用x/y分量，返回的三角形逆时针排列；多边形退化、洞不在外边界内或无法找到耳朵时返回错误
+func EarClippingTriangulation(polygon []*mat.VecDense, holes ...[]*mat.VecDense) ([]Triangle, error) {
+	if len(polygon) < 3 {
+		return nil, fmt.Errorf("ear clipping: polygon needs at least 3 vertices, got %d", len(polygon))
 	}
 
-	for _, p := range polygon { // 检查是否有其他顶点在三角形内
-		if (p.At(0, 0) == a.At(0, 0) && p.At(1, 0) == a.At(1, 0)) ||
-			(p.At(0, 0) == b.At(0, 0) && p.At(1, 0) == b.At(1, 0)) ||
-			(p.At(0, 0) == c.At(0, 0) && p.At(1, 0) == c.At(1, 0)) {
+	c := &earClipper{}
+	for _, ring := range append([][]*mat.VecDense{polygon}, holes...) {
+		for _, p := range ring {
+			if math.IsNaN(p.AtVec(0)) || math.IsInf(p.AtVec(0), 0) || math.IsNaN(p.AtVec(1)) || math.IsInf(p.AtVec(1), 0) {
+				return nil, fmt.Errorf("ear clipping: invalid vertex %v", FormatVec(p))
+			}
+		}
+	}
+
+	outer := c.linkedList(polygon, true)
+	if outer == nil || outer.next == outer.prev {
+		return nil, fmt.Errorf("ear clipping: degenerate polygon")
+	}
+	if ringArea(polygon) == 0 {
+		return nil, fmt.Errorf("ear clipping: polygon has zero area")
+	}
+
+	if len(holes) > 0 {
+		var err error
+		if outer, err = c.eliminateHoles(holes, outer); err != nil {
+			return nil, err
+		}
+	}
+
+	// 顶点较多时计算包围盒，用于z-order索引
+	if len(c.vertices) > hashThreshold {
+		c.useHash = true
+		c.minX, c.minY = math.Inf(1), math.Inf(1)
+		maxX, maxY := math.Inf(-1), math.Inf(-1)
+		for _, p := range c.vertices {
+			c.minX, c.minY = math.Min(c.minX, p.AtVec(0)), math.Min(c.minY, p.AtVec(1))
+			maxX, maxY = math.Max(maxX, p.AtVec(0)), math.Max(maxY, p.AtVec(1))
+		}
+		if size := math.Max(maxX-c.minX, maxY-c.minY); size != 0 {
+			c.invSize = 32767 / size
+		}
+	}
+
+	if err := c.clip(outer, 0); err != nil {
+		return nil, err
+	}
+	return c.triangles, nil
+}
+
+// ringArea 计算环在xy平面上的有向面积，逆时针为正
+func ringArea(ring []*mat.VecDense) float64 {
+	sum := 0.0
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		sum += p.AtVec(0)*q.AtVec(1) - q.AtVec(0)*p.AtVec(1)
+	}
+	return sum / 2
+}
+
+// linkedList 将环转换为双向循环链表，ccw为true时链表逆时针排列，否则顺时针排列
+func (c *earClipper) linkedList(ring []*mat.VecDense, ccw bool) *earNode {
+	start := len(c.vertices)
+	c.vertices = append(c.vertices, ring...)
+
+	var last *earNode
+	if ccw == (ringArea(ring) > 0) {
+		for i := range ring {
+			last = insertEarNode(start+i, ring[i], last)
+		}
+	} else {
+		for i := len(ring) - 1; i >= 0; i-- {
+			last = insertEarNode(start+i, ring[i], last)
+		}
+	}
+
+	if last != nil && nodesEqual(last, last.next) {
+		removeEarNode(last)
+		last = last.next
+	}
+	return last
+}
+
+// clip 逐个切除耳朵；找不到耳朵时依次尝试过滤退化顶点、修复局部自交、沿对角线分割多边形
+func (c *earClipper) clip(ear *earNode, pass int) error {
+	if ear == nil {
+		return nil
+	}
+	if pass == 0 && c.useHash {
+		c.indexCurve(ear)
+	}
+
+	stop := ear
+	for ear.prev != ear.next {
+		prev, next := ear.prev, ear.next
+		if c.isEar(ear) {
+			c.addTriangle(prev, ear, next)
+			removeEarNode(ear)
+
+			// 跳过下一个顶点可以减少狭长三角形
+			ear, stop = next.next, next.next
+			continue
+		}
+
+		ear = next
+		if ear != stop {
 			continue
 		}
 
-		if Cross2D(a, b, p) > 0 && // 如果在三角形内
-			Cross2D(b, c, p) > 0 &&
-			Cross2D(c, a, p) > 0 {
+		switch pass {
+		case 0:
+			return c.clip(filterEarNodes(ear, nil), 1)
+		case 1:
+			return c.clip(c.cureLocalIntersections(filterEarNodes(ear, nil)), 2)
+		default:
+			return c.splitClip(ear)
+		}
+	}
+	return nil
+}
+
+// addTriangle 记录一个三角形
+func (c *earClipper) addTriangle(a, b, d *earNode) {
+	c.triangles = append(c.triangles, Triangle{[3]*mat.VecDense{c.vertices[a.i], c.vertices[b.i], c.vertices[d.i]}})
+}
+
+// isEar 判断顶点是否为耳朵: 凸顶点，且以它和两个相邻顶点组成的三角形内没有其他凹顶点
+func (c *earClipper) isEar(ear *earNode) bool {
+	a, b, d := ear.prev, ear, ear.next
+	if earCross(a, b, d) <= 0 {
+		return false
+	}
+
+	x0, x1 := math.Min(a.x, math.Min(b.x, d.x)), math.Max(a.x, math.Max(b.x, d.x))
+	y0, y1 := math.Min(a.y, math.Min(b.y, d.y)), math.Max(a.y, math.Max(b.y, d.y))
+	blocks := func(p *earNode) bool {
+		return p != a && p != d && p.x >= x0 && p.x <= x1 && p.y >= y0 && p.y <= y1 &&
+			pointInEarTriangle(a, b, d, p) && earCross(p.prev, p, p.next) <= 0
+	}
+
+	if !c.useHash {
+		for p := d.next; p != a; p = p.next {
+			if blocks(p) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// 只检查z-order落在三角形包围盒范围内的顶点，向两个方向同时搜索
+	minZ, maxZ := c.zOrder(x0, y0), c.zOrder(x1, y1)
+	p, n := ear.prevZ, ear.nextZ
+	for p != nil && p.z >= minZ && n != nil && n.z <= maxZ {
+		if blocks(p) || blocks(n) {
+			return false
+		}
+		p, n = p.prevZ, n.nextZ
+	}
+	for ; p != nil && p.z >= minZ; p = p.prevZ {
+		if blocks(p) {
+			return false
+		}
+	}
+	for ; n != nil && n.z <= maxZ; n = n.nextZ {
+		if blocks(n) {
 			return false
 		}
 	}
 	return true
 }
 
-// EarClippingTriangulation 执行耳切法三角剖分
-func EarClippingTriangulation(polygon []*mat.VecDense) []Triangle {
-	triangleSet := make([]Triangle, 0)
-	poly := make([]*mat.VecDense, len(polygon))
-	copy(poly, polygon)
+// cureLocalIntersections 边a-p与p.next-b相交时切除三角形(a, p, b)，消除局部自交
+func (c *earClipper) cureLocalIntersections(start *earNode) *earNode {
+	if start == nil {
+		return nil
+	}
+
+	p := start
+	for {
+		a, b := p.prev, p.next.next
+		if !nodesEqual(a, b) && segmentsIntersect(a, p, p.next, b) && locallyInside(a, b) && locallyInside(b, a) {
+			c.addTriangle(a, p, b)
+			removeEarNode(p)
+			removeEarNode(p.next)
+			p, start = b, b
+		}
+		p = p.next
+		if p == start || p.next == p {
+			break
+		}
+	}
+	return filterEarNodes(p, nil)
+}
+
+// splitClip 找到一条有效对角线将多边形分为两部分，分别进行耳切
+func (c *earClipper) splitClip(start *earNode) error {
+	a := start
+	for {
+		for b := a.next.next; b != a.prev; b = b.next {
+			if a.i != b.i && isValidDiagonal(a, b) {
+				other := splitEarPolygon(a, b)
+				a = filterEarNodes(a, a.next)
+				other = filterEarNodes(other, other.next)
+				if err := c.clip(a, 0); err != nil {
+					return err
+				}
+				return c.clip(other, 0)
+			}
+		}
+		a = a.next
+		if a == start {
+			return fmt.Errorf("ear clipping: no ear or diagonal found, polygon may be self-intersecting")
+		}
+	}
+}
+
+// eliminateHoles 按最左顶点的x坐标从小到大依次将洞桥接到外边界上
+func (c *earClipper) eliminateHoles(holes [][]*mat.VecDense, outer *earNode) (*earNode, error) {
+	type hole struct {
+		index    int
+		leftmost *earNode
+	}
+
+	queue := make([]hole, 0, len(holes))
+	for k, ring := range holes {
+		list := c.linkedList(ring, false)
+		if list == nil {
+			continue
+		}
+		if list == list.next {
+			list.steiner = true
+		}
+		queue = append(queue, hole{k, leftmostEarNode(list)})
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].leftmost.x < queue[j].leftmost.x
+	})
+
+	for _, h := range queue {
+		bridge := findHoleBridge(h.leftmost, outer)
+		if bridge == nil {
+			return nil, fmt.Errorf("ear clipping: hole %d is not inside the polygon", h.index)
+		}
 
-	for len(poly) >= 3 {
-		n := len(poly)
+		// 桥接后过滤切口附近的共线顶点
+		reverse := splitEarPolygon(bridge, h.leftmost)
+		filterEarNodes(reverse, reverse.next)
+		outer = filterEarNodes(bridge, bridge.next)
+	}
+	return outer, nil
+}
 
-		for i := 0; i < n; i++ {
-			a := (i + n - 1) % n
-			c := (i + 1) % n
+// findHoleBridge 从洞的最左顶点向左发射射线，找到外边界上可以与之直接相连的顶点 (Eberly)
+func findHoleBridge(hole, outer *earNode) *earNode {
+	hx, hy := hole.x, hole.y
+	qx := math.Inf(-1)
+	var m *earNode
 
-			if len(poly) == 3 || isEar(poly[a], poly[i], poly[c], poly) {
-				// 创建三角形
-				tri := Triangle{
-					[3]*mat.VecDense{poly[a], poly[i], poly[c]},
+	// 找到射线相交的最近一条边，取其x较小的端点作为候选；恰好交于顶点时直接返回该顶点
+	p := outer
+	for {
+		if nodesEqual(hole, p) {
+			return p
+		}
+		if hy <= p.y && hy >= p.next.y && p.next.y != p.y {
+			x := p.x + (hy-p.y)*(p.next.x-p.x)/(p.next.y-p.y)
+			if x <= hx && x > qx {
+				qx = x
+				m = p
+				if p.next.x < p.x {
+					m = p.next
+				}
+				if x == hx {
+					return m
 				}
-				triangleSet = append(triangleSet, tri)
+			}
+		}
+		p = p.next
+		if p == outer {
+			break
+		}
+	}
+	if m == nil {
+		return nil
+	}
 
-				// 移除当前顶点
-				poly = append(poly[:i], poly[i+1:]...)
-				n--
-				i-- // 调整索引
+	// 洞顶点、交点与候选点组成的三角形内若有其他顶点，选择与射线夹角最小的顶点
+	stop, mx, my := m, m.x, m.y
+	tanMin := math.Inf(1)
+	a, b := &earNode{x: hx, y: hy}, &earNode{x: qx, y: hy}
+	if hy < my {
+		a, b = b, a
+	}
+	p = m
+	for {
+		if hx >= p.x && p.x >= mx && hx != p.x && pointInEarTriangle(b, &earNode{x: mx, y: my}, a, p) {
+			tan := math.Abs(hy-p.y) / (hx - p.x)
+			if locallyInside(p, hole) && (tan < tanMin || (tan == tanMin && (p.x > m.x || (p.x == m.x && sectorContainsSector(m, p))))) {
+				m, tanMin = p, tan
 			}
 		}
+		p = p.next
+		if p == stop {
+			return m
+		}
+	}
+}
+
+// sectorContainsSector 判断顶点m处的扇形是否包含顶点p处的扇形
+func sectorContainsSector(m, p *earNode) bool {
+	return earCross(m.prev, m, p.prev) > 0 && earCross(p.next, m, m.next) > 0
+}
+
+// leftmostEarNode 返回环上最左(x相同时y最小)的顶点
+func leftmostEarNode(start *earNode) *earNode {
+	leftmost := start
+	for p := start.next; p != start; p = p.next {
+		if p.x < leftmost.x || (p.x == leftmost.x && p.y < leftmost.y) {
+			leftmost = p
+		}
+	}
+	return leftmost
+}
+
+// indexCurve 计算每个顶点的z-order并按其排序建立prevZ/nextZ链表
+func (c *earClipper) indexCurve(start *earNode) {
+	var nodes []*earNode
+	p := start
+	for {
+		p.z = c.zOrder(p.x, p.y)
+		nodes = append(nodes, p)
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].z < nodes[j].z
+	})
+
+	for i, n := range nodes {
+		n.prevZ, n.nextZ = nil, nil
+		if i > 0 {
+			n.prevZ = nodes[i-1]
+		}
+		if i+1 < len(nodes) {
+			n.nextZ = nodes[i+1]
+		}
+	}
+}
+
+// zOrder 将坐标映射到15位整数网格后交错其二进制位，得到z-order曲线上的位置
+func (c *earClipper) zOrder(x, y float64) uint32 {
+	spread := func(v uint32) uint32 {
+		v = (v | v<<8) & 0x00FF00FF
+		v = (v | v<<4) & 0x0F0F0F0F
+		v = (v | v<<2) & 0x33333333
+		return (v | v<<1) & 0x55555555
+	}
+	return spread(uint32((x-c.minX)*c.invSize)) | spread(uint32((y-c.minY)*c.invSize))<<1
+}
+
+// filterEarNodes 移除start到end之间重复和共线的顶点，end为nil时检查整个环
+func filterEarNodes(start, end *earNode) *earNode {
+	if start == nil {
+		return nil
+	}
+	if end == nil {
+		end = start
+	}
+
+	p := start
+	for {
+		again := false
+		if !p.steiner && (nodesEqual(p, p.next) || earCross(p.prev, p, p.next) == 0) {
+			removeEarNode(p)
+			p, end = p.prev, p.prev
+			if p == p.next {
+				break
+			}
+			again = true
+		} else {
+			p = p.next
+		}
+		if !again && p == end {
+			break
+		}
+	}
+	return end
+}
+
+// isValidDiagonal 判断ab是否为多边形内部且不与其他边相交的对角线
+func isValidDiagonal(a, b *earNode) bool {
+	if a.next.i == b.i || a.prev.i == b.i || intersectsPolygon(a, b) {
+		return false
+	}
+	if locallyInside(a, b) && locallyInside(b, a) && middleInside(a, b) &&
+		(earCross(a.prev, a, b.prev) != 0 || earCross(a, b.prev, b) != 0) { // 不产生方向相反的扇形
+		return true
+	}
+	return nodesEqual(a, b) && earCross(a.prev, a, a.next) < 0 && earCross(b.prev, b, b.next) < 0 // 长度为零的对角线
+}
+
+// segmentsIntersect 判断线段p1q1与p2q2是否相交(含端点接触与共线重叠)
+func segmentsIntersect(p1, q1, p2, q2 *earNode) bool {
+	o1, o2 := sign(earCross(p1, q1, p2)), sign(earCross(p1, q1, q2))
+	o3, o4 := sign(earCross(p2, q2, p1)), sign(earCross(p2, q2, q1))
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+	return (o1 == 0 && inSegmentBox(p1, p2, q1)) || (o2 == 0 && inSegmentBox(p1, q2, q1)) ||
+		(o3 == 0 && inSegmentBox(p2, p1, q2)) || (o4 == 0 && inSegmentBox(p2, q1, q2))
+}
+
+// sign 返回数值的符号
+func sign(v float64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// inSegmentBox 判断与pr共线的点q是否位于线段pr上
+func inSegmentBox(p, q, r *earNode) bool {
+	return q.x <= math.Max(p.x, r.x) && q.x >= math.Min(p.x, r.x) && q.y <= math.Max(p.y, r.y) && q.y >= math.Min(p.y, r.y)
+}
+
+// intersectsPolygon 判断线段ab是否与多边形中不以a、b为端点的边相交
+func intersectsPolygon(a, b *earNode) bool {
+	p := a
+	for {
+		if p.i != a.i && p.next.i != a.i && p.i != b.i && p.next.i != b.i && segmentsIntersect(p, p.next, a, b) {
+			return true
+		}
+		p = p.next
+		if p == a {
+			return false
+		}
 	}
-	return triangleSet
+}
+
+// locallyInside 判断对角线ab在顶点a附近是否位于多边形内部
+func locallyInside(a, b *earNode) bool {
+	if earCross(a.prev, a, a.next) > 0 {
+		return earCross(a, b, a.next) <= 0 && earCross(a, a.prev, b) <= 0
+	}
+	return earCross(a, b, a.prev) > 0 || earCross(a, a.next, b) > 0
+}
+
+// middleInside 判断对角线ab的中点是否位于多边形内部
+func middleInside(a, b *earNode) bool {
+	px, py := (a.x+b.x)/2, (a.y+b.y)/2
+	inside := false
+	p := a
+	for {
+		if (p.y > py) != (p.next.y > py) && p.next.y != p.y && px < (p.next.x-p.x)*(py-p.y)/(p.next.y-p.y)+p.x {
+			inside = !inside
+		}
+		p = p.next
+		if p == a {
+			return inside
+		}
+	}
+}
+
+// splitEarPolygon 沿对角线ab将环分成两个环，a、b各复制一份，返回包含b的复制的另一个环
+func splitEarPolygon(a, b *earNode) *earNode {
+	a2 := &earNode{i: a.i, x: a.x, y: a.y}
+	b2 := &earNode{i: b.i, x: b.x, y: b.y}
+	an, bp := a.next, b.prev
+
+	a.next, b.prev = b, a
+	a2.next, an.prev = an, a2
+	b2.next, a2.prev = a2, b2
+	bp.next, b2.prev = b2, bp
+	return b2
+}
+
+// insertEarNode 在last之后插入顶点，last为nil时新建一个环
+func insertEarNode(i int, p *mat.VecDense, last *earNode) *earNode {
+	n := &earNode{i: i, x: p.AtVec(0), y: p.AtVec(1)}
+	if last == nil {
+		n.prev, n.next = n, n
+	} else {
+		n.next, n.prev = last.next, last
+		last.next.prev = n
+		last.next = n
+	}
+	return n
+}
+
+// removeEarNode 从环和z-order链表中移除顶点
+func removeEarNode(p *earNode) {
+	p.next.prev = p.prev
+	p.prev.next = p.next
+	if p.prevZ != nil {
+		p.prevZ.nextZ = p.nextZ
+	}
+	if p.nextZ != nil {
+		p.nextZ.prevZ = p.prevZ
+	}
+}
+
+// nodesEqual 判断两个顶点坐标是否相同
+func nodesEqual(a, b *earNode) bool {
+	return a.x == b.x && a.y == b.y
+}
+
+// earCross 计算(b-a)×(d-a)，正值表示abd逆时针
+func earCross(a, b, d *earNode) float64 {
+	return (b.x-a.x)*(d.y-a.y) - (b.y-a.y)*(d.x-a.x)
+}
+
+// pointInEarTriangle 判断点p是否在逆时针三角形abd内(含边界)
+func pointInEarTriangle(a, b, d, p *earNode) bool {
+	return earCross(a, b, p) >= 0 && earCross(b, d, p) >= 0 && earCross(d, a, p) >= 0
 }
@@ -0,0 +1,65 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// circleRing 返回圆心(cx, cy)、半径在r与r·ratio之间交替的星形环，clockwise为true时顺时针排列
+func circleRing(cx, cy, r, ratio float64, n int, clockwise bool) []*mat.VecDense {
+	ring := make([]*mat.VecDense, n)
+	for i := range ring {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		if clockwise {
+			a = -a
+		}
+		radius := r
+		if i%2 == 1 {
+			radius *= ratio
+		}
+		ring[i] = vec3(cx+radius*math.Cos(a), cy+radius*math.Sin(a), 0)
+	}
+	return ring
+}
+
+func TestEarClippingWithHoles(t *testing.T) {
+	// 顶点数超过z-order索引的阈值，外边界顺时针、洞逆时针，绕向由函数自动识别
+	outer := circleRing(0, 0, 10, 0.8, 200, true)
+	holes := [][]*mat.VecDense{circleRing(-3, 0, 2, 1, 16, false), circleRing(3, 1, 1.5, 0.7, 10, false)}
+	tris, err := EarClippingTriangulation(outer, holes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := 200 + 16 + 10 + 2*len(holes) - 2; len(tris) != want {
+		t.Errorf("got %d triangles, want %d", len(tris), want)
+	}
+	want := math.Abs(polygonArea2D(outer)) - math.Abs(polygonArea2D(holes[0])) - math.Abs(polygonArea2D(holes[1]))
+	area := 0.0
+	for _, tri := range tris {
+		a := Orient2D(tri.P[0], tri.P[1], tri.P[2]) / 2
+		if a <= 0 {
+			t.Fatalf("triangle %v is not counterclockwise", tri)
+		}
+		area += a
+	}
+	if math.Abs(area-want) > 1e-9*want {
+		t.Errorf("area %g, want %g", area, want)
+	}
+}
+
+func TestEarClippingErrors(t *testing.T) {
+	if _, err := EarClippingTriangulation([]*mat.VecDense{vec3(0, 0, 0), vec3(1, 0, 0)}); err == nil {
+		t.Error("two vertices: expected an error")
+	}
+	if _, err := EarClippingTriangulation([]*mat.VecDense{vec3(0, 0, 0), vec3(1, 0, 0), vec3(2, 0, 0)}); err == nil {
+		t.Error("collinear polygon: expected an error")
+	}
+	square := []*mat.VecDense{vec3(0, 0, 0), vec3(1, 0, 0), vec3(1, 1, 0), vec3(0, 1, 0)}
+	outside := []*mat.VecDense{vec3(5, 5, 0), vec3(6, 5, 0), vec3(6, 6, 0)}
+	if _, err := EarClippingTriangulation(square, outside); err == nil {
+		t.Error("hole outside the polygon: expected an error")
+	}
+}
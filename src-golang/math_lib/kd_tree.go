@@ -0,0 +1,123 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"sort"
+)
+
+// kdTree 三维点集的静态k-d树，节点按中位数划分，nodes[i]为第i层划分后位于中间位置的点
+type kdTree struct {
+	points []*mat.VecDense
+	nodes  []kdNode
+	root   int
+}
+
+// kdNode k-d树的节点，left/right为-1时表示没有子树
+type kdNode struct {
+	point       int
+	axis        int
+	left, right int
+}
+
+// newKDTree 构造k-d树，每层选择范围最大的坐标轴进行划分
+func newKDTree(points []*mat.VecDense) *kdTree {
+	t := &kdTree{points: points, nodes: make([]kdNode, 0, len(points))}
+	ids := make([]int, len(points))
+	for i := range ids {
+		ids[i] = i
+	}
+	t.root = t.build(ids)
+	return t
+}
+
+// build 递归构造子树，返回子树根节点的下标
+func (t *kdTree) build(ids []int) int {
+	if len(ids) == 0 {
+		return -1
+	}
+
+	axis, spread := 0, -1.0
+	for d := 0; d < 3; d++ {
+		lo, hi := t.points[ids[0]].AtVec(d), t.points[ids[0]].AtVec(d)
+		for _, i := range ids[1:] {
+			lo, hi = min(lo, t.points[i].AtVec(d)), max(hi, t.points[i].AtVec(d))
+		}
+		if hi-lo > spread {
+			axis, spread = d, hi-lo
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return t.points[ids[i]].AtVec(axis) < t.points[ids[j]].AtVec(axis)
+	})
+
+	mid := len(ids) / 2
+	idx := len(t.nodes)
+	t.nodes = append(t.nodes, kdNode{point: ids[mid], axis: axis})
+	left := t.build(ids[:mid])
+	right := t.build(ids[mid+1:])
+	t.nodes[idx].left, t.nodes[idx].right = left, right
+	return idx
+}
+
+// nearest 返回离p最近的k个点的下标及距离的平方，按距离从小到大排列
+func (t *kdTree) nearest(p *mat.VecDense, k int) ([]int, []float64) {
+	ids := make([]int, 0, k+1)
+	dists := make([]float64, 0, k+1)
+
+	var search func(n int)
+	search = func(n int) {
+		if n < 0 {
+			return
+		}
+		node := t.nodes[n]
+		q := t.points[node.point]
+
+		d := squaredDistance(p, q)
+		if len(ids) < k || d < dists[len(dists)-1] {
+			pos := sort.SearchFloat64s(dists, d)
+			ids = append(ids[:pos], append([]int{node.point}, ids[pos:]...)...)
+			dists = append(dists[:pos], append([]float64{d}, dists[pos:]...)...)
+			if len(ids) > k {
+				ids, dists = ids[:k], dists[:k]
+			}
+		}
+
+		diff := p.AtVec(node.axis) - q.AtVec(node.axis)
+		near, far := node.left, node.right
+		if diff > 0 {
+			near, far = far, near
+		}
+		search(near)
+		if len(ids) < k || diff*diff < dists[len(dists)-1] {
+			search(far)
+		}
+	}
+	search(t.root)
+	return ids, dists
+}
+
+// withinRadius 返回与p的距离不超过r的所有点的下标
+func (t *kdTree) withinRadius(p *mat.VecDense, r float64) []int {
+	var res []int
+	var search func(n int)
+	search = func(n int) {
+		if n < 0 {
+			return
+		}
+		node := t.nodes[n]
+		q := t.points[node.point]
+		if squaredDistance(p, q) <= r*r {
+			res = append(res, node.point)
+		}
+
+		diff := p.AtVec(node.axis) - q.AtVec(node.axis)
+		if diff <= r {
+			search(node.left)
+		}
+		if diff >= -r {
+			search(node.right)
+		}
+	}
+	search(t.root)
+	return res
+}
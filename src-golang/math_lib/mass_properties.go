@@ -0,0 +1,283 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"sort"
+)
+
+// maxBoxCandidates OrientedBoundingBox最多尝试的凸包面法向量数量
+const maxBoxCandidates = 256
+
+// MassProperties 封闭网格所围实体的质量属性
+type MassProperties struct {
+	Volume   float64       // 体积，法向量朝内时为负
+	Area     float64       // 表面积
+	Mass     float64       // 质量 = 密度×体积
+	Centroid *mat.VecDense // 质心
+	Inertia  *mat.SymDense // 关于质心的惯性张量
+}
+
+// OrientedBox 有向包围盒，Axes为三个互相正交的单位向量，盒子为 Center + Σ t_i·Axes[i], |t_i| ≤ HalfExtents[i]
+type OrientedBox struct {
+	Center      *mat.VecDense
+	Axes        [3]*mat.VecDense
+	HalfExtents [3]float64
+}
+
+// Volume 用散度定理计算封闭网格所围的有向体积，法向量朝外时为正
+func Volume(tris []*Triangle) float64 {
+	volume := 0.0
+	for _, tri := range tris {
+		volume += mat.Dot(tri.P[0], Cross2(tri.P[1], tri.P[2])) / 6
+	}
+	return volume
+}
+
+// SurfaceArea 计算网格的表面积
+func SurfaceArea(tris []*Triangle) float64 {
+	area := 0.0
+	for _, tri := range tris {
+		area += triangleArea(tri)
+	}
+	return area
+}
+
+// ComputeMassProperties 计算密度均匀的封闭网格的体积、表面积、质量、质心与关于质心的惯性张量
+// 每个三角形与参考点组成一个有向四面体，四面体的二阶矩为 ∫xxᵀdV = V/20·(Σpᵢpᵢᵀ + (Σpᵢ)(Σpᵢ)ᵀ)，
+// 参考点取包围盒中心以减小抵消误差
+func ComputeMassProperties(tris []*Triangle, density float64) MassProperties {
+	res := MassProperties{Area: SurfaceArea(tris), Centroid: mat.NewVecDense(3, nil), Inertia: mat.NewSymDense(3, nil)}
+	if len(tris) == 0 {
+		return res
+	}
+	lo, hi := BoundingBox(tris)
+	origin := AddVec(mat.NewVecDense(3, nil), lo, hi)
+	origin.ScaleVec(0.5, origin)
+
+	first := mat.NewVecDense(3, nil)  // Σ V·(四面体质心)
+	second := mat.NewSymDense(3, nil) // Σ ∫xxᵀdV
+	p := [3]*mat.VecDense{mat.NewVecDense(3, nil), mat.NewVecDense(3, nil), mat.NewVecDense(3, nil)}
+	for _, tri := range tris {
+		for i := range p {
+			p[i].SubVec(tri.P[i], origin)
+		}
+		v := mat.Dot(p[0], Cross2(p[1], p[2])) / 6
+		sum := AddVecs(mat.NewVecDense(3, nil), p[0], p[1], p[2])
+
+		res.Volume += v
+		first.AddScaledVec(first, v/4, sum)
+		for i := 0; i < 3; i++ {
+			for j := i; j < 3; j++ {
+				s := sum.AtVec(i) * sum.AtVec(j)
+				for _, q := range p {
+					s += q.AtVec(i) * q.AtVec(j)
+				}
+				second.SetSym(i, j, second.At(i, j)+v/20*s)
+			}
+		}
+	}
+	if res.Volume == 0 {
+		return res
+	}
+
+	// 将二阶矩平移到质心，惯性张量 I = tr(C)·E - C
+	c := ScaleVec2(1/res.Volume, first)
+	res.Centroid = AddVec(mat.NewVecDense(3, nil), c, origin)
+	res.Mass = density * res.Volume
+	trace := 0.0
+	for i := 0; i < 3; i++ {
+		trace += second.At(i, i) - res.Volume*c.AtVec(i)*c.AtVec(i)
+	}
+	for i := 0; i < 3; i++ {
+		for j := i; j < 3; j++ {
+			moment := second.At(i, j) - res.Volume*c.AtVec(i)*c.AtVec(j)
+			value := -moment
+			if i == j {
+				value += trace
+			}
+			res.Inertia.SetSym(i, j, density*value)
+		}
+	}
+	return res
+}
+
+// BoundingBox 计算网格的轴对齐包围盒，网格为空时返回两个零向量
+func BoundingBox(tris []*Triangle) (lo, hi *mat.VecDense) {
+	if len(tris) == 0 {
+		return mat.NewVecDense(3, nil), mat.NewVecDense(3, nil)
+	}
+	lo, hi = mat.VecDenseCopyOf(tris[0].P[0]), mat.VecDenseCopyOf(tris[0].P[0])
+	for _, tri := range tris {
+		for _, p := range tri.P {
+			lo, hi = MinVec(lo, p), MaxVec(hi, p)
+		}
+	}
+	return lo, hi
+}
+
+// OrientedBoundingBox 计算网格的有向包围盒
+// 候选方向取凸包各面(面数很多时取面积最大的maxBoxCandidates个)的法向量与凸包表面协方差矩阵的主轴，在垂直于候选方向的平面内求面积最小的外接矩形(其一边必与二维凸包的某条边平行)，
+// 返回体积最小的盒子；顶点共面或共线时退化为厚度为零的盒子
+func OrientedBoundingBox(tris []*Triangle) OrientedBox {
+	var points []*mat.VecDense
+	for _, tri := range tris {
+		points = append(points, tri.P[:]...)
+	}
+	hull := ConvexHull(points)
+	if len(hull) == 0 {
+		return axisAlignedBox(tris)
+	}
+
+	// 面数很多时只取面积最大的若干个面的法向量
+	order := make([]int, len(hull))
+	areas := make([]float64, len(hull))
+	for i := range hull {
+		order[i], areas[i] = i, triangleArea(&hull[i])
+	}
+	sort.Slice(order, func(a, b int) bool { return areas[order[a]] > areas[order[b]] })
+
+	var candidates []*mat.VecDense
+	seen := make(map[[3]float64]bool)
+	for _, i := range order {
+		if len(candidates) == maxBoxCandidates {
+			break
+		}
+		n := hull[i].GetNormal()
+		key := [3]float64{math.Abs(n.AtVec(0)), math.Abs(n.AtVec(1)), math.Abs(n.AtVec(2))}
+		if !seen[key] {
+			seen[key] = true
+			candidates = append(candidates, n)
+		}
+	}
+	candidates = append(candidates, surfaceAxes(hull)...)
+
+	var hullPoints []*mat.VecDense
+	for i := range hull {
+		hullPoints = append(hullPoints, hull[i].P[:]...)
+	}
+	hullPoints = uniquePoints3D(hullPoints)
+	var best OrientedBox
+	bestVolume := math.Inf(1)
+	for _, n := range candidates {
+		box := boxAroundAxis(hullPoints, n)
+		if volume := box.HalfExtents[0] * box.HalfExtents[1] * box.HalfExtents[2]; volume < bestVolume {
+			best, bestVolume = box, volume
+		}
+	}
+	return best
+}
+
+// axisAlignedBox 以轴对齐包围盒表示的OrientedBox
+func axisAlignedBox(tris []*Triangle) OrientedBox {
+	lo, hi := BoundingBox(tris)
+	box := OrientedBox{Center: AddVec(mat.NewVecDense(3, nil), lo, hi)}
+	box.Center.ScaleVec(0.5, box.Center)
+	for d := 0; d < 3; d++ {
+		box.Axes[d] = mat.NewVecDense(3, nil)
+		box.Axes[d].SetVec(d, 1)
+		box.HalfExtents[d] = (hi.AtVec(d) - lo.AtVec(d)) / 2
+	}
+	return box
+}
+
+// surfaceAxes 返回三角网格表面(按面积均匀分布)协方差矩阵的三个特征向量
+func surfaceAxes(tris []Triangle) []*mat.VecDense {
+	mean := mat.NewVecDense(3, nil)
+	total := 0.0
+	for i := range tris {
+		area := triangleArea(&tris[i])
+		total += area
+		mean.AddScaledVec(mean, area/3, AddVecs(mat.NewVecDense(3, nil), tris[i].P[:]...))
+	}
+	if total == 0 {
+		return nil
+	}
+	mean.ScaleVec(1/total, mean)
+
+	// 三角形上均匀分布的二阶矩为 A/12·(Σpᵢpᵢᵀ + (Σpᵢ)(Σpᵢ)ᵀ)
+	cov := mat.NewSymDense(3, nil)
+	p := [3]*mat.VecDense{mat.NewVecDense(3, nil), mat.NewVecDense(3, nil), mat.NewVecDense(3, nil)}
+	for i := range tris {
+		area := triangleArea(&tris[i])
+		for k := range p {
+			p[k].SubVec(tris[i].P[k], mean)
+		}
+		sum := AddVecs(mat.NewVecDense(3, nil), p[0], p[1], p[2])
+		for a := 0; a < 3; a++ {
+			for b := a; b < 3; b++ {
+				s := sum.AtVec(a) * sum.AtVec(b)
+				for _, q := range p {
+					s += q.AtVec(a) * q.AtVec(b)
+				}
+				cov.SetSym(a, b, cov.At(a, b)+area/12*s)
+			}
+		}
+	}
+
+	var eig mat.EigenSym
+	if !eig.Factorize(cov, true) {
+		return nil
+	}
+	var vectors mat.Dense
+	eig.VectorsTo(&vectors)
+	res := make([]*mat.VecDense, 3)
+	for k := range res {
+		res[k] = mat.VecDenseCopyOf(vectors.ColView(k))
+	}
+	return res
+}
+
+// boxAroundAxis 以单位向量n为一个轴，在垂直平面内用最小面积外接矩形确定另两个轴
+func boxAroundAxis(points []*mat.VecDense, n *mat.VecDense) OrientedBox {
+	frame := newPlaneFrame(mat.NewVecDense(3, nil), n)
+	projected := make([]*mat.VecDense, len(points))
+	for i, p := range points {
+		projected[i] = frame.project(p)
+	}
+	polygon := ConvexHull2D(projected)
+
+	// 外接矩形的方向由二维凸包的某条边决定
+	bestArea := math.Inf(1)
+	var ux, uy float64 = 1, 0
+	for i := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+		dx, dy := q.AtVec(0)-polygon[i].AtVec(0), q.AtVec(1)-polygon[i].AtVec(1)
+		length := math.Hypot(dx, dy)
+		if length == 0 {
+			continue
+		}
+		dx, dy = dx/length, dy/length
+		loA, hiA, loB, hiB := rectangleExtents(polygon, dx, dy)
+		if area := (hiA - loA) * (hiB - loB); area < bestArea {
+			bestArea, ux, uy = area, dx, dy
+		}
+	}
+
+	axisA := AddVec(mat.NewVecDense(3, nil), ScaleVec2(ux, frame.u), ScaleVec2(uy, frame.v))
+	axisB := Cross2(n, axisA)
+	box := OrientedBox{Axes: [3]*mat.VecDense{axisA, axisB, mat.VecDenseCopyOf(n)}}
+	box.Center = mat.NewVecDense(3, nil)
+	for k, axis := range box.Axes {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, p := range points {
+			d := mat.Dot(p, axis)
+			lo, hi = math.Min(lo, d), math.Max(hi, d)
+		}
+		box.HalfExtents[k] = (hi - lo) / 2
+		box.Center.AddScaledVec(box.Center, (hi+lo)/2, axis)
+	}
+	return box
+}
+
+// rectangleExtents 返回多边形在方向(dx, dy)及其法向上的投影范围
+func rectangleExtents(polygon []*mat.VecDense, dx, dy float64) (loA, hiA, loB, hiB float64) {
+	loA, hiA, loB, hiB = math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	for _, p := range polygon {
+		a := p.AtVec(0)*dx + p.AtVec(1)*dy
+		b := -p.AtVec(0)*dy + p.AtVec(1)*dx
+		loA, hiA = math.Min(loA, a), math.Max(hiA, a)
+		loB, hiB = math.Min(loB, b), math.Max(hiB, b)
+	}
+	return loA, hiA, loB, hiB
+}
@@ -0,0 +1,49 @@
+package math_lib
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestComputeMassProperties(t *testing.T) {
+	p := ComputeMassProperties(boxMesh(1, 1, 1, 2, 3, 4), 2)
+	if math.Abs(p.Volume-6) > 1e-12 || math.Abs(p.Area-22) > 1e-12 || math.Abs(p.Mass-12) > 1e-12 {
+		t.Errorf("volume %g, area %g, mass %g", p.Volume, p.Area, p.Mass)
+	}
+	if !sameVec(p.Centroid, vec3(1.5, 2, 2.5), 1e-12) {
+		t.Errorf("centroid %v", p.Centroid.RawVector().Data)
+	}
+	// 长方体关于质心的惯性矩 I_xx = m(b²+c²)/12
+	want := mat.NewSymDense(3, []float64{13, 0, 0, 0, 10, 0, 0, 0, 5})
+	if !mat.EqualApprox(p.Inertia, want, 1e-9) {
+		t.Errorf("inertia %v", mat.Formatted(p.Inertia))
+	}
+}
+
+func TestOrientedBoundingBox(t *testing.T) {
+	rotation := RotateMatrix(vec3(1, 2, 3), vec3(0, 0, 0), 0.7)
+	var tris []*Triangle
+	for _, tri := range boxMesh(-0.5, -1, -1.5, 0.5, 1, 1.5) {
+		tris = append(tris, &Triangle{[3]*mat.VecDense{TransformPoint(rotation, tri.P[0]), TransformPoint(rotation, tri.P[1]), TransformPoint(rotation, tri.P[2])}})
+	}
+
+	box := OrientedBoundingBox(tris)
+	extents := box.HalfExtents[:]
+	sort.Float64s(extents)
+	if math.Abs(extents[0]-0.5) > 1e-9 || math.Abs(extents[1]-1) > 1e-9 || math.Abs(extents[2]-1.5) > 1e-9 {
+		t.Errorf("half extents %v", box.HalfExtents)
+	}
+	if !sameVec(box.Center, vec3(0, 0, 0), 1e-9) {
+		t.Errorf("center %v", box.Center.RawVector().Data)
+	}
+
+	lo, hi := BoundingBox(tris)
+	for i := 0; i < 3; i++ {
+		if hi.AtVec(i)-lo.AtVec(i) < 2*extents[0] {
+			t.Errorf("axis-aligned box thinner than the oriented box")
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// Mesh 顶点共享的三角网格，Faces中每个面按逆时针顺序保存三个顶点在Vertices中的下标
+type Mesh struct {
+	Vertices []*mat.VecDense
+	Faces    [][3]int
+}
+
+// NewMesh 由三角形列表建立网格，坐标完全相同的顶点合并为一个
+func NewMesh(tris []*Triangle) *Mesh {
+	m := &Mesh{Faces: make([][3]int, 0, len(tris))}
+	index := make(map[[3]float64]int, len(tris))
+	for _, tri := range tris {
+		var face [3]int
+		for i, p := range tri.P {
+			key := [3]float64{p.AtVec(0), p.AtVec(1), p.AtVec(2)}
+			id, ok := index[key]
+			if !ok {
+				id = len(m.Vertices)
+				index[key] = id
+				m.Vertices = append(m.Vertices, mat.VecDenseCopyOf(p))
+			}
+			face[i] = id
+		}
+		m.Faces = append(m.Faces, face)
+	}
+	return m
+}
+
+// Triangles 将网格转换为三角形列表，相邻三角形共享同一个顶点向量
+func (m *Mesh) Triangles() []*Triangle {
+	res := make([]*Triangle, len(m.Faces))
+	for i, f := range m.Faces {
+		res[i] = &Triangle{[3]*mat.VecDense{m.Vertices[f[0]], m.Vertices[f[1]], m.Vertices[f[2]]}}
+	}
+	return res
+}
+
+// BoundaryEdges 返回只属于一个面的边，方向与所在面的顶点顺序一致；封闭网格没有边界边
+func (m *Mesh) BoundaryEdges() [][2]int {
+	count := make(map[[2]int]int, 3*len(m.Faces))
+	for _, f := range m.Faces {
+		for e := 0; e < 3; e++ {
+			count[edgeKey(f[e], f[(e+1)%3])]++
+		}
+	}
+
+	var res [][2]int
+	for _, f := range m.Faces {
+		for e := 0; e < 3; e++ {
+			if count[edgeKey(f[e], f[(e+1)%3])] == 1 {
+				res = append(res, [2]int{f[e], f[(e+1)%3]})
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,786 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// BooleanOp 网格布尔运算的类型
+type BooleanOp int
+
+const (
+	BooleanUnion        BooleanOp = iota // 并集 A∪B
+	BooleanIntersection                  // 交集 A∩B
+	BooleanDifference                    // 差集 A-B
+)
+
+// booleanMargin 面内交点的重心坐标与边界保持的最小距离，避免舍入误差使交点落到三角形外
+const booleanMargin = 1e-12
+
+// booleanSnap 交点到边的端点或另一个网格的顶点的相对距离小于该值时，取端点或顶点的坐标
+const booleanSnap = 1e-9
+
+// booleanRay 判断点是否在网格内部时使用的射线方向，取与坐标轴都不平行的方向
+var booleanRay = [3]float64{0.5772156649015329, 0.3183098861837907, 0.7518398074789774}
+
+// meshBoolean 网格布尔运算的状态，两个网格的顶点与面合并保存，A在前B在后
+// 所有拓扑判断使用精确谓词，并将B整体平移无穷小量εδ，δ=(1, ε, ε²)，
+// 使共面、点在面上、边与边相交等退化情况都得到一致的判断结果
+type meshBoolean struct {
+	points     []*mat.VecDense // 原始顶点与交点
+	canonical  []int           // 坐标相同的点合并后的代表点
+	faces      [][3]int        // 去除退化面后的原始面
+	faceB      int             // 第一个属于B的面
+	crossings  map[crossingKey]int
+	edgeCross  map[[4]int]int // 共面的两条边的交点
+	edgePoints map[[2]int][]edgePoint
+	segments   [][][2]int // 每个面上的交线段，端点为交点下标
+}
+
+// crossingKey 一个网格的边与另一个网格的面的交点
+type crossingKey struct {
+	edge [2]int
+	face int
+}
+
+// edgePoint 边上的交点，t为从较小下标端点出发的参数
+type edgePoint struct {
+	id int
+	t  float64
+}
+
+// booleanPiece 分割后的三角形及其所属网格
+type booleanPiece struct {
+	v     [3]int
+	fromB bool
+}
+
+// MeshBoolean 计算两个封闭定向三角网格的布尔运算，结果中两网格的交线处共享顶点
+// 输入网格需封闭且法向量指向外侧；两网格的面精确共面时互相压印对方的边，按B沿(1, ε, ε²)方向平移无穷小量取舍后，
+// 重叠而方向相反的部分成对删除，结果不含零厚度的薄片；仅在舍入误差意义下共面的面按一般位置处理
+func MeshBoolean(a, b []*Triangle, op BooleanOp) []*Triangle {
+	s := newMeshBoolean(NewMesh(a), NewMesh(b))
+	s.intersect()
+	s.weld()
+	pieces, cuts := s.split()
+	return s.assemble(pieces, cuts, op)
+}
+
+// newMeshBoolean 合并两个网格并去除退化面
+func newMeshBoolean(a, b *Mesh) *meshBoolean {
+	s := &meshBoolean{
+		points:     append(append([]*mat.VecDense{}, a.Vertices...), b.Vertices...),
+		crossings:  make(map[crossingKey]int),
+		edgeCross:  make(map[[4]int]int),
+		edgePoints: make(map[[2]int][]edgePoint),
+	}
+	for i, m := range []*Mesh{a, b} {
+		if i == 1 {
+			s.faceB = len(s.faces)
+		}
+		offset := i * len(a.Vertices)
+		for _, f := range m.Faces {
+			f = [3]int{f[0] + offset, f[1] + offset, f[2] + offset}
+			if f[0] != f[1] && f[1] != f[2] && f[2] != f[0] && !collinear3D(s.points[f[0]], s.points[f[1]], s.points[f[2]]) {
+				s.faces = append(s.faces, f)
+			}
+		}
+	}
+	s.segments = make([][][2]int, len(s.faces))
+	return s
+}
+
+// faceBox 面的包围盒
+func (s *meshBoolean) faceBox(f int) aabb {
+	return pointsBox(s.points[s.faces[f][0]], s.points[s.faces[f][1]], s.points[s.faces[f][2]])
+}
+
+// intersect 用包围盒树找出A与B中可能相交的面对，计算每对面的交线段
+func (s *meshBoolean) intersect() {
+	boxes := make([]aabb, len(s.faces)-s.faceB)
+	for i := range boxes {
+		boxes[i] = s.faceBox(s.faceB + i)
+	}
+	tree := newBoxTree(boxes)
+	for fa := 0; fa < s.faceB; fa++ {
+		tree.query(s.faceBox(fa), func(i int) {
+			s.intersectPair(fa, s.faceB+i)
+		})
+	}
+}
+
+// intersectPair 计算A的面fa与B的面fb的交线段: 在扰动下两个三角形处于一般位置，
+// 一个三角形的边穿过另一个三角形的交点恰好有0个或2个
+func (s *meshBoolean) intersectPair(fa, fb int) {
+	if s.coplanar(fa, fb) {
+		// 重合的面互相压印对方的边，使两侧按相同的线分割，重叠部分之后成对删除
+		s.imprint(fa, fb)
+		s.imprint(fb, fa)
+		return
+	}
+	if !s.straddles(fa, fb) || !s.straddles(fb, fa) {
+		return
+	}
+
+	var ids []int
+	for _, pair := range [2][2]int{{fa, fb}, {fb, fa}} {
+		f, g := pair[0], pair[1]
+		for e := 0; e < 3; e++ {
+			u, v := s.faces[f][e], s.faces[f][(e+1)%3]
+			if s.edgeCrossesFace(u, v, g) {
+				ids = append(ids, s.crossing(u, v, g))
+			}
+		}
+	}
+	if len(ids) == 2 {
+		seg := [2]int{ids[0], ids[1]}
+		s.segments[fa] = append(s.segments[fa], seg)
+		s.segments[fb] = append(s.segments[fb], seg)
+	}
+}
+
+// straddles 判断面f的顶点是否分布在面g所在平面的两侧
+func (s *meshBoolean) straddles(f, g int) bool {
+	first := s.planeSide(g, s.faces[f][0])
+	return s.planeSide(g, s.faces[f][1]) != first || s.planeSide(g, s.faces[f][2]) != first
+}
+
+// planeSide 另一个网格的顶点v位于面f所在平面的哪一侧
+func (s *meshBoolean) planeSide(f, v int) int {
+	shift := 1
+	if f >= s.faceB {
+		shift = -1
+	}
+	face := s.faces[f]
+	return perturbedPlaneSide(s.points[face[0]], s.points[face[1]], s.points[face[2]], s.points[v], shift)
+}
+
+// edgeCrossesFace 判断边uv是否穿过另一个网格的面f
+func (s *meshBoolean) edgeCrossesFace(u, v, f int) bool {
+	if s.planeSide(f, u) == s.planeSide(f, v) {
+		return false
+	}
+	shift := -1
+	if f >= s.faceB {
+		shift = 1
+	}
+	face := s.faces[f]
+	p, q := s.points[u], s.points[v]
+	first := perturbedEdgeSide(p, q, s.points[face[0]], s.points[face[1]], shift)
+	return perturbedEdgeSide(p, q, s.points[face[1]], s.points[face[2]], shift) == first &&
+		perturbedEdgeSide(p, q, s.points[face[2]], s.points[face[0]], shift) == first
+}
+
+// crossing 返回边uv与面f的交点，同一交点只计算一次，使共享该边的面得到同一个顶点
+func (s *meshBoolean) crossing(u, v, f int) int {
+	key := crossingKey{edgeKey(u, v), f}
+	if id, ok := s.crossings[key]; ok {
+		return id
+	}
+
+	face := s.faces[f]
+	a, b, c := s.points[face[0]], s.points[face[1]], s.points[face[2]]
+	p, q := s.points[key.edge[0]], s.points[key.edge[1]]
+	dp, dq := Orient3D(a, b, c, p), Orient3D(a, b, c, q)
+	t := 0.5
+	if dp != dq {
+		t = math.Max(0, math.Min(1, dp/(dp-dq)))
+	}
+	if t < booleanSnap {
+		t = 0
+	} else if t > 1-booleanSnap {
+		t = 1
+	}
+
+	x := interpolateEdge(p, q, t)
+
+	// 交点与面的顶点几乎重合时(边穿过另一个网格的顶点)取该顶点的坐标，避免留下面积接近零的三角形
+	tolerance := booleanSnap * booleanSnap * squaredDistance(p, q)
+	for _, w := range face {
+		if squaredDistance(x, s.points[w]) <= tolerance {
+			x.CopyVec(s.points[w])
+			break
+		}
+	}
+
+	id := len(s.points)
+	s.points = append(s.points, x)
+	s.crossings[key] = id
+	s.edgePoints[key.edge] = append(s.edgePoints[key.edge], edgePoint{id, t})
+	return id
+}
+
+// interpolateEdge 返回线段pq上参数为t的点，从较近的端点插值，使t为0或1时与端点坐标完全相同
+func interpolateEdge(p, q *mat.VecDense, t float64) *mat.VecDense {
+	x := mat.NewVecDense(3, nil)
+	if t < 0.5 {
+		x.SubVec(q, p)
+		x.AddScaledVec(p, t, x)
+	} else {
+		x.SubVec(p, q)
+		x.AddScaledVec(q, 1-t, x)
+	}
+	return x
+}
+
+// coplanar 判断面g的三个顶点是否都精确位于面f所在平面上
+func (s *meshBoolean) coplanar(f, g int) bool {
+	a, b, c := s.points[s.faces[f][0]], s.points[s.faces[f][1]], s.points[s.faces[f][2]]
+	for _, v := range s.faces[g] {
+		if Orient3D(a, b, c, s.points[v]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// imprint 将与面f共面的另一个网格的面g的边裁剪到f内，作为f上的交线段
+// 落在f的边上的点加入该边的交点，裁剪后沿f的边的部分不作为交线段
+func (s *meshBoolean) imprint(f, g int) {
+	face := s.faces[f]
+	i, j := dominantAxes(s.points[face[0]], s.points[face[1]], s.points[face[2]])
+	var corners [3]*mat.VecDense
+	for k, v := range face {
+		corners[k] = project2D(s.points[v], i, j)
+	}
+	orientation := sign(Orient2D(corners[0], corners[1], corners[2]))
+
+	// event 边与面f的闭包的交集上的点，edges标记该点所在的f的边
+	type event struct {
+		id    int
+		t     float64
+		edges [3]bool
+	}
+	for e := 0; e < 3; e++ {
+		u, v := s.faces[g][e], s.faces[g][(e+1)%3]
+		p, q := project2D(s.points[u], i, j), project2D(s.points[v], i, j)
+		var events []event
+
+		for n, x := range [2]*mat.VecDense{p, q} {
+			ev := event{id: [2]int{u, v}[n], t: float64(n)}
+			inside, on, count := true, 0, 0
+			for k := 0; k < 3; k++ {
+				switch orientation * sign(Orient2D(corners[k], corners[(k+1)%3], x)) {
+				case -1:
+					inside = false
+				case 0:
+					ev.edges[k] = true
+					on, count = k, count+1
+				}
+			}
+			if !inside {
+				continue
+			}
+			// 只在一条边上时位于该边内部，在两条边上时与f的顶点重合
+			if count == 1 {
+				s.addEdgePoint(face[on], face[(on+1)%3], ev.id)
+			}
+			events = append(events, ev)
+		}
+
+		d := SubVec(mat.NewVecDense(2, nil), q, p)
+		length := mat.Dot(d, d)
+		for k, c := range corners {
+			if Orient2D(p, q, c) != 0 {
+				continue
+			}
+			t := mat.Dot(SubVec(mat.NewVecDense(2, nil), c, p), d) / length
+			if t > 0 && t < 1 {
+				ev := event{id: face[k], t: t}
+				ev.edges[k], ev.edges[(k+2)%3] = true, true
+				events = append(events, ev)
+			}
+		}
+
+		for k := 0; k < 3; k++ {
+			c0, c1 := corners[k], corners[(k+1)%3]
+			o1, o2 := Orient2D(p, q, c0), Orient2D(p, q, c1)
+			o3, o4 := Orient2D(c0, c1, p), Orient2D(c0, c1, q)
+			if sign(o1)*sign(o2) >= 0 || sign(o3)*sign(o4) >= 0 {
+				continue
+			}
+			ev := event{id: s.edgeCrossing(face[k], face[(k+1)%3], o1/(o1-o2), u, v), t: o3 / (o3 - o4)}
+			ev.edges[k] = true
+			events = append(events, ev)
+		}
+
+		for a := 1; a < len(events); a++ {
+			for b := a; b > 0 && events[b].t < events[b-1].t; b-- {
+				events[b], events[b-1] = events[b-1], events[b]
+			}
+		}
+	next:
+		for a := 1; a < len(events); a++ {
+			from, to := events[a-1], events[a]
+			if from.id == to.id {
+				continue
+			}
+			for k := 0; k < 3; k++ {
+				if from.edges[k] && to.edges[k] {
+					continue next
+				}
+			}
+			seg := [2]int{from.id, to.id}
+			for _, other := range s.segments[f] {
+				if other == seg || other == [2]int{seg[1], seg[0]} {
+					continue next
+				}
+			}
+			s.segments[f] = append(s.segments[f], seg)
+		}
+	}
+}
+
+// edgeCrossing 返回共面的边ab与边uv的交点，t为交点在ab上从a出发的参数
+func (s *meshBoolean) edgeCrossing(a, b int, t float64, u, v int) int {
+	ka, kb := edgeKey(a, b), edgeKey(u, v)
+	key := [4]int{ka[0], ka[1], kb[0], kb[1]}
+	if kb[0] < ka[0] {
+		key = [4]int{kb[0], kb[1], ka[0], ka[1]}
+	}
+	if id, ok := s.edgeCross[key]; ok {
+		return id
+	}
+
+	p, q := s.points[a], s.points[b]
+	x := interpolateEdge(p, q, t)
+	tolerance := booleanSnap * booleanSnap * squaredDistance(p, q)
+	for _, w := range [4]int{a, b, u, v} {
+		if squaredDistance(x, s.points[w]) <= tolerance {
+			x.CopyVec(s.points[w])
+			break
+		}
+	}
+
+	id := len(s.points)
+	s.points = append(s.points, x)
+	s.edgeCross[key] = id
+	s.addEdgePoint(a, b, id)
+	s.addEdgePoint(u, v, id)
+	return id
+}
+
+// addEdgePoint 将位于边uv上的点id加入该边的交点，参数t按投影计算
+func (s *meshBoolean) addEdgePoint(u, v, id int) {
+	key := edgeKey(u, v)
+	for _, ep := range s.edgePoints[key] {
+		if ep.id == id {
+			return
+		}
+	}
+	p, q := s.points[key[0]], s.points[key[1]]
+	d := SubVec(mat.NewVecDense(3, nil), q, p)
+	t := mat.Dot(SubVec(mat.NewVecDense(3, nil), s.points[id], p), d) / mat.Dot(d, d)
+	s.edgePoints[key] = append(s.edgePoints[key], edgePoint{id, t})
+}
+
+// weld 为坐标完全相同的点指定同一个代表点
+func (s *meshBoolean) weld() {
+	index := make(map[[3]float64]int, len(s.points))
+	s.canonical = make([]int, len(s.points))
+	for i, p := range s.points {
+		key := [3]float64{p.AtVec(0), p.AtVec(1), p.AtVec(2)}
+		if c, ok := index[key]; ok {
+			s.canonical[i] = c
+		} else {
+			index[key] = i
+			s.canonical[i] = i
+		}
+	}
+}
+
+// split 沿交线段分割所有面，返回分割后的三角形与交线(作为区域边界的边)
+func (s *meshBoolean) split() ([]booleanPiece, map[[2]int]bool) {
+	var pieces []booleanPiece
+	cuts := make(map[[2]int]bool)
+	for f, face := range s.faces {
+		fromB := f >= s.faceB
+		if len(s.segments[f]) == 0 && !s.hasEdgePoints(f) {
+			v := [3]int{s.canonical[face[0]], s.canonical[face[1]], s.canonical[face[2]]}
+			pieces = append(pieces, booleanPiece{v, fromB})
+			continue
+		}
+		for _, seg := range s.segments[f] {
+			if a, b := s.canonical[seg[0]], s.canonical[seg[1]]; a != b {
+				cuts[edgeKey(a, b)] = true
+			}
+		}
+		for _, v := range s.splitFace(f) {
+			pieces = append(pieces, booleanPiece{v, fromB})
+		}
+	}
+	return pieces, cuts
+}
+
+// hasEdgePoints 判断面f的边上是否有交点，与另一个网格的面共面相接时边上可能有交点而面内没有交线段
+func (s *meshBoolean) hasEdgePoints(f int) bool {
+	for e := 0; e < 3; e++ {
+		if len(s.edgePoints[edgeKey(s.faces[f][e], s.faces[f][(e+1)%3])]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// splitFace 在面f内以交线段为约束做约束Delaunay三角剖分
+// 以重心坐标为平面坐标，三个顶点映射到(0,0)、(1,0)、(0,1)，边上的交点严格位于边上，面内的交点严格位于三角形内
+func (s *meshBoolean) splitFace(f int) [][3]int {
+	face := s.faces[f]
+	p0, p1, p2 := s.points[face[0]], s.points[face[1]], s.points[face[2]]
+	corners := [3][2]float64{{0, 0}, {1, 0}, {0, 1}}
+
+	planar := make(map[int]*mat.VecDense)
+	ids := make(map[*mat.VecDense]int)
+	place := func(id int, x, y float64) *mat.VecDense {
+		c := s.canonical[id]
+		if p, ok := planar[c]; ok {
+			return p
+		}
+		p := mat.NewVecDense(3, []float64{x, y, 0})
+		planar[c], ids[p] = p, c
+		return p
+	}
+
+	var ring []*mat.VecDense
+	for e := 0; e < 3; e++ {
+		u, v := face[e], face[(e+1)%3]
+		from, to := corners[e], corners[(e+1)%3]
+		ring = append(ring, place(u, from[0], from[1]))
+		for _, ep := range s.pointsAlong(u, v) {
+			// 坐标相同的交点只在环上出现一次
+			if p := place(ep.id, from[0]+ep.t*(to[0]-from[0]), from[1]+ep.t*(to[1]-from[1])); p != ring[len(ring)-1] {
+				ring = append(ring, p)
+			}
+		}
+	}
+	for len(ring) > 3 && ring[len(ring)-1] == ring[0] {
+		ring = ring[:len(ring)-1]
+	}
+
+	barycentric := newBarycentric(p0, p1, p2)
+	var segments []Edge
+	for _, seg := range s.segments[f] {
+		var ends [2]*mat.VecDense
+		for i, id := range seg {
+			if p, ok := planar[s.canonical[id]]; ok {
+				ends[i] = p
+				continue
+			}
+			b1, b2 := barycentric(s.points[id])
+			b1, b2 = math.Max(b1, booleanMargin), math.Max(b2, booleanMargin)
+			if sum := b1 + b2; sum > 1-booleanMargin {
+				b1, b2 = b1*(1-booleanMargin)/sum, b2*(1-booleanMargin)/sum
+			}
+			ends[i] = place(id, b1, b2)
+		}
+		if ends[0] != ends[1] {
+			segments = append(segments, Edge{ends[0], ends[1]})
+		}
+	}
+
+	var res [][3]int
+	for _, tri := range ConstrainedDelaunay([][]*mat.VecDense{ring}, nil, segments) {
+		var v [3]int
+		for i, p := range tri.P {
+			id, ok := ids[p]
+			if !ok {
+				// 约束相交时新增的点，按重心坐标还原三维坐标
+				x := mat.VecDenseCopyOf(p0)
+				x.AddScaledVec(x, p.AtVec(0), SubVec(mat.NewVecDense(3, nil), p1, p0))
+				x.AddScaledVec(x, p.AtVec(1), SubVec(mat.NewVecDense(3, nil), p2, p0))
+				id = len(s.points)
+				s.points = append(s.points, x)
+				s.canonical = append(s.canonical, id)
+				ids[p] = id
+			}
+			v[i] = id
+		}
+		if v[0] != v[1] && v[1] != v[2] && v[2] != v[0] {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// pointsAlong 返回边uv上的交点，按从u到v的顺序排列，t为从u出发的参数
+func (s *meshBoolean) pointsAlong(u, v int) []edgePoint {
+	points := s.edgePoints[edgeKey(u, v)]
+	res := make([]edgePoint, len(points))
+	for i, ep := range points {
+		if u > v {
+			ep.t = 1 - ep.t
+		}
+		res[i] = ep
+	}
+	for i := 1; i < len(res); i++ {
+		for j := i; j > 0 && res[j].t < res[j-1].t; j-- {
+			res[j], res[j-1] = res[j-1], res[j]
+		}
+	}
+	return res
+}
+
+// newBarycentric 返回计算点在三角形p0p1p2所在平面上投影的重心坐标(对应p1、p2的分量)的函数
+// 投影时去掉法向量绝对值最大的坐标分量
+func newBarycentric(p0, p1, p2 *mat.VecDense) func(x *mat.VecDense) (b1, b2 float64) {
+	e1 := SubVec(mat.NewVecDense(3, nil), p1, p0)
+	e2 := SubVec(mat.NewVecDense(3, nil), p2, p0)
+	n := Cross2(e1, e2)
+	k := 0
+	for d := 1; d < 3; d++ {
+		if math.Abs(n.AtVec(d)) > math.Abs(n.AtVec(k)) {
+			k = d
+		}
+	}
+	i, j := (k+1)%3, (k+2)%3
+	cross := func(ax, ay, bx, by float64) float64 { return ax*by - ay*bx }
+	area := cross(e1.AtVec(i), e1.AtVec(j), e2.AtVec(i), e2.AtVec(j))
+	return func(x *mat.VecDense) (float64, float64) {
+		dx, dy := x.AtVec(i)-p0.AtVec(i), x.AtVec(j)-p0.AtVec(j)
+		return cross(dx, dy, e2.AtVec(i), e2.AtVec(j)) / area, cross(e1.AtVec(i), e1.AtVec(j), dx, dy) / area
+	}
+}
+
+// assemble 将分割后的三角形按不跨越交线的边连成区域，判断每个区域是否在另一个网格内部并按运算类型取舍
+func (s *meshBoolean) assemble(pieces []booleanPiece, cuts map[[2]int]bool, op BooleanOp) []*Triangle {
+	parent := make([]int, len(pieces))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	edges := make(map[[2]int][]int, 3*len(pieces))
+	for i, piece := range pieces {
+		for e := 0; e < 3; e++ {
+			key := edgeKey(piece.v[e], piece.v[(e+1)%3])
+			if !cuts[key] {
+				edges[key] = append(edges[key], i)
+			}
+		}
+	}
+	for _, list := range edges {
+		for _, i := range list[1:] {
+			if pieces[i].fromB == pieces[list[0]].fromB {
+				parent[find(i)] = find(list[0])
+			}
+		}
+	}
+
+	// 每个区域取面积最大的三角形的重心判断内外
+	representative := make(map[int]int)
+	areas := make([]float64, len(pieces))
+	for i, piece := range pieces {
+		areas[i] = triangleArea(&Triangle{[3]*mat.VecDense{s.points[piece.v[0]], s.points[piece.v[1]], s.points[piece.v[2]]}})
+		root := find(i)
+		if r, ok := representative[root]; !ok || areas[i] > areas[r] {
+			representative[root] = i
+		}
+	}
+	inside := make(map[int]bool, len(representative))
+	for root, i := range representative {
+		v := pieces[i].v
+		centroid := AddVecs(mat.NewVecDense(3, nil), s.points[v[0]], s.points[v[1]], s.points[v[2]])
+		centroid.ScaleVec(1.0/3, centroid)
+		inside[root] = s.insideOther(centroid, pieces[i].fromB)
+	}
+
+	var kept [][3]int
+	var keptB []bool
+	for i, piece := range pieces {
+		keep, flip := booleanKeep(op, inside[find(i)], piece.fromB)
+		if !keep {
+			continue
+		}
+		v := piece.v
+		if flip {
+			v[1], v[2] = v[2], v[1]
+		}
+		kept = append(kept, v)
+		keptB = append(keptB, piece.fromB)
+	}
+	kept = s.cancelCoplanarOverlaps(kept, keptB)
+
+	// 交点吸附到端点后留下的零面积三角形连同其T形连接一起去除
+	m := &Mesh{Vertices: s.points, Faces: kept}
+	m.removeDegenerateFaces()
+	kept = cancelOppositeFaces(m.Faces)
+
+	vertices := make(map[int]*mat.VecDense)
+	vertex := func(id int) *mat.VecDense {
+		if p, ok := vertices[id]; ok {
+			return p
+		}
+		p := mat.VecDenseCopyOf(s.points[id])
+		vertices[id] = p
+		return p
+	}
+	res := make([]*Triangle, len(kept))
+	for i, v := range kept {
+		res[i] = &Triangle{[3]*mat.VecDense{vertex(v[0]), vertex(v[1]), vertex(v[2])}}
+	}
+	return res
+}
+
+// cancelOppositeFaces 成对删除顶点相同、方向相反的面，这些面来自两个网格重合的表面，围成的体积为零
+func cancelOppositeFaces(faces [][3]int) [][3]int {
+	open := make(map[[3]int][]int)
+	removed := make([]bool, len(faces))
+	for i, v := range faces {
+		opposite := rotateFace([3]int{v[0], v[2], v[1]})
+		if list := open[opposite]; len(list) > 0 {
+			removed[i], removed[list[len(list)-1]] = true, true
+			open[opposite] = list[:len(list)-1]
+			continue
+		}
+		key := rotateFace(v)
+		open[key] = append(open[key], i)
+	}
+
+	res := faces[:0]
+	for i, v := range faces {
+		if !removed[i] {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// cancelCoplanarOverlaps 删除与另一个网格的方向相反的共面三角形重叠的三角形
+// 两个网格的面重合时，重叠部分两侧都会保留下来并围成厚度为零的薄片
+func (s *meshBoolean) cancelCoplanarOverlaps(faces [][3]int, fromB []bool) [][3]int {
+	boxes := make([]aabb, len(faces))
+	normals := make([]*mat.VecDense, len(faces))
+	for i, v := range faces {
+		a, b, c := s.points[v[0]], s.points[v[1]], s.points[v[2]]
+		boxes[i] = pointsBox(a, b, c)
+		normals[i] = Cross2(SubVec(mat.NewVecDense(3, nil), b, a), SubVec(mat.NewVecDense(3, nil), c, a))
+	}
+	tree := newBoxTree(boxes)
+
+	removed := make([]bool, len(faces))
+	for i, v := range faces {
+		if mat.Norm(normals[i], 2) == 0 {
+			continue
+		}
+		centroid := AddVecs(mat.NewVecDense(3, nil), s.points[v[0]], s.points[v[1]], s.points[v[2]])
+		centroid.ScaleVec(1.0/3, centroid)
+		tree.query(boxes[i], func(j int) {
+			n := normals[j]
+			length := mat.Norm(n, 2)
+			if removed[i] || fromB[j] == fromB[i] || length == 0 || mat.Dot(normals[i], n) >= 0 {
+				return
+			}
+			w := faces[j]
+			p0, p1, p2 := s.points[w[0]], s.points[w[1]], s.points[w[2]]
+			// 三个顶点到j所在平面的距离相对j的尺寸可以忽略时认为共面
+			tolerance := booleanSnap * math.Sqrt(length)
+			for _, id := range v {
+				if math.Abs(mat.Dot(n, SubVec(mat.NewVecDense(3, nil), s.points[id], p0)))/length > tolerance {
+					return
+				}
+			}
+			b1, b2 := newBarycentric(p0, p1, p2)(centroid)
+			if b1 >= -booleanSnap && b2 >= -booleanSnap && b1+b2 <= 1+booleanSnap {
+				removed[i] = true
+			}
+		})
+	}
+
+	res := faces[:0]
+	for i, v := range faces {
+		if !removed[i] {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// booleanKeep 根据运算类型决定是否保留某个区域，以及是否需要翻转其方向
+func booleanKeep(op BooleanOp, inside, fromB bool) (keep, flip bool) {
+	switch op {
+	case BooleanIntersection:
+		return inside, false
+	case BooleanDifference:
+		if fromB {
+			return inside, true
+		}
+		return !inside, false
+	default:
+		return !inside, false
+	}
+}
+
+// insideOther 用射线奇偶规则判断点x是否在另一个网格内部，fromB表示x属于B
+// 射线与面的相交判断使用与求交相同的扰动谓词，射线经过顶点或边时也能得到一致的计数
+func (s *meshBoolean) insideOther(x *mat.VecDense, fromB bool) bool {
+	lo, hi := 0, s.faceB
+	planeShift := 1
+	if !fromB {
+		lo, hi = s.faceB, len(s.faces)
+		planeShift = -1
+	}
+
+	box := pointsBox(s.points...)
+	length := 1.0
+	for d := 0; d < 3; d++ {
+		length += 2 * (box.hi[d] - box.lo[d])
+	}
+	far := mat.NewVecDense(3, nil)
+	for d := 0; d < 3; d++ {
+		far.SetVec(d, x.AtVec(d)+length*booleanRay[d])
+	}
+	ray := pointsBox(x, far)
+
+	count := 0
+	for f := lo; f < hi; f++ {
+		if !ray.overlaps(s.faceBox(f)) {
+			continue
+		}
+		a, b, c := s.points[s.faces[f][0]], s.points[s.faces[f][1]], s.points[s.faces[f][2]]
+		if perturbedPlaneSide(a, b, c, x, planeShift) == perturbedPlaneSide(a, b, c, far, planeShift) {
+			continue
+		}
+		first := perturbedEdgeSide(x, far, a, b, -planeShift)
+		if perturbedEdgeSide(x, far, b, c, -planeShift) == first && perturbedEdgeSide(x, far, c, a, -planeShift) == first {
+			count++
+		}
+	}
+	return count%2 == 1
+}
+
+// perturbedPlaneSide 点d相对于平面abc的位置(±1)，d与abc属于不同网格
+// Orient3D为零时取平移扰动的一阶项 s·((b-a)×(c-a))的符号，shift为1表示d被平移，-1表示abc被平移
+func perturbedPlaneSide(a, b, c, d *mat.VecDense, shift int) int {
+	if o := Orient3D(a, b, c, d); o != 0 {
+		return sign(o)
+	}
+	return shift * lexCrossSign(a, b, a, c)
+}
+
+// perturbedEdgeSide 直线pq与rt的相对位置(±1)，两条边属于不同网格
+// Orient3D为零时取平移扰动的一阶项 s·((q-p)×(r-t))的符号，shift为1表示rt被平移，-1表示pq被平移
+func perturbedEdgeSide(p, q, r, t *mat.VecDense, shift int) int {
+	if o := Orient3D(p, q, r, t); o != 0 {
+		return sign(o)
+	}
+	return shift * lexCrossSign(p, q, t, r)
+}
+
+// lexCrossSign 精确计算(p1-p0)×(q1-q0)与方向(1, ε, ε²)点积的符号，即第一个非零分量的符号
+func lexCrossSign(p0, p1, q0, q1 *mat.VecDense) int {
+	u, v := differenceRow(p1, p0), differenceRow(q1, q0)
+	for _, ij := range [3][2]int{{1, 2}, {2, 0}, {0, 1}} {
+		i, j := ij[0], ij[1]
+		c := sumExpansions(mulExpansion(u[i], v[j]), negateExpansion(mulExpansion(u[j], v[i])))
+		if s := sign(expansionEstimate(c)); s != 0 {
+			return s
+		}
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMeshBoolean(t *testing.T) {
+	a := boxMesh(0, 0, 0, 2, 2, 2)
+	b := boxMesh(1.1, 0.5, 0.3, 3.1, 2.5, 2.3)
+	overlap := 0.9 * 1.5 * 1.7
+	for _, c := range []struct {
+		op     BooleanOp
+		volume float64
+	}{
+		{BooleanUnion, 16 - overlap},
+		{BooleanIntersection, overlap},
+		{BooleanDifference, 8 - overlap},
+	} {
+		res := MeshBoolean(a, b, c.op)
+		if v := enclosedVolume(res); math.Abs(v-c.volume) > 1e-9 {
+			t.Errorf("op %d: volume %g, want %g", c.op, v, c.volume)
+		}
+		if n := len(NewMesh(res).BoundaryEdges()); n != 0 {
+			t.Errorf("op %d: %d boundary edges", c.op, n)
+		}
+	}
+}
+
+func TestMeshBooleanDisjoint(t *testing.T) {
+	a := boxMesh(0, 0, 0, 1, 1, 1)
+	b := boxMesh(2, 0, 0, 3, 1, 1)
+	if v := enclosedVolume(MeshBoolean(a, b, BooleanUnion)); math.Abs(v-2) > 1e-12 {
+		t.Errorf("union volume %g, want 2", v)
+	}
+	if res := MeshBoolean(a, b, BooleanIntersection); len(res) != 0 {
+		t.Errorf("intersection has %d triangles", len(res))
+	}
+	if v := enclosedVolume(MeshBoolean(a, b, BooleanDifference)); math.Abs(v-1) > 1e-12 {
+		t.Errorf("difference volume %g, want 1", v)
+	}
+}
+
+func TestMeshBooleanFlush(t *testing.T) {
+	box := boxMesh(0, 0, 0, 2, 2, 2)
+	nan := math.NaN()
+	for _, c := range []struct {
+		name    string
+		a, b    []*Triangle
+		volumes [3]float64 // 并集、交集、差集，NaN表示不检查
+	}{
+		{"flush", box, boxMesh(1, 0, 0, 3, 2, 2), [3]float64{12, 4, 4}},
+		{"inner", box, boxMesh(0.5, 0.5, 0, 1.5, 1.5, 1), [3]float64{8, 1, 7}},
+		{"coplanar", box, boxMesh(1, 1, 0, 3, 3, 2), [3]float64{14, 2, 6}},
+		{"same", box, boxMesh(0, 0, 0, 2, 2, 2), [3]float64{8, 8, 0}},
+		{"touching", box, boxMesh(2, 1, 0.5, 4, 3, 2.5), [3]float64{16, 0, 8}},
+		{"touching-below", box, boxMesh(-2, 1, 0.5, 0, 3, 2.5), [3]float64{16, 0, 8}},
+		{"sphere", unitSphere(4).Triangles(), box, [3]float64{nan, nan, nan}},
+	} {
+		for i, op := range []BooleanOp{BooleanUnion, BooleanIntersection, BooleanDifference} {
+			res := MeshBoolean(c.a, c.b, op)
+			if v := enclosedVolume(res); !math.IsNaN(c.volumes[i]) && math.Abs(v-c.volumes[i]) > 1e-9 {
+				t.Errorf("%s op %d: volume %g, want %g", c.name, op, v, c.volumes[i])
+			}
+			r := NewMesh(res).Validate()
+			if !r.Watertight || r.DegenerateFaces != 0 || r.SelfIntersections != 0 {
+				t.Errorf("%s op %d: watertight %v, %d degenerate faces, %d self-intersections",
+					c.name, op, r.Watertight, r.DegenerateFaces, r.SelfIntersections)
+			}
+		}
+	}
+}
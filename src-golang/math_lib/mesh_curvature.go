@@ -0,0 +1,203 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// MeshCurvature 网格每个顶点的离散曲率，法向量朝外时凸处的平均曲率为正
+type MeshCurvature struct {
+	Mean      []float64       // 平均曲率 H
+	Gaussian  []float64       // 高斯曲率 K
+	K1, K2    []float64       // 主曲率 H ± √(H² - K)，K1 ≥ K2
+	Dir1      []*mat.VecDense // K1对应的主方向
+	Dir2      []*mat.VecDense // K2对应的主方向
+	Normals   []*mat.VecDense // 顶点法向量(相邻面法向量按面积加权)
+	MixedArea []float64       // 顶点的混合Voronoi面积
+}
+
+// LaplaceBeltrami 返回余切权重的拉普拉斯-贝尔特拉米算子L与顶点的混合Voronoi面积A (Meyer et al.)
+// L的非对角元为边所对两角余切之和的一半，对角元使每行之和为零；函数f的离散拉普拉斯为 (Lf)_i / A_i
+func (m *Mesh) LaplaceBeltrami() (*SparseMatrix, []float64) {
+	L := NewSparseMatrix(len(m.Vertices))
+	for _, f := range m.Faces {
+		for k := 0; k < 3; k++ {
+			i, j := f[(k+1)%3], f[(k+2)%3]
+			w := m.cornerCotangent(f, k) / 2
+			L.Add(i, j, w)
+			L.Add(j, i, w)
+			L.Add(i, i, -w)
+			L.Add(j, j, -w)
+		}
+	}
+	return L, m.mixedAreas()
+}
+
+// cornerCotangent 返回面f在第k个顶点处内角的余切，面积为零时返回0
+func (m *Mesh) cornerCotangent(f [3]int, k int) float64 {
+	p := m.Vertices[f[k]]
+	a := SubVec(mat.NewVecDense(3, nil), m.Vertices[f[(k+1)%3]], p)
+	b := SubVec(mat.NewVecDense(3, nil), m.Vertices[f[(k+2)%3]], p)
+	area := mat.Norm(Cross2(a, b), 2)
+	if area == 0 {
+		return 0
+	}
+	return mat.Dot(a, b) / area
+}
+
+// cornerAngle 返回面f在第k个顶点处的内角
+func (m *Mesh) cornerAngle(f [3]int, k int) float64 {
+	p := m.Vertices[f[k]]
+	a := SubVec(mat.NewVecDense(3, nil), m.Vertices[f[(k+1)%3]], p)
+	b := SubVec(mat.NewVecDense(3, nil), m.Vertices[f[(k+2)%3]], p)
+	return math.Atan2(mat.Norm(Cross2(a, b), 2), mat.Dot(a, b))
+}
+
+// mixedAreas 计算每个顶点的混合Voronoi面积：非钝角三角形按Voronoi区域分配，钝角三角形的钝角顶点分得一半面积，其余顶点各分得四分之一
+func (m *Mesh) mixedAreas() []float64 {
+	res := make([]float64, len(m.Vertices))
+	for _, f := range m.Faces {
+		area := triangleArea(&Triangle{[3]*mat.VecDense{m.Vertices[f[0]], m.Vertices[f[1]], m.Vertices[f[2]]}})
+		var cot [3]float64
+		obtuse := -1
+		for k := range cot {
+			cot[k] = m.cornerCotangent(f, k)
+			if cot[k] < 0 {
+				obtuse = k
+			}
+		}
+		for k := 0; k < 3; k++ {
+			switch {
+			case obtuse == k:
+				res[f[k]] += area / 2
+			case obtuse >= 0:
+				res[f[k]] += area / 4
+			default:
+				// 1/8·(|PQ|²·cot R + |PR|²·cot Q)
+				p, q, r := m.Vertices[f[k]], m.Vertices[f[(k+1)%3]], m.Vertices[f[(k+2)%3]]
+				pq := squaredDistance(p, q)
+				pr := squaredDistance(p, r)
+				res[f[k]] += (pq*cot[(k+2)%3] + pr*cot[(k+1)%3]) / 8
+			}
+		}
+	}
+	return res
+}
+
+// Curvature 估计每个顶点的曲率：平均曲率由余切拉普拉斯作用于坐标得到，高斯曲率由角亏得到(边界顶点使用π减内角和)，
+// 主方向由沿相邻边的法曲率最小二乘拟合的曲率张量的特征向量得到
+func (m *Mesh) Curvature() MeshCurvature {
+	n := len(m.Vertices)
+	L, area := m.LaplaceBeltrami()
+	c := MeshCurvature{
+		Mean: make([]float64, n), Gaussian: make([]float64, n), K1: make([]float64, n), K2: make([]float64, n),
+		Dir1: make([]*mat.VecDense, n), Dir2: make([]*mat.VecDense, n), Normals: make([]*mat.VecDense, n), MixedArea: area,
+	}
+
+	angle := make([]float64, n)
+	for i := range c.Normals {
+		c.Normals[i] = mat.NewVecDense(3, nil)
+	}
+	for _, f := range m.Faces {
+		a := SubVec(mat.NewVecDense(3, nil), m.Vertices[f[1]], m.Vertices[f[0]])
+		b := SubVec(mat.NewVecDense(3, nil), m.Vertices[f[2]], m.Vertices[f[0]])
+		normal := Cross2(a, b)
+		for k, v := range f {
+			c.Normals[v].AddVec(c.Normals[v], normal)
+			angle[v] += m.cornerAngle(f, k)
+		}
+	}
+	boundary := make([]bool, n)
+	for _, e := range m.BoundaryEdges() {
+		boundary[e[0]], boundary[e[1]] = true, true
+	}
+
+	neighbors := make([][]int, n)
+	for key := range m.edgeFaces() {
+		neighbors[key[0]] = append(neighbors[key[0]], key[1])
+		neighbors[key[1]] = append(neighbors[key[1]], key[0])
+	}
+	x := make([][]float64, 3)
+	for d := range x {
+		x[d] = make([]float64, n)
+		for i, p := range m.Vertices {
+			x[d][i] = p.AtVec(d)
+		}
+		x[d] = L.MulVec(x[d])
+	}
+
+	for i := 0; i < n; i++ {
+		if length := mat.Norm(c.Normals[i], 2); length > 0 {
+			c.Normals[i].ScaleVec(1/length, c.Normals[i])
+		}
+		c.Dir1[i], c.Dir2[i] = mat.NewVecDense(3, nil), mat.NewVecDense(3, nil)
+		if area[i] == 0 {
+			continue
+		}
+
+		// Δx = -2H·n
+		laplace := mat.NewVecDense(3, []float64{x[0][i], x[1][i], x[2][i]})
+		c.Mean[i] = -mat.Dot(laplace, c.Normals[i]) / (2 * area[i])
+		full := 2 * math.Pi
+		if boundary[i] {
+			full = math.Pi
+		}
+		c.Gaussian[i] = (full - angle[i]) / area[i]
+		d := math.Sqrt(math.Max(c.Mean[i]*c.Mean[i]-c.Gaussian[i], 0))
+		c.K1[i], c.K2[i] = c.Mean[i]+d, c.Mean[i]-d
+		c.Dir1[i], c.Dir2[i] = m.principalDirections(i, neighbors[i], c.Normals[i])
+	}
+	return c
+}
+
+// principalDirections 在顶点i的切平面内用沿各相邻边的法曲率 κ = -2n·(xj - xi)/|xj - xi|² (符号与平均曲率一致)最小二乘拟合曲率张量，
+// 返回较大与较小特征值对应的单位方向；拟合欠定时返回切平面的任意一组正交方向
+func (m *Mesh) principalDirections(i int, neighbors []int, normal *mat.VecDense) (*mat.VecDense, *mat.VecDense) {
+	if mat.Norm(normal, 2) == 0 {
+		return mat.NewVecDense(3, nil), mat.NewVecDense(3, nil)
+	}
+	frame := newPlaneFrame(m.Vertices[i], normal)
+
+	// 拟合 κ(θ) = a·cos²θ + 2b·cosθsinθ + c·sin²θ
+	var ata mat.SymDense
+	ata.ReuseAsSym(3)
+	atb := mat.NewVecDense(3, nil)
+	rows := 0
+	for _, j := range neighbors {
+		e := SubVec(mat.NewVecDense(3, nil), m.Vertices[j], m.Vertices[i])
+		l2 := mat.Dot(e, e)
+		u, v := mat.Dot(e, frame.u), mat.Dot(e, frame.v)
+		t := math.Hypot(u, v)
+		if l2 == 0 || t == 0 {
+			continue
+		}
+		kappa := -2 * mat.Dot(normal, e) / l2
+		cs, sn := u/t, v/t
+		row := [3]float64{cs * cs, 2 * cs * sn, sn * sn}
+		for p := 0; p < 3; p++ {
+			for q := p; q < 3; q++ {
+				ata.SetSym(p, q, ata.At(p, q)+row[p]*row[q])
+			}
+			atb.SetVec(p, atb.AtVec(p)+row[p]*kappa)
+		}
+		rows++
+	}
+
+	var coef mat.VecDense
+	if rows < 3 || coef.SolveVec(&ata, atb) != nil {
+		return mat.VecDenseCopyOf(frame.u), mat.VecDenseCopyOf(frame.v)
+	}
+	tensor := mat.NewSymDense(2, []float64{coef.AtVec(0), coef.AtVec(1), coef.AtVec(1), coef.AtVec(2)})
+	var eig mat.EigenSym
+	if !eig.Factorize(tensor, true) {
+		return mat.VecDenseCopyOf(frame.u), mat.VecDenseCopyOf(frame.v)
+	}
+	var vectors mat.Dense
+	eig.VectorsTo(&vectors)
+
+	// 特征值按升序排列，第二个特征向量对应较大的主曲率
+	dir := func(k int) *mat.VecDense {
+		return AddVec(mat.NewVecDense(3, nil), ScaleVec2(vectors.At(0, k), frame.u), ScaleVec2(vectors.At(1, k), frame.v))
+	}
+	return dir(1), dir(0)
+}
@@ -0,0 +1,59 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLaplaceBeltrami(t *testing.T) {
+	m := unitSphere(3)
+	L, area := m.LaplaceBeltrami()
+	total := 0.0
+	for _, a := range area {
+		total += a
+	}
+	if s := SurfaceArea(m.Triangles()); math.Abs(total-s) > 1e-9 {
+		t.Errorf("mixed areas sum to %g, surface area %g", total, s)
+	}
+
+	ones := make([]float64, L.Size())
+	for i := range ones {
+		ones[i] = 1
+	}
+	for i, v := range L.MulVec(ones) {
+		if math.Abs(v) > 1e-12 {
+			t.Fatalf("row %d sums to %g", i, v)
+		}
+	}
+	for e := range m.edgeFaces() {
+		if L.At(e[0], e[1]) != L.At(e[1], e[0]) {
+			t.Fatalf("operator is not symmetric at %v", e)
+		}
+	}
+}
+
+func TestCurvatureSphere(t *testing.T) {
+	c := unitSphere(4).Curvature()
+	for i := range c.Mean {
+		if math.Abs(c.Mean[i]-1) > 0.05 || math.Abs(c.Gaussian[i]-1) > 0.1 {
+			t.Fatalf("vertex %d: H = %g, K = %g", i, c.Mean[i], c.Gaussian[i])
+		}
+		if c.K1[i] < c.K2[i] || math.Abs(c.K1[i]-1) > 0.1 || math.Abs(c.K2[i]-1) > 0.1 {
+			t.Fatalf("vertex %d: k1 = %g, k2 = %g", i, c.K1[i], c.K2[i])
+		}
+	}
+}
+
+func TestCurvaturePlane(t *testing.T) {
+	m := planeGrid(8, 4)
+	c := m.Curvature()
+	boundary := make(map[int]bool)
+	for _, e := range m.BoundaryEdges() {
+		boundary[e[0]] = true
+	}
+	for i := range c.Mean {
+		if math.Abs(c.Mean[i]) > 1e-9 || (!boundary[i] && math.Abs(c.Gaussian[i]) > 1e-9) {
+			t.Fatalf("vertex %d: H = %g, K = %g", i, c.Mean[i], c.Gaussian[i])
+		}
+	}
+}
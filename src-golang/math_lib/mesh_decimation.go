@@ -0,0 +1,437 @@
+package math_lib
+
+import (
+	"container/heap"
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+const (
+	decimationBoundaryWeight = 1000 // 边界边与接缝边约束平面的权重(相对于面所在平面)
+	decimationFlipCos        = 0.2  // 折叠前后同一个面的法向量夹角余弦的下限，防止面翻转
+)
+
+// DecimationOptions 网格简化的停止条件与约束，TargetFaces与MaxError至少有一个为正，任一条件达到即停止
+type DecimationOptions struct {
+	TargetFaces int      // 目标面数，0表示不限
+	MaxError    float64  // 单次折叠允许的最大二次误差(到原始平面的距离平方和)，0表示不限
+	Seams       [][2]int // 与边界边一样保留的边，例如纹理接缝
+}
+
+// DecimationReport 网格简化的统计
+type DecimationReport struct {
+	CollapsedEdges int     `json:"collapsed_edges"` // 折叠的边数
+	RemovedFaces   int     `json:"removed_faces"`   // 删除的面数
+	MaxError       float64 `json:"max_error"`       // 已执行的折叠中最大的二次误差
+}
+
+// quadric 对称4x4矩阵的上三角部分，表示到一组平面的距离平方和 Q(x) = [x 1]ᵀQ[x 1]
+type quadric [10]float64
+
+// planeQuadric 平面 n·x + d = 0 (n为单位向量)乘以权重w的二次误差
+func planeQuadric(n [3]float64, d, w float64) quadric {
+	a, b, c := n[0], n[1], n[2]
+	return quadric{w * a * a, w * a * b, w * a * c, w * a * d, w * b * b, w * b * c, w * b * d, w * c * c, w * c * d, w * d * d}
+}
+
+func (q *quadric) add(o quadric) {
+	for i := range q {
+		q[i] += o[i]
+	}
+}
+
+// eval 计算点x处的二次误差
+func (q *quadric) eval(x [3]float64) float64 {
+	return q[0]*x[0]*x[0] + 2*q[1]*x[0]*x[1] + 2*q[2]*x[0]*x[2] + 2*q[3]*x[0] +
+		q[4]*x[1]*x[1] + 2*q[5]*x[1]*x[2] + 2*q[6]*x[1] +
+		q[7]*x[2]*x[2] + 2*q[8]*x[2] + q[9]
+}
+
+// optimum 求二次误差的最小点，矩阵接近奇异时返回false
+func (q *quadric) optimum() ([3]float64, bool) {
+	a := [3][3]float64{{q[0], q[1], q[2]}, {q[1], q[4], q[5]}, {q[2], q[5], q[7]}}
+	b := [3]float64{-q[3], -q[6], -q[8]}
+	det := a[0][0]*(a[1][1]*a[2][2]-a[1][2]*a[2][1]) - a[0][1]*(a[1][0]*a[2][2]-a[1][2]*a[2][0]) + a[0][2]*(a[1][0]*a[2][1]-a[1][1]*a[2][0])
+	scale := q[0] + q[4] + q[7]
+	if scale == 0 || math.Abs(det) < 1e-9*scale*scale*scale {
+		return [3]float64{}, false
+	}
+
+	// 克莱姆法则
+	var x [3]float64
+	for k := 0; k < 3; k++ {
+		m := a
+		for i := 0; i < 3; i++ {
+			m[i][k] = b[i]
+		}
+		x[k] = (m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) - m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) + m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])) / det
+	}
+	return x, true
+}
+
+// collapseCandidate 堆中的候选折叠，顶点的版本号改变后失效
+type collapseCandidate struct {
+	u, v           int
+	cost           float64
+	target         [3]float64
+	stampU, stampV int
+}
+
+// collapseQueue 按误差排序的小根堆
+type collapseQueue []collapseCandidate
+
+func (q collapseQueue) Len() int           { return len(q) }
+func (q collapseQueue) Less(i, j int) bool { return q[i].cost < q[j].cost }
+func (q collapseQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *collapseQueue) Push(x any)        { *q = append(*q, x.(collapseCandidate)) }
+func (q *collapseQueue) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// decimation 边折叠过程中的网格状态
+type decimation struct {
+	pos      [][3]float64
+	faces    [][3]int
+	alive    []bool
+	incident [][]int // 每个顶点所属的面，可能包含已删除的面
+	quadrics []quadric
+	locked   []bool // 位于边界或接缝上的顶点
+	seams    map[[2]int]bool
+	stamp    []int
+	queue    collapseQueue
+}
+
+// Decimate 用Garland-Heckbert二次误差度量的边折叠简化网格，每次折叠误差最小的边并将顶点放在误差最小的位置
+// 边界边与接缝边加入垂直于所在面的约束平面，两端都在边界或接缝上的内部边不折叠；
+// 折叠前检查连接条件与面的翻转，保持网格的拓扑不变。网格应为顶点共享的流形网格
+func (m *Mesh) Decimate(opts DecimationOptions) DecimationReport {
+	var r DecimationReport
+	s := newDecimation(m, opts.Seams)
+	faces := 0
+	for i := range s.faces {
+		if s.alive[i] {
+			faces++
+		} else {
+			r.RemovedFaces++
+		}
+	}
+	for faces > opts.TargetFaces && s.queue.Len() > 0 {
+		c := heap.Pop(&s.queue).(collapseCandidate)
+		if c.stampU != s.stamp[c.u] || c.stampV != s.stamp[c.v] {
+			continue
+		}
+		if opts.MaxError > 0 && c.cost > opts.MaxError {
+			break
+		}
+		removed := s.collapse(c.u, c.v, c.target)
+		if removed == 0 {
+			continue
+		}
+		faces -= removed
+		r.CollapsedEdges++
+		r.RemovedFaces += removed
+		r.MaxError = math.Max(r.MaxError, c.cost)
+	}
+
+	var res [][3]int
+	for i, f := range s.faces {
+		if s.alive[i] {
+			res = append(res, f)
+		}
+	}
+	for i, p := range s.pos {
+		if p != [3]float64{m.Vertices[i].AtVec(0), m.Vertices[i].AtVec(1), m.Vertices[i].AtVec(2)} {
+			m.Vertices[i] = mat.NewVecDense(3, []float64{p[0], p[1], p[2]})
+		}
+	}
+	m.Faces = res
+	m.removeUnusedVertices()
+	return r
+}
+
+// newDecimation 计算每个顶点的二次误差并将所有边加入堆
+func newDecimation(m *Mesh, seams [][2]int) *decimation {
+	s := &decimation{
+		pos:      make([][3]float64, len(m.Vertices)),
+		faces:    append([][3]int(nil), m.Faces...),
+		alive:    make([]bool, len(m.Faces)),
+		incident: m.incidentFaces(),
+		quadrics: make([]quadric, len(m.Vertices)),
+		locked:   make([]bool, len(m.Vertices)),
+		seams:    make(map[[2]int]bool, len(seams)),
+		stamp:    make([]int, len(m.Vertices)),
+	}
+	for i, v := range m.Vertices {
+		s.pos[i] = [3]float64{v.AtVec(0), v.AtVec(1), v.AtVec(2)}
+	}
+	for i, f := range s.faces {
+		// 有重复顶点的面(例如参数曲面极点处的面)直接删除
+		s.alive[i] = f[0] != f[1] && f[1] != f[2] && f[2] != f[0]
+		if !s.alive[i] {
+			continue
+		}
+		n, ok := s.normal(f[0], f[1], f[2], -1, [3]float64{})
+		if !ok {
+			continue
+		}
+		q := planeQuadric(n, -dot3(n, s.pos[f[0]]), 1)
+		for _, v := range f {
+			s.quadrics[v].add(q)
+		}
+	}
+
+	// 边界边与接缝边的约束平面经过该边并垂直于所在面，权重与边长的平方成正比
+	edges := m.edgeFaces()
+	constrain := func(u, v, face int) {
+		s.locked[u], s.locked[v] = true, true
+		n, ok := s.normal(s.faces[face][0], s.faces[face][1], s.faces[face][2], -1, [3]float64{})
+		if !ok {
+			return
+		}
+		e := sub3(s.pos[v], s.pos[u])
+		c := cross3(e, n)
+		length := math.Sqrt(dot3(c, c))
+		if length == 0 {
+			return
+		}
+		for k := range c {
+			c[k] /= length
+		}
+		q := planeQuadric(c, -dot3(c, s.pos[u]), decimationBoundaryWeight*dot3(e, e))
+		s.quadrics[u].add(q)
+		s.quadrics[v].add(q)
+	}
+	for _, e := range seams {
+		key := edgeKey(e[0], e[1])
+		if list := edges[key]; len(list) > 0 && !s.seams[key] {
+			s.seams[key] = true
+			for _, face := range list {
+				constrain(key[0], key[1], face)
+			}
+		}
+	}
+	for key, list := range edges {
+		if len(list) == 1 {
+			constrain(key[0], key[1], list[0])
+		}
+	}
+
+	for key := range edges {
+		s.push(key[0], key[1])
+	}
+	return s
+}
+
+// push 计算折叠边uv的最优位置与误差并加入堆
+func (s *decimation) push(u, v int) {
+	q := s.quadrics[u]
+	q.add(s.quadrics[v])
+	target, ok := q.optimum()
+	cost := math.Inf(1)
+	if ok {
+		cost = q.eval(target)
+	}
+	// 只有一端在边界或接缝上时该端点保持不动
+	if s.locked[u] != s.locked[v] {
+		target = s.pos[u]
+		if s.locked[v] {
+			target = s.pos[v]
+		}
+		heap.Push(&s.queue, collapseCandidate{u: u, v: v, cost: math.Max(q.eval(target), 0), target: target, stampU: s.stamp[u], stampV: s.stamp[v]})
+		return
+	}
+
+	// 矩阵奇异或最优点离边太远时在两端点与中点中选择
+	mid := [3]float64{(s.pos[u][0] + s.pos[v][0]) / 2, (s.pos[u][1] + s.pos[v][1]) / 2, (s.pos[u][2] + s.pos[v][2]) / 2}
+	if !ok || dist3(target, mid) > 2*dist3(s.pos[u], s.pos[v]) {
+		cost = math.Inf(1)
+		for _, p := range [][3]float64{s.pos[u], s.pos[v], mid} {
+			if c := q.eval(p); c < cost {
+				cost, target = c, p
+			}
+		}
+	}
+	heap.Push(&s.queue, collapseCandidate{u: u, v: v, cost: math.Max(cost, 0), target: target, stampU: s.stamp[u], stampV: s.stamp[v]})
+}
+
+// collapse 将边uv折叠到位置p，保留顶点u，返回删除的面数；折叠会破坏拓扑或使面翻转时返回0
+func (s *decimation) collapse(u, v int, p [3]float64) int {
+	s.incident[u] = s.aliveFaces(u)
+	s.incident[v] = s.aliveFaces(v)
+
+	var shared []int
+	opposite := make(map[int]bool)
+	for _, i := range s.incident[u] {
+		if faceHas(s.faces[i], v) {
+			shared = append(shared, i)
+			for _, w := range s.faces[i] {
+				if w != u && w != v {
+					opposite[w] = true
+				}
+			}
+		}
+	}
+	if len(shared) == 0 || len(shared) > 2 {
+		return 0
+	}
+	key := edgeKey(u, v)
+	if s.locked[u] && s.locked[v] && len(shared) == 2 && !s.seams[key] {
+		return 0
+	}
+
+	// 连接条件: u与v的公共邻点只能是共享面的第三个顶点
+	neighbors := make(map[int]bool)
+	for _, i := range s.incident[u] {
+		for _, w := range s.faces[i] {
+			neighbors[w] = true
+		}
+	}
+	vNeighbors := make(map[int]bool)
+	for _, i := range s.incident[v] {
+		for _, w := range s.faces[i] {
+			if w != u && w != v && neighbors[w] && !opposite[w] {
+				return 0
+			}
+			vNeighbors[w] = true
+		}
+	}
+	if len(shared) == 1 && len(s.incident[u]) == 1 && len(s.incident[v]) == 1 {
+		return 0
+	}
+
+	// 两侧剩余的面不能重合(例如四面体)
+	kept := make(map[[3]int]bool)
+	for _, i := range s.incident[u] {
+		if !faceHas(s.faces[i], v) {
+			kept[sortedTriple(s.faces[i][0], s.faces[i][1], s.faces[i][2])] = true
+		}
+	}
+	for _, i := range s.incident[v] {
+		f := s.faces[i]
+		if faceHas(f, u) {
+			continue
+		}
+		for k := range f {
+			if f[k] == v {
+				f[k] = u
+			}
+		}
+		if kept[sortedTriple(f[0], f[1], f[2])] {
+			return 0
+		}
+	}
+
+	// 检查剩余的面在移动后是否翻转或退化
+	for _, x := range []int{u, v} {
+		for _, i := range s.incident[x] {
+			if faceHas(s.faces[i], u) && faceHas(s.faces[i], v) {
+				continue
+			}
+			f := s.faces[i]
+			before, ok := s.normal(f[0], f[1], f[2], -1, [3]float64{})
+			after, ok2 := s.normal(f[0], f[1], f[2], x, p)
+			if ok && (!ok2 || dot3(before, after) < decimationFlipCos) {
+				return 0
+			}
+		}
+	}
+
+	for _, i := range shared {
+		s.alive[i] = false
+	}
+	for _, i := range s.incident[v] {
+		if !s.alive[i] {
+			continue
+		}
+		for k := range s.faces[i] {
+			if s.faces[i][k] == v {
+				s.faces[i][k] = u
+			}
+		}
+		s.incident[u] = append(s.incident[u], i)
+	}
+	s.incident[u] = s.aliveFaces(u)
+	s.incident[v] = nil
+
+	// v上的接缝边转移到u
+	for w := range vNeighbors {
+		if s.seams[edgeKey(v, w)] {
+			delete(s.seams, edgeKey(v, w))
+			if w != u {
+				s.seams[edgeKey(u, w)] = true
+			}
+		}
+	}
+
+	s.pos[u] = p
+	s.quadrics[u].add(s.quadrics[v])
+	s.locked[u] = s.locked[u] || s.locked[v]
+	s.stamp[u]++
+	s.stamp[v]++
+
+	pushed := make(map[int]bool)
+	for _, i := range s.incident[u] {
+		for _, w := range s.faces[i] {
+			if w != u && !pushed[w] {
+				pushed[w] = true
+				s.push(u, w)
+			}
+		}
+	}
+	return len(shared)
+}
+
+// aliveFaces 返回顶点v所属的未删除的面
+func (s *decimation) aliveFaces(v int) []int {
+	res := s.incident[v][:0]
+	for _, i := range s.incident[v] {
+		if s.alive[i] {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// normal 计算面abc的单位法向量，moved不为-1时将该顶点替换为位置p，面积为零时返回false
+func (s *decimation) normal(a, b, c, moved int, p [3]float64) ([3]float64, bool) {
+	pa, pb, pc := s.pos[a], s.pos[b], s.pos[c]
+	switch moved {
+	case a:
+		pa = p
+	case b:
+		pb = p
+	case c:
+		pc = p
+	}
+	n := cross3(sub3(pb, pa), sub3(pc, pa))
+	length := math.Sqrt(dot3(n, n))
+	if length == 0 {
+		return n, false
+	}
+	return [3]float64{n[0] / length, n[1] / length, n[2] / length}, true
+}
+
+// faceHas 判断面f是否包含顶点v
+func faceHas(f [3]int, v int) bool {
+	return f[0] == v || f[1] == v || f[2] == v
+}
+
+func sub3(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func dot3(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross3(a, b [3]float64) [3]float64 {
+	return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func dist3(a, b [3]float64) float64 {
+	d := sub3(a, b)
+	return math.Sqrt(dot3(d, d))
+}
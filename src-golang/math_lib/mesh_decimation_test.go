@@ -0,0 +1,37 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecimateSphere(t *testing.T) {
+	m := unitSphere(4)
+	r := m.Decimate(DecimationOptions{TargetFaces: 200})
+	if len(m.Faces) > 200 || len(m.Faces) < 190 || r.RemovedFaces != 2048-len(m.Faces) {
+		t.Errorf("%d faces left, report %+v", len(m.Faces), r)
+	}
+	v := m.Validate()
+	if !v.Watertight || !v.Manifold || !v.ConsistentlyOriented || v.Genus != 0 || v.DegenerateFaces != 0 {
+		t.Errorf("report %+v", v)
+	}
+	if volume := Volume(m.Triangles()); math.Abs(volume-4*math.Pi/3) > 0.1*4*math.Pi/3 {
+		t.Errorf("volume %g", volume)
+	}
+}
+
+func TestDecimatePlaneKeepsBoundary(t *testing.T) {
+	m := planeGrid(10, 5)
+	m.Decimate(DecimationOptions{MaxError: 1e-12})
+	if len(m.Faces) >= 100 {
+		t.Errorf("%d faces left on a flat grid", len(m.Faces))
+	}
+	if area := SurfaceArea(m.Triangles()); math.Abs(area-2) > 1e-9 {
+		t.Errorf("area %g, want 2", area)
+	}
+	for _, p := range m.Vertices {
+		if math.Abs(p.AtVec(2)) > 1e-9 {
+			t.Fatalf("vertex %v left the plane", p.RawVector().Data)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package math_lib
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// GeodesicDistance 用热方法(Crane et al.)计算每个顶点到sources中最近顶点的测地距离：
+// 先以时间步长 t = h² (h为平均边长)求解热传导 (A - tL)u = δ，再将面上的 -∇u 归一化为向量场X，最后求解泊松方程 Lφ = ∇·X
+// u随到源点的距离按指数衰减，远处的值比|δ|小许多数量级，因此两个方程组都用稀疏Cholesky分解直接求解而不用迭代法
+// 边界使用Neumann条件；与sources不连通的顶点距离为+Inf
+func (m *Mesh) GeodesicDistance(sources []int) ([]float64, error) {
+	n := len(m.Vertices)
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("geodesic: no source vertex")
+	}
+	for _, s := range sources {
+		if s < 0 || s >= n {
+			return nil, fmt.Errorf("geodesic: invalid source vertex %d", s)
+		}
+	}
+
+	neighbors := make([][]int, n)
+	h, count := 0.0, 0
+	for key := range m.edgeFaces() {
+		neighbors[key[0]] = append(neighbors[key[0]], key[1])
+		neighbors[key[1]] = append(neighbors[key[1]], key[0])
+		h += math.Sqrt(squaredDistance(m.Vertices[key[0]], m.Vertices[key[1]]))
+		count++
+	}
+	if count == 0 || h == 0 {
+		return nil, fmt.Errorf("geodesic: mesh has no edges")
+	}
+	h /= float64(count)
+	t := h * h
+
+	// 连通分量，roots为每个分量中的第一个顶点
+	component := make([]int, n)
+	for i := range component {
+		component[i] = -1
+	}
+	var roots []int
+	for v := range component {
+		if component[v] >= 0 {
+			continue
+		}
+		component[v] = len(roots)
+		queue := []int{v}
+		for k := 0; k < len(queue); k++ {
+			for _, j := range neighbors[queue[k]] {
+				if component[j] < 0 {
+					component[j] = len(roots)
+					queue = append(queue, j)
+				}
+			}
+		}
+		roots = append(roots, v)
+	}
+
+	// 热传导
+	L, area := m.LaplaceBeltrami()
+	heat := NewSparseMatrix(n)
+	for i, row := range L.rows {
+		heat.Add(i, i, math.Max(area[i], 1e-12*t))
+		for _, e := range row {
+			heat.Add(i, e.col, -t*e.value)
+		}
+	}
+	delta := make([]float64, n)
+	for _, s := range sources {
+		delta[s] = 1
+	}
+	factor, err := heat.Cholesky()
+	if err != nil {
+		return nil, fmt.Errorf("geodesic: heat flow: %w", err)
+	}
+	u := factor.Solve(delta)
+
+	// 归一化梯度场的散度：∇·X = 1/2·Σ cotθ1·(e1·X) + cotθ2·(e2·X)
+	pos := make([][3]float64, n)
+	for i, v := range m.Vertices {
+		pos[i] = [3]float64{v.AtVec(0), v.AtVec(1), v.AtVec(2)}
+	}
+	div := make([]float64, n)
+	for _, f := range m.Faces {
+		g := faceGradient(pos, f, u)
+		length := math.Sqrt(dot3(g, g))
+		if length == 0 {
+			continue
+		}
+		x := [3]float64{-g[0] / length, -g[1] / length, -g[2] / length}
+		for k := 0; k < 3; k++ {
+			i, j, l := f[k], f[(k+1)%3], f[(k+2)%3]
+			e1, e2 := sub3(pos[j], pos[i]), sub3(pos[l], pos[i])
+			div[i] += (m.cornerCotangent(f, (k+2)%3)*dot3(e1, x) + m.cornerCotangent(f, (k+1)%3)*dot3(e2, x)) / 2
+		}
+	}
+
+	// Lφ = ∇·X，L半负定，改为求解 -Lφ = -∇·X；φ在每个连通分量内只确定到相差常数，固定分量第一个顶点的φ为零使方程组正定
+	pinned := make([]bool, n)
+	for _, r := range roots {
+		pinned[r] = true
+	}
+	poisson := NewSparseMatrix(n)
+	for i, row := range L.rows {
+		if pinned[i] {
+			poisson.Add(i, i, 1)
+			div[i] = 0
+			continue
+		}
+		for _, e := range row {
+			if !pinned[e.col] {
+				poisson.Add(i, e.col, -e.value)
+			}
+		}
+		div[i] = -div[i]
+	}
+	if factor, err = poisson.Cholesky(); err != nil {
+		return nil, fmt.Errorf("geodesic: poisson: %w", err)
+	}
+	phi := factor.Solve(div)
+
+	// 含有源点的连通分量平移使最小值为零，其余顶点为+Inf
+	reached := make([]bool, len(roots))
+	for _, s := range sources {
+		reached[component[s]] = true
+	}
+	low := make([]float64, len(roots))
+	for c := range low {
+		low[c] = math.Inf(1)
+	}
+	for i, c := range component {
+		low[c] = math.Min(low[c], phi[i])
+	}
+	res := make([]float64, n)
+	for i, c := range component {
+		res[i] = math.Inf(1)
+		if reached[c] {
+			res[i] = phi[i] - low[c]
+		}
+	}
+	for _, s := range sources {
+		res[s] = 0
+	}
+	return res, nil
+}
+
+// faceGradient 返回线性插值的顶点函数值在面f上的梯度 Σ φi·(n × ei)/(2A)，ei为顶点i所对的边；面积为零时返回零向量
+func faceGradient(pos [][3]float64, f [3]int, phi []float64) [3]float64 {
+	normal := cross3(sub3(pos[f[1]], pos[f[0]]), sub3(pos[f[2]], pos[f[0]]))
+	area2 := dot3(normal, normal)
+	var g [3]float64
+	if area2 == 0 {
+		return g
+	}
+	for k := 0; k < 3; k++ {
+		c := cross3(normal, sub3(pos[f[(k+2)%3]], pos[f[(k+1)%3]]))
+		for d := 0; d < 3; d++ {
+			g[d] += phi[f[k]] * c[d] / area2
+		}
+	}
+	return g
+}
+
+// GeodesicPath 返回从顶点source到顶点target的测地线折线(首尾分别为两个顶点)：
+// 用GeodesicDistance求出到source的距离场后，从target沿各面上距离场的负梯度方向穿过面片回溯到source，
+// 负梯度指向面外时沿边走向距离较小的端点
+func (m *Mesh) GeodesicPath(source, target int) ([]*mat.VecDense, error) {
+	if target < 0 || target >= len(m.Vertices) {
+		return nil, fmt.Errorf("geodesic: invalid target vertex %d", target)
+	}
+	dist, err := m.GeodesicDistance([]int{source})
+	if err != nil {
+		return nil, err
+	}
+	if math.IsInf(dist[target], 1) {
+		return nil, fmt.Errorf("geodesic: vertex %d is not connected to vertex %d", target, source)
+	}
+
+	t := &geodesicTracer{m: m, dist: dist, edges: m.edgeFaces(), incident: m.incidentFaces(), pos: make([][3]float64, len(m.Vertices))}
+	for i, v := range m.Vertices {
+		t.pos[i] = [3]float64{v.AtVec(0), v.AtVec(1), v.AtVec(2)}
+	}
+	points, err := t.trace(target)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*mat.VecDense, len(points))
+	for i, p := range points {
+		res[len(points)-1-i] = mat.NewVecDense(3, []float64{p[0], p[1], p[2]})
+	}
+	return res, nil
+}
+
+// geodesicTracer 沿距离场的负梯度回溯测地线
+type geodesicTracer struct {
+	m        *Mesh
+	pos      [][3]float64
+	dist     []float64
+	edges    map[[2]int][]int
+	incident [][]int
+	visited  []bool // 回溯经过的顶点
+}
+
+// geodesicState 回溯过程中的当前位置：位于顶点vertex(≥0)，或位于从face穿出的边edge上参数为s处(pos[edge[0]] + s·(pos[edge[1]] - pos[edge[0]]))
+type geodesicState struct {
+	vertex int
+	edge   [2]int
+	s      float64
+	face   int
+}
+
+// trace 从顶点start回溯到距离为零的顶点，返回经过的点
+func (t *geodesicTracer) trace(start int) ([][3]float64, error) {
+	state := geodesicState{vertex: start}
+	points := [][3]float64{t.pos[start]}
+	t.visited = make([]bool, len(t.pos))
+	t.visited[start] = true
+	for steps := 0; steps < 4*len(t.m.Faces)+len(t.pos); steps++ {
+		if state.vertex >= 0 && t.dist[state.vertex] == 0 {
+			return points, nil
+		}
+		var ok bool
+		if state.vertex >= 0 {
+			state, ok = t.fromVertex(state.vertex)
+			if !ok {
+				return nil, fmt.Errorf("geodesic: path stuck at vertex %d", state.vertex)
+			}
+		} else {
+			state = t.fromEdge(state)
+		}
+		if state.vertex >= 0 {
+			t.visited[state.vertex] = true
+			points = append(points, t.pos[state.vertex])
+		} else {
+			points = append(points, t.edgePoint(state))
+		}
+	}
+	return nil, fmt.Errorf("geodesic: path tracing did not reach the source")
+}
+
+// edgePoint 返回位于边上的状态对应的点
+func (t *geodesicTracer) edgePoint(state geodesicState) [3]float64 {
+	a, b := t.pos[state.edge[0]], t.pos[state.edge[1]]
+	return [3]float64{a[0] + state.s*(b[0]-a[0]), a[1] + state.s*(b[1]-a[1]), a[2] + state.s*(b[2]-a[2])}
+}
+
+// fromVertex 从顶点v出发，选择下降最快的相邻边或相邻面内的负梯度方向；没有下降方向时返回false
+func (t *geodesicTracer) fromVertex(v int) (geodesicState, bool) {
+	best, rate := geodesicState{vertex: v}, 0.0
+	for _, fi := range t.incident[v] {
+		f := t.m.Faces[fi]
+		k := 0
+		for f[k] != v {
+			k++
+		}
+		a, b := f[(k+1)%3], f[(k+2)%3]
+		if t.dist[a] == 0 || t.dist[b] == 0 {
+			// 相邻面内含有源点时直接走向源点
+			s := a
+			if t.dist[a] != 0 {
+				s = b
+			}
+			if r := t.dist[v] / dist3(t.pos[v], t.pos[s]); r > rate {
+				best, rate = geodesicState{vertex: s}, r
+			}
+			continue
+		}
+		for _, w := range [2]int{a, b} {
+			if l := dist3(t.pos[v], t.pos[w]); l > 0 {
+				if r := (t.dist[v] - t.dist[w]) / l; r > rate {
+					best, rate = geodesicState{vertex: w}, r
+				}
+			}
+		}
+
+		g := faceGradient(t.pos, f, t.dist)
+		length := math.Sqrt(dot3(g, g))
+		if length <= rate {
+			continue
+		}
+		d := [3]float64{-g[0], -g[1], -g[2]}
+		normal := cross3(sub3(t.pos[a], t.pos[v]), sub3(t.pos[b], t.pos[v]))
+		if dot3(cross3(sub3(t.pos[a], t.pos[v]), d), normal) < 0 || dot3(cross3(d, sub3(t.pos[b], t.pos[v])), normal) < 0 {
+			continue
+		}
+		if s, ok := t.crossing(t.pos[v], d, a, b, normal); ok {
+			best, rate = t.snap(a, b, s, fi), length
+		}
+	}
+	if rate > 0 {
+		return best, true
+	}
+
+	// 距离场的数值误差可能产生局部极小值，此时走向距离最小的未经过的相邻顶点
+	for _, fi := range t.incident[v] {
+		for _, w := range t.m.Faces[fi] {
+			if !t.visited[w] && (best.vertex == v || t.dist[w] < t.dist[best.vertex]) {
+				best = geodesicState{vertex: w}
+			}
+		}
+	}
+	return best, best.vertex != v
+}
+
+// fromEdge 从边上的点进入边另一侧的面，沿该面内的负梯度方向前进到面的另一条边；无法进入时沿边走向距离较小的端点
+func (t *geodesicTracer) fromEdge(state geodesicState) geodesicState {
+	a, b := state.edge[0], state.edge[1]
+	lower := geodesicState{vertex: a}
+	if t.dist[b] < t.dist[a] {
+		lower.vertex = b
+	}
+	next := -1
+	for _, fi := range t.edges[edgeKey(a, b)] {
+		if fi != state.face {
+			next = fi
+		}
+	}
+	if next < 0 || len(t.edges[edgeKey(a, b)]) != 2 {
+		return lower
+	}
+
+	f := t.m.Faces[next]
+	c := f[0] + f[1] + f[2] - a - b
+	if t.dist[c] == 0 {
+		return geodesicState{vertex: c}
+	}
+	g := faceGradient(t.pos, f, t.dist)
+	d := [3]float64{-g[0], -g[1], -g[2]}
+	normal := cross3(sub3(t.pos[f[1]], t.pos[f[0]]), sub3(t.pos[f[2]], t.pos[f[0]]))
+	inward := cross3(normal, sub3(t.pos[b], t.pos[a]))
+	if dot3(inward, sub3(t.pos[c], t.pos[a])) < 0 {
+		inward = [3]float64{-inward[0], -inward[1], -inward[2]}
+	}
+	if dot3(d, inward) <= 1e-12*math.Sqrt(dot3(d, d)*dot3(inward, inward)) {
+		return lower
+	}
+
+	p := t.edgePoint(state)
+	for _, e := range [2][2]int{{a, c}, {b, c}} {
+		if s, ok := t.crossing(p, d, e[0], e[1], normal); ok {
+			return t.snap(e[0], e[1], s, next)
+		}
+	}
+	return lower
+}
+
+// crossing 求从p沿方向d(位于法向量为normal的面内)的射线与边(a, b)的交点参数s∈[0, 1]
+func (t *geodesicTracer) crossing(p, d [3]float64, a, b int, normal [3]float64) (float64, bool) {
+	e := sub3(t.pos[b], t.pos[a])
+	denom := dot3(cross3(e, d), normal)
+	if denom == 0 {
+		return 0, false
+	}
+	s := dot3(cross3(sub3(p, t.pos[a]), d), normal) / denom
+	if s < -1e-9 || s > 1+1e-9 {
+		return 0, false
+	}
+	s = math.Max(0, math.Min(1, s))
+	q := [3]float64{t.pos[a][0] + s*e[0] - p[0], t.pos[a][1] + s*e[1] - p[1], t.pos[a][2] + s*e[2] - p[2]}
+	return s, dot3(q, d) > 0
+}
+
+// snap 返回边(a, b)上参数s处的状态，靠近端点时取为端点
+func (t *geodesicTracer) snap(a, b int, s float64, face int) geodesicState {
+	switch {
+	case s < 1e-9:
+		return geodesicState{vertex: a}
+	case s > 1-1e-9:
+		return geodesicState{vertex: b}
+	}
+	return geodesicState{vertex: -1, edge: [2]int{a, b}, s: s, face: face}
+}
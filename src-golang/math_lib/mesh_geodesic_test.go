@@ -0,0 +1,61 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+)
+
+// 热传导的解随到源点的边数按指数衰减，远离源点处的距离不能被求解误差淹没
+func TestGeodesicDistancePlane(t *testing.T) {
+	for _, nx := range []int{20, 160} {
+		m := planeGrid(nx, nx/2)
+		d, err := m.GeodesicDistance([]int{0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		worst := 0.0
+		for i, p := range m.Vertices {
+			worst = math.Max(worst, math.Abs(d[i]-math.Hypot(p.AtVec(0), p.AtVec(1))))
+		}
+		if worst > 0.1 {
+			t.Errorf("%d×%d grid: max distance error %g", nx, nx/2, worst)
+		}
+	}
+}
+
+func TestGeodesicDistanceSphere(t *testing.T) {
+	for _, c := range []struct {
+		levels    int
+		tolerance float64
+	}{{3, 0.15}, {5, 0.05}} {
+		m := unitSphere(c.levels)
+		d, err := m.GeodesicDistance([]int{0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		worst := 0.0
+		for i, p := range m.Vertices {
+			worst = math.Max(worst, math.Abs(d[i]-math.Acos(math.Max(-1, math.Min(1, p.AtVec(0))))))
+		}
+		if worst > c.tolerance {
+			t.Errorf("%d levels: max distance error %g", c.levels, worst)
+		}
+	}
+}
+
+func TestGeodesicPathPlane(t *testing.T) {
+	for _, nx := range []int{20, 40} {
+		m := planeGrid(nx, nx/2)
+		path, err := m.GeodesicPath(0, len(m.Vertices)-1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		length := 0.0
+		for i := 1; i < len(path); i++ {
+			length += math.Sqrt(squaredDistance(path[i], path[i-1]))
+		}
+		if exact := math.Sqrt(5); math.Abs(length-exact) > 0.03*exact {
+			t.Errorf("%d×%d grid: path length %g, want %g", nx, nx/2, length, exact)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// meshDistance 到固定三角网格的距离场，封闭网格用角度加权伪法向量(Bærentzen & Aanæs)判断符号，内部为负；开放网格为无符号距离
+type meshDistance struct {
+	*surfaceProjector
+	faces   [][3]int
+	face    [][3]float64          // 面的单位法向量
+	edge    map[[2]int][3]float64 // 边两侧面法向量之和
+	vertex  [][3]float64          // 相邻面法向量按内角加权之和
+	signed  bool
+	outward float64 // 面法向量朝外时为1，朝内时为-1
+}
+
+func newMeshDistance(m *Mesh) *meshDistance {
+	all := make([]int, len(m.Faces))
+	for i := range all {
+		all[i] = i
+	}
+	volume := m.closedVolume(all)
+	d := &meshDistance{surfaceProjector: newSurfaceProjector(m), faces: m.Faces, signed: volume != 0, outward: 1}
+	if !d.signed {
+		return d
+	}
+	if volume < 0 {
+		d.outward = -1
+	}
+
+	d.face = make([][3]float64, len(m.Faces))
+	d.edge = make(map[[2]int][3]float64, 3*len(m.Faces)/2)
+	d.vertex = make([][3]float64, len(m.Vertices))
+	for i, f := range m.Faces {
+		t := d.tris[i]
+		n := cross3(sub3(t[1], t[0]), sub3(t[2], t[0]))
+		if length := math.Sqrt(dot3(n, n)); length > 0 {
+			n = [3]float64{n[0] / length, n[1] / length, n[2] / length}
+		}
+		d.face[i] = n
+		for k := 0; k < 3; k++ {
+			key := edgeKey(f[k], f[(k+1)%3])
+			e := d.edge[key]
+			a := m.cornerAngle(f, k)
+			for c := 0; c < 3; c++ {
+				e[c] += n[c]
+				d.vertex[f[k]][c] += a * n[c]
+			}
+			d.edge[key] = e
+		}
+	}
+	return d
+}
+
+// at 返回点p到网格的(带符号)距离
+func (d *meshDistance) at(p [3]float64) float64 {
+	best, dist2 := d.tree.nearest(p, func(i int) float64 {
+		q := closestPointOnTriangle(p, d.tris[i][0], d.tris[i][1], d.tris[i][2])
+		return dot3(sub3(q, p), sub3(q, p))
+	})
+	if best < 0 {
+		return math.Inf(1)
+	}
+	dist := math.Sqrt(dist2)
+	if !d.signed {
+		return dist
+	}
+
+	// 最近点位于面内、边上或顶点处时分别使用面、边或顶点的伪法向量
+	t, f := d.tris[best], d.faces[best]
+	q := closestPointOnTriangle(p, t[0], t[1], t[2])
+	lambda := barycentric3(q, t[0], t[1], t[2])
+	var zero []int
+	for k, l := range lambda {
+		if l < 1e-7 {
+			zero = append(zero, k)
+		}
+	}
+	n := d.face[best]
+	switch len(zero) {
+	case 1:
+		n = d.edge[edgeKey(f[(zero[0]+1)%3], f[(zero[0]+2)%3])]
+	case 2:
+		n = d.vertex[f[3-zero[0]-zero[1]]]
+	}
+	if d.outward*dot3(sub3(p, q), n) < 0 {
+		return -dist
+	}
+	return dist
+}
+
+// barycentric3 返回点q(位于三角形abc所在平面内)的重心坐标
+func barycentric3(q, a, b, c [3]float64) [3]float64 {
+	v0, v1, v2 := sub3(b, a), sub3(c, a), sub3(q, a)
+	d00, d01, d11 := dot3(v0, v0), dot3(v0, v1), dot3(v1, v1)
+	d20, d21 := dot3(v2, v0), dot3(v2, v1)
+	denom := d00*d11 - d01*d01
+	if denom == 0 {
+		return [3]float64{1, 0, 0}
+	}
+	v := (d11*d20 - d01*d21) / denom
+	w := (d00*d21 - d01*d20) / denom
+	return [3]float64{1 - v - w, v, w}
+}
+
+// isosurface 用MarchingCubes提取距离场等于level的等值面，resolution为扩展后包围盒最长边上的体素数，输出三角形的法向量指向距离增大的一侧
+func (d *meshDistance) isosurface(level float64, resolution int) []*Triangle {
+	if len(d.tris) == 0 || resolution < 1 {
+		return []*Triangle{}
+	}
+	lo := [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	hi := [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, t := range d.tris {
+		for _, p := range t {
+			for k := 0; k < 3; k++ {
+				lo[k], hi[k] = math.Min(lo[k], p[k]), math.Max(hi[k], p[k])
+			}
+		}
+	}
+	margin := math.Max(level, 0)
+	size := math.Max(hi[0]-lo[0], math.Max(hi[1]-lo[1], hi[2]-lo[2])) + 2*margin
+	if size == 0 {
+		return []*Triangle{}
+	}
+
+	// 包围盒每侧留出两个体素，保证等值面在网格内闭合
+	cell := size / float64(resolution)
+	st, ed, N := make([]float64, 3), make([]float64, 3), make([]int, 3)
+	for k := 0; k < 3; k++ {
+		N[k] = int(math.Ceil((hi[k]-lo[k]+2*margin)/cell)) + 4
+		st[k] = lo[k] - margin - 2*cell
+		ed[k] = st[k] + float64(N[k])*cell
+	}
+
+	// 相邻体素共享格点，按格点下标缓存距离
+	cache := make(map[[3]int]float64)
+	f := func(x *mat.VecDense) float64 {
+		var key [3]int
+		for k := range key {
+			key[k] = int(math.Round((x.AtVec(k) - st[k]) / cell))
+		}
+		v, ok := cache[key]
+		if !ok {
+			v = d.at([3]float64{x.AtVec(0), x.AtVec(1), x.AtVec(2)}) - level
+			cache[key] = v
+		}
+		return v
+	}
+
+	// MarchingCubes生成的三角形法向量指向函数值为负的一侧，交换两个顶点使其指向外侧
+	tris := MarchingCubes(f, st, ed, N)
+	for _, tri := range tris {
+		tri.P[1], tri.P[2] = tri.P[2], tri.P[1]
+	}
+
+	// 相邻体素分别插值得到的同一个顶点可能有舍入误差，合并后删除退化面使输出网格封闭
+	mesh := NewMesh(tris)
+	mesh.weldVertices(1e-6 * cell)
+	mesh.removeDegenerateFaces()
+	mesh.removeUnusedVertices()
+	return mesh.Triangles()
+}
+
+// Offset 用网格的距离场与MarchingCubes生成偏移曲面，resolution为包围盒最长边上的体素数
+// 封闭网格按带符号距离偏移，distance为正时向外、为负时向内；开放网格(如零厚度的花瓣)生成包围它、到它距离为|distance|的封闭曲面
+// 偏移后的自相交部分由距离场自动消除，输出三角形的法向量指向外侧；判断是否封闭前先合并因舍入误差而分开的顶点(见welded)
+func (m *Mesh) Offset(distance float64, resolution int) []*Triangle {
+	d := newMeshDistance(m.welded())
+	if !d.signed {
+		distance = math.Abs(distance)
+	}
+	return d.isosurface(distance, resolution)
+}
+
+// Shell 生成可打印的实体壁：封闭网格与其偏移曲面(distance为负时向内、为正时向外)组合为厚度|distance|的空心实体，
+// 内侧曲面的法向量朝向空腔，偏移曲面为空(向内偏移超过零件厚度)时返回空切片；开放网格生成以其为中面、厚度为|distance|的封闭实体；与Offset相同地先合并因舍入误差而分开的顶点
+func (m *Mesh) Shell(distance float64, resolution int) []*Triangle {
+	m = m.welded()
+	d := newMeshDistance(m)
+	if !d.signed {
+		return d.isosurface(math.Abs(distance)/2, resolution)
+	}
+
+	offset := d.isosurface(distance, resolution)
+	if len(offset) == 0 {
+		return offset
+	}
+	surface := make([]*Triangle, len(m.Faces))
+	for i, f := range m.Faces {
+		p := [3]*mat.VecDense{mat.VecDenseCopyOf(m.Vertices[f[0]]), mat.VecDenseCopyOf(m.Vertices[f[1]]), mat.VecDenseCopyOf(m.Vertices[f[2]])}
+		if d.outward < 0 {
+			p[1], p[2] = p[2], p[1]
+		}
+		surface[i] = &Triangle{p}
+	}
+
+	// 外壁保持法向量朝外，内壁翻转为朝向空腔
+	inner := surface
+	if distance < 0 {
+		inner = offset
+	}
+	for _, tri := range inner {
+		tri.P[1], tri.P[2] = tri.P[2], tri.P[1]
+	}
+	return append(surface, offset...)
+}
+
+// welded 返回合并了距离不超过包围盒对角线1e-9倍的顶点并删除退化面后的网格副本，不修改m
+// NewMesh只合并坐标完全相同的顶点，MarchingCubes等输出中舍入误差造成的裂缝会使封闭网格被当作开放网格
+func (m *Mesh) welded() *Mesh {
+	res := &Mesh{Vertices: m.Vertices, Faces: append([][3]int(nil), m.Faces...)}
+	res.weldVertices(m.weldTolerance())
+	res.removeDegenerateFaces()
+	return res
+}
+
+// weldTolerance welded合并顶点的距离: 包围盒对角线的1e-9倍，网格为空时为0
+func (m *Mesh) weldTolerance() float64 {
+	if len(m.Vertices) == 0 {
+		return 0
+	}
+	lo, hi := mat.VecDenseCopyOf(m.Vertices[0]), mat.VecDenseCopyOf(m.Vertices[0])
+	for _, p := range m.Vertices {
+		lo, hi = MinVec(lo, p), MaxVec(hi, p)
+	}
+	return 1e-9 * mat.Norm(SubVec(mat.NewVecDense(3, nil), hi, lo), 2)
+}
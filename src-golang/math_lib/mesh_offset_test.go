@@ -0,0 +1,42 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOffsetSphere(t *testing.T) {
+	m := unitSphere(3)
+	for _, distance := range []float64{0.2, -0.3} {
+		tris := m.Offset(distance, 24)
+		r := 1 + distance
+		if v, want := Volume(tris), 4*math.Pi*r*r*r/3; math.Abs(v-want) > 0.05*want {
+			t.Errorf("distance %g: volume %g, want %g", distance, v, want)
+		}
+		if report := NewMesh(tris).Validate(); !report.Watertight || report.Components != 1 || report.Genus != 0 {
+			t.Errorf("distance %g: report %+v", distance, report)
+		}
+	}
+}
+
+func TestShell(t *testing.T) {
+	m := unitSphere(3)
+	tris := m.Shell(-0.2, 24)
+	want := Volume(m.Triangles()) - 4*math.Pi*0.8*0.8*0.8/3
+	if v := Volume(tris); math.Abs(v-want) > 0.05*want {
+		t.Errorf("shell volume %g, want %g", v, want)
+	}
+	if report := NewMesh(tris).Validate(); !report.Watertight || report.Components != 2 || report.InvertedComponents != 1 {
+		t.Errorf("report %+v", report)
+	}
+
+	// 开放网格生成以其为中面的实体，体积约为面积×厚度加上边缘的半圆柱
+	tris = planeGrid(8, 4).Shell(0.2, 24)
+	want = 2*0.2 + 6*math.Pi*0.1*0.1/2
+	if v := Volume(tris); math.Abs(v-want) > 0.1*want {
+		t.Errorf("open shell volume %g, want %g", v, want)
+	}
+	if report := NewMesh(tris).Validate(); !report.Watertight {
+		t.Errorf("open shell report %+v", report)
+	}
+}
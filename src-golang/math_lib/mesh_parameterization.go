@@ -0,0 +1,410 @@
+package math_lib
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"sort"
+)
+
+// ParameterizationOptions 网格参数化的切分与打包参数
+type ParameterizationOptions struct {
+	MaxChartAngle float64  // 同一图表内面法向量与图表平均法向量的最大夹角(弧度)，0表示只按接缝与拓扑切分
+	Seams         [][2]int // 必须切开的边
+	Margin        float64  // 打包后图表之间的间距，相对于[0, 1]²的边长
+}
+
+// UVAtlas 网格的纹理坐标，同一顶点在接缝两侧的不同图表中有不同的纹理坐标
+type UVAtlas struct {
+	UVs     []*mat.VecDense // 位于[0, 1]²内的二维纹理坐标
+	FaceUVs [][3]int        // 每个面的三个角在UVs中的下标
+	Charts  [][]int         // 每个图表包含的面
+}
+
+// Parameterize 计算网格的纹理坐标：先按接缝、非流形边与法向量夹角将面划分为图表，不是拓扑圆盘的图表继续一分为二，
+// 再用LSCM (Lévy et al.)将每个图表共形地展开到平面并缩放到与三维面积相同，最后按行将图表打包到[0, 1]²
+// m的面先被替换为焊接后的面(见welded)，顶点下标不变，FaceUVs与替换后的面对应
+func (m *Mesh) Parameterize(opts ParameterizationOptions) (*UVAtlas, error) {
+	// MarchingCubes等输出中舍入误差造成的裂缝会把曲面切成许多图表，接缝的端点同样映射到合并后的顶点
+	target := m.weldTargets(m.weldTolerance())
+	m.Faces = m.welded().Faces
+
+	pos := make([][3]float64, len(m.Vertices))
+	for i, v := range m.Vertices {
+		pos[i] = [3]float64{v.AtVec(0), v.AtVec(1), v.AtVec(2)}
+	}
+	seams := make(map[[2]int]bool, len(opts.Seams))
+	for _, e := range opts.Seams {
+		seams[edgeKey(target[e[0]], target[e[1]])] = true
+	}
+	edges := m.edgeFaces()
+	adjacent := make([][]int, len(m.Faces))
+	for key, list := range edges {
+		if len(list) == 2 && !seams[key] {
+			adjacent[list[0]] = append(adjacent[list[0]], list[1])
+			adjacent[list[1]] = append(adjacent[list[1]], list[0])
+		}
+	}
+
+	var charts [][]int
+	for _, chart := range m.growCharts(pos, adjacent, opts.MaxChartAngle) {
+		charts = append(charts, m.diskCharts(chart, adjacent)...)
+	}
+
+	atlas := &UVAtlas{FaceUVs: make([][3]int, len(m.Faces)), Charts: charts}
+	uvs := make([][][2]float64, len(charts))
+	base := 0
+	for c, chart := range charts {
+		verts, uv, err := m.lscm(pos, chart)
+		if err != nil {
+			return nil, fmt.Errorf("parameterize: chart %d: %w", c, err)
+		}
+		m.normalizeChart(pos, chart, verts, uv)
+		uvs[c] = uv
+
+		local := make(map[int]int, len(verts))
+		for i, v := range verts {
+			local[v] = base + i
+		}
+		for _, fi := range chart {
+			for k, v := range m.Faces[fi] {
+				atlas.FaceUVs[fi][k] = local[v]
+			}
+		}
+		base += len(verts)
+	}
+
+	for _, p := range packCharts(uvs, opts.Margin) {
+		atlas.UVs = append(atlas.UVs, mat.NewVecDense(2, []float64{p[0], p[1]}))
+	}
+	return atlas, nil
+}
+
+// growCharts 从未分配的面开始广度优先地生长图表，相邻面的法向量与图表的面积加权平均法向量夹角不超过maxAngle时加入
+func (m *Mesh) growCharts(pos [][3]float64, adjacent [][]int, maxAngle float64) [][]int {
+	normals := make([][3]float64, len(m.Faces))
+	for i, f := range m.Faces {
+		normals[i] = cross3(sub3(pos[f[1]], pos[f[0]]), sub3(pos[f[2]], pos[f[0]]))
+	}
+	cos := math.Cos(maxAngle)
+	visited := make([]bool, len(m.Faces))
+	var charts [][]int
+	for start := range m.Faces {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		chart := []int{start}
+		sum := normals[start]
+		for k := 0; k < len(chart); k++ {
+			for _, g := range adjacent[chart[k]] {
+				if visited[g] {
+					continue
+				}
+				if maxAngle > 0 {
+					n := normals[g]
+					length := math.Sqrt(dot3(n, n) * dot3(sum, sum))
+					if length > 0 && dot3(n, sum) < cos*length {
+						continue
+					}
+				}
+				visited[g] = true
+				chart = append(chart, g)
+				for d := 0; d < 3; d++ {
+					sum[d] += normals[g][d]
+				}
+			}
+		}
+		charts = append(charts, chart)
+	}
+	return charts
+}
+
+// diskCharts 将图表反复一分为二，直到每一部分都是拓扑圆盘
+func (m *Mesh) diskCharts(chart []int, adjacent [][]int) [][]int {
+	if len(chart) == 1 || m.isDisk(chart) {
+		return [][]int{chart}
+	}
+	member := make(map[int]int, len(chart))
+	for _, f := range chart {
+		member[f] = -1
+	}
+	bfs := func(seeds ...int) int {
+		for f := range member {
+			member[f] = -1
+		}
+		queue := append([]int(nil), seeds...)
+		for i, s := range seeds {
+			member[s] = i
+		}
+		for k := 0; k < len(queue); k++ {
+			for _, g := range adjacent[queue[k]] {
+				if label, ok := member[g]; ok && label < 0 {
+					member[g] = member[queue[k]]
+					queue = append(queue, g)
+				}
+			}
+		}
+		return queue[len(queue)-1]
+	}
+
+	// 两个相距最远的面分别作为两部分的种子
+	a := bfs(chart[0])
+	b := bfs(a)
+	bfs(a, b)
+	var parts [2][]int
+	for _, f := range chart {
+		parts[member[f]] = append(parts[member[f]], f)
+	}
+	return append(m.diskCharts(parts[0], adjacent), m.diskCharts(parts[1], adjacent)...)
+}
+
+// isDisk 判断面集合是否为拓扑圆盘：流形、只有一条边界且欧拉示性数为1
+func (m *Mesh) isDisk(faces []int) bool {
+	count := make(map[[2]int]int, 3*len(faces)/2)
+	verts := make(map[int]bool, len(faces))
+	for _, fi := range faces {
+		f := m.Faces[fi]
+		for k := 0; k < 3; k++ {
+			count[edgeKey(f[k], f[(k+1)%3])]++
+			verts[f[k]] = true
+		}
+	}
+	if len(verts)-len(count)+len(faces) != 1 {
+		return false
+	}
+	next := make(map[int][]int)
+	for key, c := range count {
+		switch {
+		case c > 2:
+			return false
+		case c == 1:
+			next[key[0]] = append(next[key[0]], key[1])
+			next[key[1]] = append(next[key[1]], key[0])
+		}
+	}
+	if len(next) == 0 {
+		return false
+	}
+	for _, list := range next {
+		if len(list) != 2 {
+			return false
+		}
+	}
+
+	// 沿边界走一圈应经过所有边界顶点
+	var start int
+	for v := range next {
+		start = v
+		break
+	}
+	prev, cur, length := -1, start, 0
+	for {
+		step := next[cur][0]
+		if step == prev {
+			step = next[cur][1]
+		}
+		prev, cur = cur, step
+		length++
+		if cur == start {
+			break
+		}
+	}
+	return length == len(next)
+}
+
+// lscm 用最小二乘共形映射展开一个圆盘图表，固定边界上相距最远的两个顶点，返回图表的顶点及其平面坐标
+// 法方程的条件数随图表增大而变差，共轭梯度法在大图表上不能收敛到足够精度，因此用稀疏Cholesky分解直接求解
+func (m *Mesh) lscm(pos [][3]float64, chart []int) ([]int, [][2]float64, error) {
+	local := make(map[int]int)
+	var verts []int
+	count := make(map[[2]int]int)
+	for _, fi := range chart {
+		f := m.Faces[fi]
+		for k, v := range f {
+			if _, ok := local[v]; !ok {
+				local[v] = len(verts)
+				verts = append(verts, v)
+			}
+			count[edgeKey(v, f[(k+1)%3])]++
+		}
+	}
+	var boundary []int
+	for key, c := range count {
+		if c == 1 {
+			boundary = append(boundary, key[0], key[1])
+		}
+	}
+	farthest := func(from int) int {
+		best := from
+		for _, v := range boundary {
+			if dist3(pos[v], pos[from]) > dist3(pos[best], pos[from]) {
+				best = v
+			}
+		}
+		return best
+	}
+	p0 := farthest(boundary[0])
+	p1 := farthest(p0)
+
+	// 固定顶点的坐标，其余顶点的u、v依次编号
+	uv := make([][2]float64, len(verts))
+	uv[local[p1]] = [2]float64{dist3(pos[p0], pos[p1]), 0}
+	pinned := map[int]bool{local[p0]: true, local[p1]: true}
+	index := make([]int, len(verts))
+	free := 0
+	for i := range verts {
+		index[i] = -1
+		if !pinned[i] {
+			index[i] = free
+			free++
+		}
+	}
+	if free == 0 {
+		return verts, uv, nil
+	}
+
+	// 每个面的共形条件 Σ Wk·(uk + i·vk) = 0 的实部与虚部，Wk为顶点k所对的边在面的局部坐标系中的复数表示除以√(2A)
+	ata := NewSparseMatrix(2 * free)
+	atb := make([]float64, 2*free)
+	type term struct {
+		vertex, axis int
+		value        float64
+	}
+	for _, fi := range chart {
+		f := m.Faces[fi]
+		e1, e2 := sub3(pos[f[1]], pos[f[0]]), sub3(pos[f[2]], pos[f[0]])
+		l1 := math.Sqrt(dot3(e1, e1))
+		c := cross3(e1, e2)
+		area2 := math.Sqrt(dot3(c, c))
+		if l1 == 0 || area2 == 0 {
+			continue
+		}
+		p := [3][2]float64{{0, 0}, {l1, 0}, {dot3(e1, e2) / l1, area2 / l1}}
+		scale := 1 / math.Sqrt(area2)
+		var re, im [6]term
+		for k := 0; k < 3; k++ {
+			a := (p[(k+2)%3][0] - p[(k+1)%3][0]) * scale
+			b := (p[(k+2)%3][1] - p[(k+1)%3][1]) * scale
+			v := local[f[k]]
+			re[2*k], re[2*k+1] = term{v, 0, a}, term{v, 1, -b}
+			im[2*k], im[2*k+1] = term{v, 0, b}, term{v, 1, a}
+		}
+		for _, row := range [2][6]term{re, im} {
+			for _, s := range row {
+				if index[s.vertex] < 0 {
+					continue
+				}
+				i := 2*index[s.vertex] + s.axis
+				for _, t := range row {
+					if index[t.vertex] < 0 {
+						atb[i] -= s.value * t.value * uv[t.vertex][t.axis]
+					} else {
+						ata.Add(i, 2*index[t.vertex]+t.axis, s.value*t.value)
+					}
+				}
+			}
+		}
+	}
+
+	factor, err := ata.Cholesky()
+	if err != nil {
+		return nil, nil, err
+	}
+	x := factor.Solve(atb)
+	for i := range verts {
+		if index[i] >= 0 {
+			uv[i] = [2]float64{x[2*index[i]], x[2*index[i]+1]}
+		}
+	}
+	return verts, uv, nil
+}
+
+// normalizeChart 将展开的图表缩放到与三维面积相同，翻转时镜像，再旋转使主轴沿u方向并平移到第一象限
+func (m *Mesh) normalizeChart(pos [][3]float64, chart []int, verts []int, uv [][2]float64) {
+	local := make(map[int]int, len(verts))
+	for i, v := range verts {
+		local[v] = i
+	}
+	area3, area2 := 0.0, 0.0
+	for _, fi := range chart {
+		f := m.Faces[fi]
+		c := cross3(sub3(pos[f[1]], pos[f[0]]), sub3(pos[f[2]], pos[f[0]]))
+		area3 += math.Sqrt(dot3(c, c)) / 2
+		a, b, d := uv[local[f[0]]], uv[local[f[1]]], uv[local[f[2]]]
+		area2 += ((b[0]-a[0])*(d[1]-a[1]) - (b[1]-a[1])*(d[0]-a[0])) / 2
+	}
+	scale := 1.0
+	if area2 != 0 {
+		scale = math.Sqrt(area3 / math.Abs(area2))
+	}
+	mirror := 1.0
+	if area2 < 0 {
+		mirror = -1
+	}
+
+	var center [2]float64
+	for i := range uv {
+		uv[i] = [2]float64{mirror * scale * uv[i][0], scale * uv[i][1]}
+		center[0] += uv[i][0] / float64(len(uv))
+		center[1] += uv[i][1] / float64(len(uv))
+	}
+	var sxx, sxy, syy float64
+	for _, p := range uv {
+		dx, dy := p[0]-center[0], p[1]-center[1]
+		sxx, sxy, syy = sxx+dx*dx, sxy+dx*dy, syy+dy*dy
+	}
+	theta := math.Atan2(2*sxy, sxx-syy) / 2
+	cs, sn := math.Cos(theta), math.Sin(theta)
+	lo := [2]float64{math.Inf(1), math.Inf(1)}
+	for i, p := range uv {
+		dx, dy := p[0]-center[0], p[1]-center[1]
+		uv[i] = [2]float64{cs*dx + sn*dy, -sn*dx + cs*dy}
+		lo = [2]float64{math.Min(lo[0], uv[i][0]), math.Min(lo[1], uv[i][1])}
+	}
+	for i := range uv {
+		uv[i] = [2]float64{uv[i][0] - lo[0], uv[i][1] - lo[1]}
+	}
+}
+
+// packCharts 按高度从大到小将图表的包围盒逐行排列，再整体缩放到[0, 1]²，返回按图表顺序连接的所有纹理坐标
+func packCharts(charts [][][2]float64, margin float64) [][2]float64 {
+	size := make([][2]float64, len(charts))
+	total, widest := 0.0, 0.0
+	for c, uv := range charts {
+		for _, p := range uv {
+			size[c] = [2]float64{math.Max(size[c][0], p[0]), math.Max(size[c][1], p[1])}
+		}
+		total += size[c][0] * size[c][1]
+		widest = math.Max(widest, size[c][0])
+	}
+	pad := margin * math.Sqrt(total)
+	order := make([]int, len(charts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return size[order[i]][1] > size[order[j]][1] })
+
+	// 每行的宽度取所有包围盒近似排成正方形时的边长
+	width := math.Max(math.Sqrt(total)*1.1, widest) + 2*pad
+	offset := make([][2]float64, len(charts))
+	x, y, row, extent := pad, pad, 0.0, 0.0
+	for _, c := range order {
+		if x > pad && x+size[c][0]+pad > width {
+			x, y, row = pad, y+row+pad, 0
+		}
+		offset[c] = [2]float64{x, y}
+		x += size[c][0] + pad
+		row = math.Max(row, size[c][1])
+		extent = math.Max(extent, x)
+	}
+	extent = math.Max(extent, y+row+pad)
+
+	var res [][2]float64
+	for c, uv := range charts {
+		for _, p := range uv {
+			res = append(res, [2]float64{(p[0] + offset[c][0]) / extent, (p[1] + offset[c][1]) / extent})
+		}
+	}
+	return res
+}
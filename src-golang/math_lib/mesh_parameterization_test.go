@@ -0,0 +1,88 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+)
+
+// checkAtlas 检查纹理坐标位于[0, 1]²内且没有翻转的三角形，返回每个面的纹理面积与三维面积之比
+func checkAtlas(t *testing.T, m *Mesh, atlas *UVAtlas) []float64 {
+	t.Helper()
+	for i, uv := range atlas.UVs {
+		if uv.AtVec(0) < 0 || uv.AtVec(0) > 1 || uv.AtVec(1) < 0 || uv.AtVec(1) > 1 {
+			t.Fatalf("uv %d = %v is outside the unit square", i, uv.RawVector().Data)
+		}
+	}
+	tris := m.Triangles()
+	ratios := make([]float64, len(m.Faces))
+	for i, f := range atlas.FaceUVs {
+		a, b, c := atlas.UVs[f[0]], atlas.UVs[f[1]], atlas.UVs[f[2]]
+		area := ((b.AtVec(0)-a.AtVec(0))*(c.AtVec(1)-a.AtVec(1)) - (b.AtVec(1)-a.AtVec(1))*(c.AtVec(0)-a.AtVec(0))) / 2
+		if area <= 0 {
+			t.Fatalf("face %d is flipped in uv space", i)
+		}
+		ratios[i] = area / SurfaceArea(tris[i:i+1])
+	}
+	return ratios
+}
+
+func TestParameterizePlane(t *testing.T) {
+	m := planeGrid(10, 5)
+	for _, p := range m.Vertices {
+		p.SetVec(2, 0.2*math.Sin(3*p.AtVec(0))*math.Sin(3*p.AtVec(1)))
+	}
+	atlas, err := m.Parameterize(ParameterizationOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(atlas.Charts) != 1 || len(atlas.UVs) != len(m.Vertices) {
+		t.Fatalf("got %d charts and %d uvs", len(atlas.Charts), len(atlas.UVs))
+	}
+	checkAtlas(t, m, atlas)
+
+	// 平面网格的共形展开是相似变换，各面的面积比相同
+	flat := planeGrid(10, 5)
+	atlas, err = flat.Parameterize(ParameterizationOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ratios := checkAtlas(t, flat, atlas)
+	for i, r := range ratios {
+		if math.Abs(r-ratios[0]) > 1e-6*ratios[0] {
+			t.Fatalf("face %d scaled by %g, face 0 by %g", i, r, ratios[0])
+		}
+	}
+}
+
+func TestParameterizeSphere(t *testing.T) {
+	m := unitSphere(2)
+	atlas, err := m.Parameterize(ParameterizationOptions{Margin: 0.01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 球面不是圆盘，至少切成两个图表
+	if len(atlas.Charts) < 2 {
+		t.Errorf("got %d charts", len(atlas.Charts))
+	}
+	faces := 0
+	for _, chart := range atlas.Charts {
+		faces += len(chart)
+	}
+	if faces != len(m.Faces) {
+		t.Errorf("charts cover %d of %d faces", faces, len(m.Faces))
+	}
+	checkAtlas(t, m, atlas)
+}
+
+func TestParameterizeMarchingCubes(t *testing.T) {
+	// 不焊接时舍入误差造成的裂缝把球面切成许多图表
+	m := marchingSphere(16)
+	atlas, err := m.Parameterize(ParameterizationOptions{Margin: 0.01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(atlas.Charts) != 2 {
+		t.Errorf("got %d charts, want 2", len(atlas.Charts))
+	}
+	checkAtlas(t, m, atlas)
+}
@@ -0,0 +1,548 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"sort"
+)
+
+// surfaceProjector 将点投影到固定三角网格上的最近点
+type surfaceProjector struct {
+	tris [][3][3]float64
+	tree *boxTree
+}
+
+func newSurfaceProjector(m *Mesh) *surfaceProjector {
+	s := &surfaceProjector{tris: make([][3][3]float64, len(m.Faces))}
+	boxes := make([]aabb, len(m.Faces))
+	for i, f := range m.Faces {
+		for k, v := range f {
+			s.tris[i][k] = [3]float64{m.Vertices[v].AtVec(0), m.Vertices[v].AtVec(1), m.Vertices[v].AtVec(2)}
+		}
+		boxes[i] = pointsBox(m.Vertices[f[0]], m.Vertices[f[1]], m.Vertices[f[2]])
+	}
+	s.tree = newBoxTree(boxes)
+	return s
+}
+
+// project 返回网格上到p最近的点，网格为空时返回p
+func (s *surfaceProjector) project(p [3]float64) [3]float64 {
+	best, _ := s.tree.nearest(p, func(i int) float64 {
+		q := closestPointOnTriangle(p, s.tris[i][0], s.tris[i][1], s.tris[i][2])
+		return dot3(sub3(q, p), sub3(q, p))
+	})
+	if best < 0 {
+		return p
+	}
+	return closestPointOnTriangle(p, s.tris[best][0], s.tris[best][1], s.tris[best][2])
+}
+
+// closestPointOnTriangle 返回三角形abc上到p最近的点，按p所在的Voronoi区域分类 (Ericson, Real-Time Collision Detection 5.1.5)
+func closestPointOnTriangle(p, a, b, c [3]float64) [3]float64 {
+	lerp := func(x, y [3]float64, t float64) [3]float64 {
+		return [3]float64{x[0] + t*(y[0]-x[0]), x[1] + t*(y[1]-x[1]), x[2] + t*(y[2]-x[2])}
+	}
+	ab, ac, ap := sub3(b, a), sub3(c, a), sub3(p, a)
+	d1, d2 := dot3(ab, ap), dot3(ac, ap)
+	if d1 <= 0 && d2 <= 0 {
+		return a
+	}
+	bp := sub3(p, b)
+	d3, d4 := dot3(ab, bp), dot3(ac, bp)
+	if d3 >= 0 && d4 <= d3 {
+		return b
+	}
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return lerp(a, b, d1/(d1-d3))
+	}
+	cp := sub3(p, c)
+	d5, d6 := dot3(ab, cp), dot3(ac, cp)
+	if d6 >= 0 && d5 <= d6 {
+		return c
+	}
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return lerp(a, c, d2/(d2-d6))
+	}
+	va := d3*d6 - d5*d4
+	if va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		return lerp(b, c, (d4-d3)/((d4-d3)+(d5-d6)))
+	}
+	denom := va + vb + vc
+	if denom == 0 {
+		return a
+	}
+	v, w := vb/denom, vc/denom
+	return [3]float64{a[0] + ab[0]*v + ac[0]*w, a[1] + ab[1]*v + ac[1]*w, a[2] + ab[2]*v + ac[2]*w}
+}
+
+// closestPointOnSegment 返回线段ab上到p最近的点
+func closestPointOnSegment(p, a, b [3]float64) [3]float64 {
+	ab := sub3(b, a)
+	l2 := dot3(ab, ab)
+	if l2 == 0 {
+		return a
+	}
+	t := math.Max(0, math.Min(1, dot3(sub3(p, a), ab)/l2))
+	return [3]float64{a[0] + t*ab[0], a[1] + t*ab[1], a[2] + t*ab[2]}
+}
+
+// remesher 各向同性重新网格化的状态
+type remesher struct {
+	pos      [][3]float64
+	faces    [][3]int
+	boundary []bool
+	edges    map[[2]int][]int
+	lo, hi   float64 // 长于hi的边被分裂，短于lo的边被折叠
+}
+
+// Remesh 各向同性重新网格化(Botsch & Kobbelt)，使边长接近targetLength，每次迭代依次:
+// 分裂长于4/3·targetLength的边，折叠短于4/5·targetLength的边，翻转边使内部顶点的度数接近6(边界顶点接近4)，
+// 沿切平面将顶点移向相邻顶点的重心，最后将顶点投影回原始网格上的最近点；边界顶点只沿边界分裂或折叠，不做切向移动
+// 迭代结束后调整仍在[4/5, 4/3]·targetLength之外的边，输入网格中几乎重合的顶点先被焊接
+func (m *Mesh) Remesh(targetLength float64, iterations int) {
+	if targetLength <= 0 || len(m.Faces) == 0 {
+		return
+	}
+	// MarchingCubes等来源的网格在接缝处有几乎重合的顶点，不焊接时接缝被当作边界，边界边不会被折叠或翻转
+	w := m.welded()
+	projector := newSurfaceProjector(w)
+	r := &remesher{
+		pos:   make([][3]float64, len(w.Vertices)),
+		faces: w.Faces,
+		lo:    0.8 * targetLength,
+		hi:    4.0 / 3 * targetLength,
+	}
+	for i, v := range w.Vertices {
+		r.pos[i] = [3]float64{v.AtVec(0), v.AtVec(1), v.AtVec(2)}
+	}
+
+	for it := 0; it < iterations; it++ {
+		for r.splitLongEdges() {
+		}
+		for r.collapseShortEdges() {
+		}
+		for r.flipEdges() {
+		}
+		r.relax()
+		for v := range r.pos {
+			if !r.boundary[v] {
+				r.pos[v] = projector.project(r.pos[v])
+			}
+		}
+	}
+	// 切向平滑只使边长分布均匀，少量边长仍在[lo, hi]之外: 最后交替调整这些边的端点位置与分裂、折叠，直到所有边长都在范围内
+	for round := 0; iterations > 0 && round < 10 && !r.inRange(); round++ {
+		for k := 0; k < 20 && r.fitLengths(projector); k++ {
+		}
+		for r.splitLongEdges() {
+		}
+		for r.collapseShortEdges() {
+		}
+	}
+
+	m.Vertices = make([]*mat.VecDense, len(r.pos))
+	for i, p := range r.pos {
+		m.Vertices[i] = mat.NewVecDense(3, []float64{p[0], p[1], p[2]})
+	}
+	m.Faces = r.faces
+	m.removeUnusedVertices()
+}
+
+// update 重新统计边与边界顶点
+func (r *remesher) update() {
+	r.edges = make(map[[2]int][]int, 3*len(r.faces)/2)
+	for i, f := range r.faces {
+		for e := 0; e < 3; e++ {
+			key := edgeKey(f[e], f[(e+1)%3])
+			r.edges[key] = append(r.edges[key], i)
+		}
+	}
+	r.boundary = make([]bool, len(r.pos))
+	for key, list := range r.edges {
+		if len(list) != 2 {
+			r.boundary[key[0]], r.boundary[key[1]] = true, true
+		}
+	}
+}
+
+// sortedEdges 返回长度满足条件的边，按长度排序
+func (r *remesher) sortedEdges(keep func(length float64) bool, descending bool) [][2]int {
+	var keys [][2]int
+	length := make(map[[2]int]float64)
+	for key := range r.edges {
+		if l := dist3(r.pos[key[0]], r.pos[key[1]]); keep(l) {
+			keys = append(keys, key)
+			length[key] = l
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if length[keys[i]] != length[keys[j]] {
+			return (length[keys[i]] > length[keys[j]]) == descending
+		}
+		return keys[i][0] < keys[j][0] || (keys[i][0] == keys[j][0] && keys[i][1] < keys[j][1])
+	})
+	return keys
+}
+
+// splitLongEdges 在中点分裂互不共面的长边，返回是否有边被分裂
+func (r *remesher) splitLongEdges() bool {
+	r.update()
+	touched := make([]bool, len(r.faces))
+	split := false
+	for _, key := range r.sortedEdges(func(l float64) bool { return l > r.hi }, true) {
+		list := r.edges[key]
+		if len(list) > 2 || touched[list[0]] || (len(list) == 2 && touched[list[1]]) {
+			continue
+		}
+		a, b := r.pos[key[0]], r.pos[key[1]]
+		mid := len(r.pos)
+		r.pos = append(r.pos, [3]float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2, (a[2] + b[2]) / 2})
+		for _, i := range list {
+			touched[i] = true
+			f := rotateToEdge(r.faces[i], key)
+			r.faces[i] = [3]int{f[0], mid, f[2]}
+			r.faces = append(r.faces, [3]int{mid, f[1], f[2]})
+		}
+		split = true
+	}
+	return split
+}
+
+// rotateToEdge 循环移位面f，使边key(任意方向)位于前两个顶点
+func rotateToEdge(f [3]int, key [2]int) [3]int {
+	for k := 0; k < 3; k++ {
+		x, y := f[k], f[(k+1)%3]
+		if (x == key[0] && y == key[1]) || (x == key[1] && y == key[0]) {
+			return [3]int{x, y, f[(k+2)%3]}
+		}
+	}
+	return f
+}
+
+// collapseShortEdges 折叠互不相邻的短边，返回是否有边被折叠
+// 折叠后不能产生长于hi的边、破坏连接条件或使面翻转；边界顶点保持位置，两端都在边界上时只折叠边界边并保留角点
+func (r *remesher) collapseShortEdges() bool {
+	r.update()
+	incident := make([][]int, len(r.pos))
+	for i, f := range r.faces {
+		for _, v := range f {
+			incident[v] = append(incident[v], i)
+		}
+	}
+
+	// 边界顶点只有在到两个相邻边界顶点连线的距离很小时才能被折叠掉，从而保留边界的角点
+	along := make([][]int, len(r.pos))
+	for key, list := range r.edges {
+		if len(list) == 1 {
+			along[key[0]] = append(along[key[0]], key[1])
+			along[key[1]] = append(along[key[1]], key[0])
+		}
+	}
+	removable := func(v int) bool {
+		if len(along[v]) != 2 {
+			return false
+		}
+		a, b := r.pos[along[v][0]], r.pos[along[v][1]]
+		return dist3(r.pos[v], closestPointOnSegment(r.pos[v], a, b)) <= 0.01*r.lo
+	}
+
+	touched := make([]bool, len(r.pos))
+	dead := make([]bool, len(r.faces))
+	collapsed := false
+	for _, key := range r.sortedEdges(func(l float64) bool { return l < r.lo }, false) {
+		u, v := key[0], key[1]
+		if touched[u] || touched[v] {
+			continue
+		}
+		list := r.edges[key]
+		if r.boundary[u] && r.boundary[v] {
+			if len(list) != 1 {
+				continue
+			}
+			if !removable(v) {
+				if !removable(u) {
+					continue
+				}
+				u, v = v, u
+			}
+		}
+		if r.boundary[v] && !r.boundary[u] {
+			u, v = v, u
+		}
+		p := r.pos[u]
+		if !r.boundary[u] {
+			p = [3]float64{(r.pos[u][0] + r.pos[v][0]) / 2, (r.pos[u][1] + r.pos[v][1]) / 2, (r.pos[u][2] + r.pos[v][2]) / 2}
+		}
+		if !r.canCollapse(u, v, p, list, incident) {
+			continue
+		}
+
+		for _, i := range list {
+			dead[i] = true
+		}
+		for _, i := range incident[v] {
+			for k := range r.faces[i] {
+				if r.faces[i][k] == v {
+					r.faces[i][k] = u
+				}
+			}
+		}
+		r.pos[u] = p
+		for _, x := range []int{u, v} {
+			for _, i := range incident[x] {
+				for _, w := range r.faces[i] {
+					touched[w] = true
+				}
+			}
+		}
+		collapsed = true
+	}
+
+	faces := r.faces[:0]
+	for i, f := range r.faces {
+		if !dead[i] {
+			faces = append(faces, f)
+		}
+	}
+	r.faces = faces
+	return collapsed
+}
+
+// canCollapse 检查将边uv折叠到位置p(保留u)是否满足连接条件、不产生长边且不使面翻转
+func (r *remesher) canCollapse(u, v int, p [3]float64, shared []int, incident [][]int) bool {
+	opposite := make(map[int]bool)
+	for _, i := range shared {
+		opposite[rotateToEdge(r.faces[i], [2]int{u, v})[2]] = true
+	}
+	neighbors := make(map[int]bool)
+	for _, i := range incident[u] {
+		for _, w := range r.faces[i] {
+			neighbors[w] = true
+			if w != u && w != v && dist3(p, r.pos[w]) > r.hi {
+				return false
+			}
+		}
+	}
+	kept := make(map[[3]int]bool)
+	for _, i := range incident[u] {
+		if !faceHas(r.faces[i], v) {
+			kept[sortedTriple(r.faces[i][0], r.faces[i][1], r.faces[i][2])] = true
+		}
+	}
+	for _, i := range incident[v] {
+		f := r.faces[i]
+		if faceHas(f, u) {
+			continue
+		}
+		for k := range f {
+			if f[k] == v {
+				f[k] = u
+			}
+		}
+		if kept[sortedTriple(f[0], f[1], f[2])] {
+			return false
+		}
+		for _, w := range f {
+			if w != u && ((neighbors[w] && !opposite[w]) || dist3(p, r.pos[w]) > r.hi) {
+				return false
+			}
+		}
+	}
+
+	for _, x := range []int{u, v} {
+		for _, i := range incident[x] {
+			f := r.faces[i]
+			if faceHas(f, u) && faceHas(f, v) {
+				continue
+			}
+			before := r.faceNormal(f, -1, p)
+			after := r.faceNormal(f, x, p)
+			if dot3(before, after) <= 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// faceNormal 返回面f的法向量(未归一化)，moved不为-1时将该顶点替换为位置p
+func (r *remesher) faceNormal(f [3]int, moved int, p [3]float64) [3]float64 {
+	var q [3][3]float64
+	for k, v := range f {
+		q[k] = r.pos[v]
+		if v == moved {
+			q[k] = p
+		}
+	}
+	return cross3(sub3(q[1], q[0]), sub3(q[2], q[0]))
+}
+
+// flipEdges 翻转能使四个相关顶点的度数更接近理想值的内部边，每个面每轮最多参与一次翻转，返回是否有边被翻转
+func (r *remesher) flipEdges() bool {
+	r.update()
+	valence := make([]int, len(r.pos))
+	for key := range r.edges {
+		valence[key[0]]++
+		valence[key[1]]++
+	}
+	deviation := func(v, d int) int {
+		ideal := 6
+		if r.boundary[v] {
+			ideal = 4
+		}
+		return int(math.Abs(float64(valence[v] + d - ideal)))
+	}
+
+	touched := make([]bool, len(r.faces))
+	flipped := false
+	for _, key := range r.sortedEdges(func(float64) bool { return true }, false) {
+		list := r.edges[key]
+		if len(list) != 2 || touched[list[0]] || touched[list[1]] {
+			continue
+		}
+		f1, f2 := rotateToEdge(r.faces[list[0]], key), rotateToEdge(r.faces[list[1]], key)
+		if f1[0] != f2[1] {
+			continue // 两侧的面方向不一致
+		}
+		a, b, c, d := f1[0], f1[1], f1[2], f2[2]
+		if c == d || r.edges[edgeKey(c, d)] != nil {
+			continue
+		}
+		before := deviation(a, 0) + deviation(b, 0) + deviation(c, 0) + deviation(d, 0)
+		after := deviation(a, -1) + deviation(b, -1) + deviation(c, 1) + deviation(d, 1)
+		if after >= before {
+			continue
+		}
+
+		// 翻转后的两个面应与原来的两个面朝向一致
+		n := r.faceNormal(f1, -1, [3]float64{})
+		n2 := r.faceNormal(f2, -1, [3]float64{})
+		for k := range n {
+			n[k] += n2[k]
+		}
+		g1, g2 := [3]int{a, d, c}, [3]int{d, b, c}
+		if dot3(n, r.faceNormal(g1, -1, [3]float64{})) <= 0 || dot3(n, r.faceNormal(g2, -1, [3]float64{})) <= 0 {
+			continue
+		}
+
+		r.faces[list[0]], r.faces[list[1]] = g1, g2
+		touched[list[0]], touched[list[1]] = true, true
+		valence[a]--
+		valence[b]--
+		valence[c]++
+		valence[d]++
+		flipped = true
+	}
+	return flipped
+}
+
+// relax 将内部顶点沿切平面移向相邻顶点的重心
+func (r *remesher) relax() {
+	r.update()
+	sum := make([][3]float64, len(r.pos))
+	count := make([]int, len(r.pos))
+	for key := range r.edges {
+		for k := 0; k < 3; k++ {
+			sum[key[0]][k] += r.pos[key[1]][k]
+			sum[key[1]][k] += r.pos[key[0]][k]
+		}
+		count[key[0]]++
+		count[key[1]]++
+	}
+	normals := make([][3]float64, len(r.pos))
+	for _, f := range r.faces {
+		n := r.faceNormal(f, -1, [3]float64{})
+		for _, v := range f {
+			for k := range n {
+				normals[v][k] += n[k]
+			}
+		}
+	}
+
+	next := make([][3]float64, len(r.pos))
+	copy(next, r.pos)
+	for v := range r.pos {
+		n := normals[v]
+		length := math.Sqrt(dot3(n, n))
+		if r.boundary[v] || count[v] == 0 || length == 0 {
+			continue
+		}
+		var d [3]float64
+		for k := range d {
+			d[k] = sum[v][k]/float64(count[v]) - r.pos[v][k]
+			n[k] /= length
+		}
+		t := dot3(d, n)
+		for k := range d {
+			next[v][k] += d[k] - t*n[k]
+		}
+	}
+	r.pos = next
+}
+
+// inRange 判断所有边长是否都在[lo, hi]内
+func (r *remesher) inRange() bool {
+	r.update()
+	for key := range r.edges {
+		if l := dist3(r.pos[key[0]], r.pos[key[1]]); l < r.lo || l > r.hi {
+			return false
+		}
+	}
+	return true
+}
+
+// fitLengths 逐条处理长度在[lo, hi]之外的边，沿边的方向移动内部端点使其长度回到范围内，再投影回原始网格
+// 移动会使面翻转时放弃该边，返回是否有顶点被移动
+func (r *remesher) fitLengths(projector *surfaceProjector) bool {
+	r.update()
+	incident := make([][]int, len(r.pos))
+	for i, f := range r.faces {
+		for _, v := range f {
+			incident[v] = append(incident[v], i)
+		}
+	}
+
+	moved := false
+	for _, key := range r.sortedEdges(func(l float64) bool { return l < r.lo || l > r.hi }, false) {
+		u, v := key[0], key[1]
+		if r.boundary[u] && r.boundary[v] {
+			continue
+		}
+		d := sub3(r.pos[v], r.pos[u])
+		l := math.Sqrt(dot3(d, d))
+		if l == 0 || (l >= r.lo && l <= r.hi) {
+			continue
+		}
+		// 每个内部端点移动差值的一半，一端在边界上时另一端移动全部差值
+		shift := (l - math.Max(1.01*r.lo, math.Min(0.99*r.hi, l))) / l
+		if !r.boundary[u] && !r.boundary[v] {
+			shift /= 2
+		}
+
+		normals := make(map[int][3]float64)
+		for _, x := range key {
+			for _, i := range incident[x] {
+				normals[i] = r.faceNormal(r.faces[i], -1, [3]float64{})
+			}
+		}
+		before := [2][3]float64{r.pos[u], r.pos[v]}
+		if !r.boundary[u] {
+			r.pos[u] = projector.project([3]float64{r.pos[u][0] + shift*d[0], r.pos[u][1] + shift*d[1], r.pos[u][2] + shift*d[2]})
+		}
+		if !r.boundary[v] {
+			r.pos[v] = projector.project([3]float64{r.pos[v][0] - shift*d[0], r.pos[v][1] - shift*d[1], r.pos[v][2] - shift*d[2]})
+		}
+		for i, n := range normals {
+			if dot3(n, r.faceNormal(r.faces[i], -1, [3]float64{})) <= 0 {
+				r.pos[u], r.pos[v] = before[0], before[1]
+				break
+			}
+		}
+		if r.pos[u] != before[0] || r.pos[v] != before[1] {
+			moved = true
+		}
+	}
+	return moved
+}
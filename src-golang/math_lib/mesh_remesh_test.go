@@ -0,0 +1,72 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// edgeLengthRange 返回网格中最短与最长的边长
+func edgeLengthRange(m *Mesh) (lo, hi float64) {
+	lo = math.Inf(1)
+	for e := range m.edgeFaces() {
+		d := math.Sqrt(squaredDistance(m.Vertices[e[0]], m.Vertices[e[1]]))
+		lo, hi = math.Min(lo, d), math.Max(hi, d)
+	}
+	return lo, hi
+}
+
+func TestRemeshSphere(t *testing.T) {
+	const target = 0.15
+	m := unitSphere(2)
+	m.Remesh(target, 5)
+
+	if r := m.Validate(); !r.Watertight || !r.Manifold || !r.ConsistentlyOriented || r.Genus != 0 || r.DegenerateFaces != 0 {
+		t.Errorf("report %+v", r)
+	}
+	if lo, hi := edgeLengthRange(m); lo < 0.8*target || hi > 4.0/3*target {
+		t.Errorf("edge lengths in [%g, %g]", lo, hi)
+	}
+	// 顶点投影到原始的多面体上，不会离开单位球
+	for _, p := range m.Vertices {
+		if r := math.Sqrt(squaredDistance(p, vec3(0, 0, 0))); r > 1+1e-9 || r < 0.9 {
+			t.Fatalf("vertex at radius %g", r)
+		}
+	}
+}
+
+func TestRemeshPlaneKeepsBoundary(t *testing.T) {
+	m := planeGrid(4, 2)
+	m.Remesh(0.1, 5)
+	if area := SurfaceArea(m.Triangles()); math.Abs(area-2) > 1e-9 {
+		t.Errorf("area %g, want 2", area)
+	}
+	if r := m.Validate(); r.BoundaryLoops != 1 || !r.ConsistentlyOriented || r.DegenerateFaces != 0 {
+		t.Errorf("report %+v", r)
+	}
+	for _, p := range m.Vertices {
+		if math.Abs(p.AtVec(2)) > 1e-12 {
+			t.Fatalf("vertex %v left the plane", p.RawVector().Data)
+		}
+	}
+}
+
+// marchingSphere 用MarchingCubes提取半径为1的球面，相邻立方体计算的交点坐标可能有舍入差异
+func marchingSphere(n int) *Mesh {
+	sphere := func(p *mat.VecDense) float64 { return mat.Norm(p, 2) - 1 }
+	return NewMesh(MarchingCubes(sphere, []float64{-1.3, -1.3, -1.3}, []float64{1.3, 1.3, 1.3}, []int{n, n, n}))
+}
+
+func TestRemeshMarchingCubes(t *testing.T) {
+	const target = 0.2
+	m := marchingSphere(16)
+	m.Remesh(target, 5)
+
+	if r := m.Validate(); !r.Watertight || !r.Manifold || r.Genus != 0 || r.DegenerateFaces != 0 {
+		t.Errorf("report %+v", r)
+	}
+	if lo, hi := edgeLengthRange(m); lo < 0.8*target || hi > 4.0/3*target {
+		t.Errorf("edge lengths in [%g, %g], want [%g, %g]", lo, hi, 0.8*target, 4.0/3*target)
+	}
+}
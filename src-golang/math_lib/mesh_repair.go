@@ -0,0 +1,496 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// RepairReport 网格修复过程中各步骤的修改数量
+type RepairReport struct {
+	MergedVertices      int `json:"merged_vertices"`       // 距离在容差内而合并的顶点
+	DegenerateFaces     int `json:"degenerate_faces"`      // 删除的面积为零的面
+	DuplicateFaces      int `json:"duplicate_faces"`       // 删除的重复面
+	NonManifoldFaces    int `json:"non_manifold_faces"`    // 所在边被三个以上的面共享时删除的多余的面
+	NonManifoldVertices int `json:"non_manifold_vertices"` // 为拆开多个扇形而新增的顶点
+	FlippedFaces        int `json:"flipped_faces"`         // 翻转方向的面
+	FilledHoles         int `json:"filled_holes"`          // 填充的边界环
+	AddedFaces          int `json:"added_faces"`           // 填充孔洞新增的面
+	SkippedHoles        int `json:"skipped_holes"`         // 边数超过上限未填充的边界环
+}
+
+// Repair 修复网格并返回修改统计，依次执行:
+// 合并距离不超过tolerance的顶点，删除面积为零的面与重复面，删除非流形边上多余的面并拆开非流形顶点，
+// 统一相邻面的方向，用耳切法填充边数不超过maxHoleEdges的边界环(0表示不限)，最后使封闭部分的法向量指向外侧
+func (m *Mesh) Repair(tolerance float64, maxHoleEdges int) RepairReport {
+	var r RepairReport
+	r.MergedVertices = m.weldVertices(tolerance)
+	r.DegenerateFaces = m.removeDegenerateFaces()
+	r.DuplicateFaces = m.removeDuplicateFaces()
+	r.NonManifoldFaces = m.removeNonManifoldFaces()
+	r.NonManifoldVertices = m.splitNonManifoldVertices()
+
+	original := make(map[[3]int]bool, len(m.Faces))
+	for _, f := range m.Faces {
+		original[rotateFace(f)] = true
+	}
+	m.orientFaces()
+	r.FilledHoles, r.AddedFaces, r.SkippedHoles = m.fillHoles(maxHoleEdges)
+	m.orientOutward()
+	for _, f := range m.Faces {
+		if original[rotateFace([3]int{f[0], f[2], f[1]})] {
+			r.FlippedFaces++
+		}
+	}
+
+	m.removeUnusedVertices()
+	return r
+}
+
+// rotateFace 循环移位使最小下标在首位，方向相同的面得到相同的键
+func rotateFace(v [3]int) [3]int {
+	for v[0] > v[1] || v[0] > v[2] {
+		v = [3]int{v[1], v[2], v[0]}
+	}
+	return v
+}
+
+// weldVertices 合并距离不超过tolerance的顶点，每个顶点并入第一个与之足够接近的顶点，返回合并的顶点数
+func (m *Mesh) weldVertices(tolerance float64) int {
+	target := m.weldTargets(tolerance)
+	merged := 0
+	for i, t := range target {
+		if t != i {
+			merged++
+		}
+	}
+	for i, f := range m.Faces {
+		m.Faces[i] = [3]int{target[f[0]], target[f[1]], target[f[2]]}
+	}
+	return merged
+}
+
+// weldTargets 返回weldVertices中每个顶点并入的顶点，tolerance不大于0时每个顶点对应自身
+func (m *Mesh) weldTargets(tolerance float64) []int {
+	target := make([]int, len(m.Vertices))
+	for i := range target {
+		target[i] = i
+	}
+	if tolerance <= 0 {
+		return target
+	}
+
+	cellOf := func(p *mat.VecDense) [3]int {
+		return [3]int{int(math.Floor(p.AtVec(0) / tolerance)), int(math.Floor(p.AtVec(1) / tolerance)), int(math.Floor(p.AtVec(2) / tolerance))}
+	}
+	grid := make(map[[3]int][]int)
+	for i, p := range m.Vertices {
+		cell := cellOf(p)
+	search:
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				for dz := -1; dz <= 1; dz++ {
+					for _, j := range grid[[3]int{cell[0] + dx, cell[1] + dy, cell[2] + dz}] {
+						if squaredDistance(p, m.Vertices[j]) <= tolerance*tolerance {
+							target[i] = j
+							break search
+						}
+					}
+				}
+			}
+		}
+		if target[i] == i {
+			grid[cell] = append(grid[cell], i)
+		}
+	}
+	return target
+}
+
+// removeDegenerateFaces 删除面积为零的面，返回删除的面数
+// 三个顶点互不相同但共线时，中间的顶点位于最长边上，将最长边另一侧的面在该顶点处一分为二，避免留下T形连接
+func (m *Mesh) removeDegenerateFaces() int {
+	removed := 0
+	for pass := 0; pass <= len(m.Faces); pass++ {
+		edges := m.edgeFaces()
+		drop := make(map[int]bool)
+		var added [][3]int
+		for i, f := range m.Faces {
+			if drop[i] {
+				continue
+			}
+			if f[0] == f[1] || f[1] == f[2] || f[2] == f[0] {
+				drop[i] = true
+				removed++
+				continue
+			}
+			if !collinear3D(m.Vertices[f[0]], m.Vertices[f[1]], m.Vertices[f[2]]) {
+				continue
+			}
+
+			// 与最长边相对的顶点位于其余两点之间
+			mid := 0
+			for k := 1; k < 3; k++ {
+				if squaredDistance(m.Vertices[f[(k+1)%3]], m.Vertices[f[(k+2)%3]]) > squaredDistance(m.Vertices[f[(mid+1)%3]], m.Vertices[f[(mid+2)%3]]) {
+					mid = k
+				}
+			}
+			a, b, c := f[(mid+1)%3], f[(mid+2)%3], f[mid]
+			neighbors := edges[edgeKey(a, b)]
+			conflict := false
+			for _, j := range neighbors {
+				conflict = conflict || drop[j]
+			}
+			if conflict {
+				continue // 相邻的面在本轮已被修改，下一轮再处理
+			}
+
+			drop[i] = true
+			removed++
+			for _, j := range neighbors {
+				if j == i {
+					continue
+				}
+				drop[j] = true
+				g := m.Faces[j]
+				for e := 0; e < 3; e++ {
+					if edgeKey(g[e], g[(e+1)%3]) == edgeKey(a, b) {
+						added = append(added, [3]int{g[e], c, g[(e+2)%3]}, [3]int{c, g[(e+1)%3], g[(e+2)%3]})
+					}
+				}
+			}
+		}
+		if len(drop) == 0 {
+			break
+		}
+
+		faces := added
+		for i, f := range m.Faces {
+			if !drop[i] {
+				faces = append(faces, f)
+			}
+		}
+		m.Faces = faces
+	}
+	return removed
+}
+
+// edgeFaces 返回每条无向边所属的面
+func (m *Mesh) edgeFaces() map[[2]int][]int {
+	edges := make(map[[2]int][]int, 3*len(m.Faces))
+	for i, f := range m.Faces {
+		for e := 0; e < 3; e++ {
+			key := edgeKey(f[e], f[(e+1)%3])
+			edges[key] = append(edges[key], i)
+		}
+	}
+	return edges
+}
+
+// removeDuplicateFaces 删除顶点集合相同的重复面，保留第一个，返回删除的面数
+func (m *Mesh) removeDuplicateFaces() int {
+	seen := make(map[[3]int]bool, len(m.Faces))
+	faces := m.Faces[:0]
+	for _, f := range m.Faces {
+		key := sortedTriple(f[0], f[1], f[2])
+		if !seen[key] {
+			seen[key] = true
+			faces = append(faces, f)
+		}
+	}
+	removed := len(m.Faces) - len(faces)
+	m.Faces = faces
+	return removed
+}
+
+// removeNonManifoldFaces 对被三个以上的面共享的边，保留第一个面及其后第一个与之方向一致(在该边上方向相反)的面，删除其余的面，返回删除的面数
+// 没有方向一致的面时保留前两个面，由orientFaces统一方向；按面的顺序处理各边，使结果确定
+func (m *Mesh) removeNonManifoldFaces() int {
+	edges := m.edgeFaces()
+	drop := make(map[int]bool)
+	for _, f := range m.Faces {
+		for e := 0; e < 3; e++ {
+			var list []int
+			for _, i := range edges[edgeKey(f[e], f[(e+1)%3])] {
+				if !drop[i] {
+					list = append(list, i)
+				}
+			}
+			if len(list) <= 2 {
+				continue
+			}
+
+			first := m.Faces[list[0]]
+			u, v := f[e], f[(e+1)%3]
+			if !hasDirectedEdge(first, u, v) {
+				u, v = v, u
+			}
+			keep := list[1]
+			for _, i := range list[1:] {
+				if hasDirectedEdge(m.Faces[i], v, u) {
+					keep = i
+					break
+				}
+			}
+			for _, i := range list[1:] {
+				if i != keep {
+					drop[i] = true
+				}
+			}
+		}
+	}
+
+	faces := m.Faces[:0]
+	for i, f := range m.Faces {
+		if !drop[i] {
+			faces = append(faces, f)
+		}
+	}
+	m.Faces = faces
+	return len(drop)
+}
+
+// splitNonManifoldVertices 顶点周围的面经过该顶点的边连成多个扇形时，为第二个及之后的扇形复制该顶点，返回新增的顶点数
+func (m *Mesh) splitNonManifoldVertices() int {
+	added := 0
+	for v, faces := range m.incidentFaces() {
+		copies := make(map[int]int)
+		for k, root := range m.vertexFans(v, faces) {
+			id, ok := copies[root]
+			if !ok {
+				id = v
+				if len(copies) > 0 {
+					id = len(m.Vertices)
+					m.Vertices = append(m.Vertices, mat.VecDenseCopyOf(m.Vertices[v]))
+					added++
+				}
+				copies[root] = id
+			}
+			i := faces[k]
+			for j := range m.Faces[i] {
+				if m.Faces[i][j] == v {
+					m.Faces[i][j] = id
+				}
+			}
+		}
+	}
+	return added
+}
+
+// incidentFaces 返回每个顶点所属的面
+func (m *Mesh) incidentFaces() [][]int {
+	incident := make([][]int, len(m.Vertices))
+	for i, f := range m.Faces {
+		for _, v := range f {
+			incident[v] = append(incident[v], i)
+		}
+	}
+	return incident
+}
+
+// vertexFans 将顶点v周围的面分成扇形，共享一条经过v的边的两个面属于同一个扇形，返回faces中每个面所在扇形的代表面
+func (m *Mesh) vertexFans(v int, faces []int) []int {
+	parent := make([]int, len(faces))
+	for k := range parent {
+		parent[k] = k
+	}
+	var find func(k int) int
+	find = func(k int) int {
+		if parent[k] != k {
+			parent[k] = find(parent[k])
+		}
+		return parent[k]
+	}
+
+	byNeighbor := make(map[int]int)
+	for k, i := range faces {
+		for _, w := range m.Faces[i] {
+			if w == v {
+				continue
+			}
+			if j, ok := byNeighbor[w]; ok {
+				parent[find(k)] = find(j)
+			} else {
+				byNeighbor[w] = k
+			}
+		}
+	}
+
+	roots := make([]int, len(faces))
+	for k := range faces {
+		roots[k] = faces[find(k)]
+	}
+	return roots
+}
+
+// orientFaces 在每个连通部分内沿共享边广度优先遍历，使相邻面在公共边上的方向相反
+func (m *Mesh) orientFaces() {
+	edges := m.edgeFaces()
+	visited := make([]bool, len(m.Faces))
+	for start := range m.Faces {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		queue := []int{start}
+		for len(queue) > 0 {
+			i := queue[0]
+			queue = queue[1:]
+			f := m.Faces[i]
+			for e := 0; e < 3; e++ {
+				u, v := f[e], f[(e+1)%3]
+				for _, j := range edges[edgeKey(u, v)] {
+					if visited[j] {
+						continue
+					}
+					visited[j] = true
+					if hasDirectedEdge(m.Faces[j], u, v) {
+						m.Faces[j][1], m.Faces[j][2] = m.Faces[j][2], m.Faces[j][1]
+					}
+					queue = append(queue, j)
+				}
+			}
+		}
+	}
+}
+
+// hasDirectedEdge 判断面f的顶点顺序中是否包含有向边u->v
+func hasDirectedEdge(f [3]int, u, v int) bool {
+	for e := 0; e < 3; e++ {
+		if f[e] == u && f[(e+1)%3] == v {
+			return true
+		}
+	}
+	return false
+}
+
+// fillHoles 沿边界边找出边界环，用耳切法三角化后加入网格，新增面的方向与相邻面一致
+// 返回填充的环数、新增的面数与因超过maxHoleEdges而跳过的环数；耳切法失败时以环的重心为中心做扇形填充
+func (m *Mesh) fillHoles(maxHoleEdges int) (filled, added, skipped int) {
+	next := make(map[int]int)
+	for _, e := range m.BoundaryEdges() {
+		next[e[0]] = e[1]
+	}
+
+	// 按BoundaryEdges的顺序寻找边界环，使新增面的顺序在多次运行间保持一致
+	visited := make(map[int]bool)
+	for _, e := range m.BoundaryEdges() {
+		start := e[0]
+		if visited[start] {
+			continue
+		}
+		var loop []int
+		for v := start; !visited[v]; {
+			visited[v] = true
+			loop = append(loop, v)
+			w, ok := next[v]
+			if !ok {
+				break
+			}
+			v = w
+		}
+		if len(loop) < 3 || next[loop[len(loop)-1]] != start {
+			continue
+		}
+		if maxHoleEdges > 0 && len(loop) > maxHoleEdges {
+			skipped++
+			continue
+		}
+
+		// 新增面包含边界边的反向边，因此按相反顺序三角化
+		for i, j := 0, len(loop)-1; i < j; i, j = i+1, j-1 {
+			loop[i], loop[j] = loop[j], loop[i]
+		}
+		faces := m.triangulateLoop(loop)
+		m.Faces = append(m.Faces, faces...)
+		filled++
+		added += len(faces)
+	}
+	return filled, added, skipped
+}
+
+// triangulateLoop 三角化一个边界环，三角形的方向与环的绕向满足右手法则
+func (m *Mesh) triangulateLoop(loop []int) [][3]int {
+	if len(loop) == 3 {
+		return [][3]int{{loop[0], loop[1], loop[2]}}
+	}
+
+	ring := make([]*mat.VecDense, len(loop))
+	index := make(map[*mat.VecDense]int, len(loop))
+	for i, v := range loop {
+		ring[i] = m.Vertices[v]
+		index[ring[i]] = v
+	}
+	if tris, err := PlanarEarClippingTriangulation(ring); err == nil && len(tris) == len(loop)-2 {
+		res := make([][3]int, len(tris))
+		for i, tri := range tris {
+			res[i] = [3]int{index[tri.P[0]], index[tri.P[1]], index[tri.P[2]]}
+		}
+		return res
+	}
+
+	center := AddVecs(mat.NewVecDense(3, nil), ring...)
+	center.ScaleVec(1/float64(len(ring)), center)
+	c := len(m.Vertices)
+	m.Vertices = append(m.Vertices, center)
+	res := make([][3]int, len(loop))
+	for i := range loop {
+		res[i] = [3]int{loop[i], loop[(i+1)%len(loop)], c}
+	}
+	return res
+}
+
+// orientOutward 翻转有向体积为负的封闭连通部分，使其法向量指向外侧
+func (m *Mesh) orientOutward() {
+	for _, component := range m.components() {
+		if m.closedVolume(component) < 0 {
+			for _, i := range component {
+				m.Faces[i][1], m.Faces[i][2] = m.Faces[i][2], m.Faces[i][1]
+			}
+		}
+	}
+}
+
+// components 返回通过共享边连通的各部分所包含的面
+func (m *Mesh) components() [][]int {
+	edges := m.edgeFaces()
+	visited := make([]bool, len(m.Faces))
+	var res [][]int
+	for start := range m.Faces {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		component := []int{start}
+		for k := 0; k < len(component); k++ {
+			f := m.Faces[component[k]]
+			for e := 0; e < 3; e++ {
+				for _, j := range edges[edgeKey(f[e], f[(e+1)%3])] {
+					if !visited[j] {
+						visited[j] = true
+						component = append(component, j)
+					}
+				}
+			}
+		}
+		res = append(res, component)
+	}
+	return res
+}
+
+// removeUnusedVertices 删除没有被任何面使用的顶点并重新编号
+func (m *Mesh) removeUnusedVertices() {
+	id := make([]int, len(m.Vertices))
+	for i := range id {
+		id[i] = -1
+	}
+	var vertices []*mat.VecDense
+	for i, f := range m.Faces {
+		for k, v := range f {
+			if id[v] < 0 {
+				id[v] = len(vertices)
+				vertices = append(vertices, m.Vertices[v])
+			}
+			m.Faces[i][k] = id[v]
+		}
+	}
+	m.Vertices = vertices
+}
@@ -0,0 +1,48 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRepair(t *testing.T) {
+	box := boxMesh(0, 0, 0, 2, 2, 2)
+	// 缺少第一个面，重复第四个面，翻转第六个面，并使一个顶点略微偏离
+	tris := append([]*Triangle{}, box[1:]...)
+	tris = append(tris, box[3])
+	tris[4] = &Triangle{[3]*mat.VecDense{tris[4].P[0], tris[4].P[2], tris[4].P[1]}}
+	moved := mat.VecDenseCopyOf(tris[6].P[0])
+	moved.SetVec(0, moved.AtVec(0)+1e-7)
+	tris[6] = &Triangle{[3]*mat.VecDense{moved, tris[6].P[1], tris[6].P[2]}}
+
+	m := NewMesh(tris)
+	r := m.Repair(1e-6, 0)
+	if r.MergedVertices != 1 || r.DuplicateFaces != 1 || r.FlippedFaces != 1 || r.FilledHoles != 1 || r.AddedFaces != 1 {
+		t.Errorf("report %+v", r)
+	}
+	if len(m.Vertices) != 8 || len(m.Faces) != 12 {
+		t.Fatalf("got %d vertices and %d faces, want 8 and 12", len(m.Vertices), len(m.Faces))
+	}
+	if b := m.BoundaryEdges(); len(b) != 0 {
+		t.Errorf("%d boundary edges after repair", len(b))
+	}
+	if v := enclosedVolume(m.Triangles()); math.Abs(v-8) > 1e-6 {
+		t.Errorf("volume %g, want 8", v)
+	}
+}
+
+func TestRepairInsideOut(t *testing.T) {
+	var tris []*Triangle
+	for _, tri := range boxMesh(0, 0, 0, 1, 1, 1) {
+		tris = append(tris, &Triangle{[3]*mat.VecDense{tri.P[0], tri.P[2], tri.P[1]}})
+	}
+	m := NewMesh(tris)
+	if r := m.Repair(0, 0); r.FlippedFaces != 12 {
+		t.Errorf("flipped %d faces, want 12", r.FlippedFaces)
+	}
+	if v := enclosedVolume(m.Triangles()); math.Abs(v-1) > 1e-12 {
+		t.Errorf("volume %g, want 1", v)
+	}
+}
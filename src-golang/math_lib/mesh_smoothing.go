@@ -0,0 +1,256 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// LaplacianWeights 拉普拉斯平滑中相邻顶点的权重
+type LaplacianWeights int
+
+const (
+	UniformWeights   LaplacianWeights = iota // 所有相邻顶点权重相同
+	CotangentWeights                         // 边所对两个角的余切之和，负权重取0，减少切向漂移
+)
+
+// meshSmoother 平滑过程中不变的拓扑信息
+type meshSmoother struct {
+	m         *Mesh
+	pos       [][3]float64
+	edges     [][2]int
+	faceEdges [][3]int // 每个面中与第k个顶点相对的边在edges中的下标
+	locked    []bool
+}
+
+// newMeshSmoother 先将m的面替换为焊接后的面(见welded)，顶点下标不变，locked仍然有效；
+// 否则MarchingCubes等输出在舍入误差造成的裂缝两侧各自平滑，接缝处会裂开
+func newMeshSmoother(m *Mesh, locked []bool) *meshSmoother {
+	m.Faces = m.welded().Faces
+	s := &meshSmoother{m: m, pos: make([][3]float64, len(m.Vertices)), faceEdges: make([][3]int, len(m.Faces)), locked: locked}
+	for i, v := range m.Vertices {
+		s.pos[i] = [3]float64{v.AtVec(0), v.AtVec(1), v.AtVec(2)}
+	}
+	if s.locked == nil {
+		s.locked = make([]bool, len(m.Vertices))
+	}
+	index := make(map[[2]int]int, 3*len(m.Faces)/2)
+	for i, f := range m.Faces {
+		for k := 0; k < 3; k++ {
+			key := edgeKey(f[(k+1)%3], f[(k+2)%3])
+			id, ok := index[key]
+			if !ok {
+				id = len(s.edges)
+				index[key] = id
+				s.edges = append(s.edges, key)
+			}
+			s.faceEdges[i][k] = id
+		}
+	}
+	return s
+}
+
+// store 将平滑后的坐标写回网格
+func (s *meshSmoother) store() {
+	for i, p := range s.pos {
+		if !s.locked[i] {
+			s.m.Vertices[i] = mat.NewVecDense(3, []float64{p[0], p[1], p[2]})
+		}
+	}
+}
+
+// edgeWeights 计算每条边的权重
+func (s *meshSmoother) edgeWeights(weights LaplacianWeights) []float64 {
+	w := make([]float64, len(s.edges))
+	if weights == UniformWeights {
+		for i := range w {
+			w[i] = 1
+		}
+		return w
+	}
+	for i, f := range s.m.Faces {
+		for k := 0; k < 3; k++ {
+			a, b := sub3(s.pos[f[(k+1)%3]], s.pos[f[k]]), sub3(s.pos[f[(k+2)%3]], s.pos[f[k]])
+			c := cross3(a, b)
+			if area := math.Sqrt(dot3(c, c)); area > 0 {
+				w[s.faceEdges[i][k]] += dot3(a, b) / area / 2
+			}
+		}
+	}
+	for i := range w {
+		w[i] = math.Max(w[i], 0)
+	}
+	return w
+}
+
+// step 将每个未锁定的顶点向相邻顶点的加权平均移动factor倍的距离，factor为负时向外膨胀
+func (s *meshSmoother) step(weights LaplacianWeights, factor float64) {
+	w := s.edgeWeights(weights)
+	sum := make([][3]float64, len(s.pos))
+	total := make([]float64, len(s.pos))
+	for i, e := range s.edges {
+		for k := 0; k < 3; k++ {
+			sum[e[0]][k] += w[i] * s.pos[e[1]][k]
+			sum[e[1]][k] += w[i] * s.pos[e[0]][k]
+		}
+		total[e[0]] += w[i]
+		total[e[1]] += w[i]
+	}
+	for i := range s.pos {
+		if s.locked[i] || total[i] == 0 {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			s.pos[i][k] += factor * (sum[i][k]/total[i] - s.pos[i][k])
+		}
+	}
+}
+
+// LaplacianSmooth 拉普拉斯平滑，每次迭代将顶点向相邻顶点的加权平均移动lambda倍的距离(0 < lambda ≤ 1)
+// locked不为nil时其中为true的顶点保持不动，可用FeatureVertices锁定特征边；多次迭代会使网格收缩
+func (m *Mesh) LaplacianSmooth(weights LaplacianWeights, lambda float64, iterations int, locked []bool) {
+	s := newMeshSmoother(m, locked)
+	for it := 0; it < iterations; it++ {
+		s.step(weights, lambda)
+	}
+	s.store()
+}
+
+// TaubinSmooth Taubin λ|μ平滑，每次迭代先以lambda收缩再以mu(mu < -lambda < 0)膨胀，在去除高频噪声的同时避免整体收缩
+func (m *Mesh) TaubinSmooth(weights LaplacianWeights, lambda, mu float64, iterations int, locked []bool) {
+	s := newMeshSmoother(m, locked)
+	for it := 0; it < iterations; it++ {
+		s.step(weights, lambda)
+		s.step(weights, mu)
+	}
+	s.store()
+}
+
+// BilateralSmooth 双边法向量滤波(Zheng et al.)：先对面法向量做normalIterations次双边滤波，
+// 权重为相邻面(共享顶点)的面积、重心距离的高斯函数(σ取相邻面重心的平均距离)与法向量差的高斯函数(σ = sigmaNormal)之积，
+// 再做vertexIterations次顶点更新使各面与滤波后的法向量一致；能在去噪的同时保持尖锐特征
+func (m *Mesh) BilateralSmooth(normalIterations, vertexIterations int, sigmaNormal float64, locked []bool) {
+	s := newMeshSmoother(m, locked)
+	incident := m.incidentFaces()
+
+	// 共享顶点的相邻面
+	adjacent := make([][]int, len(m.Faces))
+	for i, f := range m.Faces {
+		seen := map[int]bool{i: true}
+		for _, v := range f {
+			for _, j := range incident[v] {
+				if !seen[j] {
+					seen[j] = true
+					adjacent[i] = append(adjacent[i], j)
+				}
+			}
+		}
+	}
+
+	normals := make([][3]float64, len(m.Faces))
+	centers := make([][3]float64, len(m.Faces))
+	areas := make([]float64, len(m.Faces))
+	sigmaCenter, pairs := 0.0, 0
+	for i, f := range m.Faces {
+		normals[i], areas[i] = s.faceNormal(f)
+		centers[i] = s.faceCenter(f)
+	}
+	for i := range m.Faces {
+		for _, j := range adjacent[i] {
+			sigmaCenter += dist3(centers[i], centers[j])
+			pairs++
+		}
+	}
+	if pairs == 0 || sigmaNormal <= 0 {
+		return
+	}
+	sigmaCenter /= float64(pairs)
+
+	for it := 0; it < normalIterations; it++ {
+		filtered := make([][3]float64, len(m.Faces))
+		for i := range m.Faces {
+			n := normals[i]
+			for k := range n {
+				n[k] *= areas[i]
+			}
+			for _, j := range adjacent[i] {
+				dc, dn := dist3(centers[i], centers[j]), dist3(normals[i], normals[j])
+				w := areas[j] * math.Exp(-dc*dc/(2*sigmaCenter*sigmaCenter)) * math.Exp(-dn*dn/(2*sigmaNormal*sigmaNormal))
+				for k := range n {
+					n[k] += w * normals[j][k]
+				}
+			}
+			if length := math.Sqrt(dot3(n, n)); length > 0 {
+				filtered[i] = [3]float64{n[0] / length, n[1] / length, n[2] / length}
+			}
+		}
+		normals = filtered
+	}
+
+	// 顶点沿滤波后的法向量移动到各相邻面重心所在平面的平均位置
+	for it := 0; it < vertexIterations; it++ {
+		for i, f := range m.Faces {
+			centers[i] = s.faceCenter(f)
+		}
+		next := make([][3]float64, len(s.pos))
+		copy(next, s.pos)
+		for v, faces := range incident {
+			if s.locked[v] || len(faces) == 0 {
+				continue
+			}
+			for _, i := range faces {
+				d := dot3(normals[i], sub3(centers[i], s.pos[v])) / float64(len(faces))
+				for k := 0; k < 3; k++ {
+					next[v][k] += d * normals[i][k]
+				}
+			}
+		}
+		s.pos = next
+	}
+	s.store()
+}
+
+// faceNormal 返回面的单位法向量与面积
+func (s *meshSmoother) faceNormal(f [3]int) ([3]float64, float64) {
+	n := cross3(sub3(s.pos[f[1]], s.pos[f[0]]), sub3(s.pos[f[2]], s.pos[f[0]]))
+	length := math.Sqrt(dot3(n, n))
+	if length == 0 {
+		return n, 0
+	}
+	return [3]float64{n[0] / length, n[1] / length, n[2] / length}, length / 2
+}
+
+// faceCenter 返回面的重心
+func (s *meshSmoother) faceCenter(f [3]int) [3]float64 {
+	a, b, c := s.pos[f[0]], s.pos[f[1]], s.pos[f[2]]
+	return [3]float64{(a[0] + b[0] + c[0]) / 3, (a[1] + b[1] + c[1]) / 3, (a[2] + b[2] + c[2]) / 3}
+}
+
+// FeatureVertices 标记位于特征边上的顶点：相邻两个面的法向量夹角超过angle(弧度)的边，以及边界边与非流形边
+// 与平滑相同地按焊接后的面判断，舍入误差造成的裂缝不会被当作边界
+func (m *Mesh) FeatureVertices(angle float64) []bool {
+	res := make([]bool, len(m.Vertices))
+	cos := math.Cos(angle)
+	w := m.welded()
+	for key, list := range w.edgeFaces() {
+		feature := len(list) != 2
+		if !feature {
+			n1 := w.faceNormal(list[0])
+			n2 := w.faceNormal(list[1])
+			feature = mat.Dot(n1, n2) < cos
+		}
+		if feature {
+			res[key[0]], res[key[1]] = true, true
+		}
+	}
+	return res
+}
+
+// faceNormal 返回第i个面的单位法向量，面积为零时返回零向量
+func (m *Mesh) faceNormal(i int) *mat.VecDense {
+	f := m.Faces[i]
+	n := Cross2(SubVec(mat.NewVecDense(3, nil), m.Vertices[f[1]], m.Vertices[f[0]]), SubVec(mat.NewVecDense(3, nil), m.Vertices[f[2]], m.Vertices[f[0]]))
+	if length := mat.Norm(n, 2); length > 0 {
+		n.ScaleVec(1/length, n)
+	}
+	return n
+}
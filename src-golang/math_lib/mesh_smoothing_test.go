@@ -0,0 +1,98 @@
+package math_lib
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// noisySphere 返回顶点沿半径方向加入随机扰动的单位球网格
+func noisySphere(levels int, noise float64) *Mesh {
+	m := unitSphere(levels)
+	rng := rand.New(rand.NewSource(1))
+	for _, p := range m.Vertices {
+		p.ScaleVec(1+noise*(2*rng.Float64()-1), p)
+	}
+	return m
+}
+
+// radiusDeviation 返回顶点到原点距离的标准差
+func radiusDeviation(m *Mesh) float64 {
+	sum, sum2 := 0.0, 0.0
+	for _, p := range m.Vertices {
+		r := math.Sqrt(p.AtVec(0)*p.AtVec(0) + p.AtVec(1)*p.AtVec(1) + p.AtVec(2)*p.AtVec(2))
+		sum += r
+		sum2 += r * r
+	}
+	n := float64(len(m.Vertices))
+	return math.Sqrt(sum2/n - sum*sum/n/n)
+}
+
+func TestLaplacianAndTaubinSmooth(t *testing.T) {
+	// 去除噪声后应恢复到未加噪声的球的体积
+	original := Volume(unitSphere(3).Triangles())
+
+	m := noisySphere(3, 0.05)
+	m.LaplacianSmooth(UniformWeights, 0.5, 20, nil)
+	if volume := Volume(m.Triangles()); volume > 0.9*original {
+		t.Errorf("Laplacian smoothing kept volume %g of %g", volume, original)
+	}
+
+	for _, weights := range []LaplacianWeights{UniformWeights, CotangentWeights} {
+		m = noisySphere(3, 0.05)
+		before := radiusDeviation(m)
+		m.TaubinSmooth(weights, 0.33, -0.34, 30, nil)
+		if after := radiusDeviation(m); after > before/2 {
+			t.Errorf("weights %d: radius deviation %g -> %g", weights, before, after)
+		}
+		if volume := Volume(m.Triangles()); math.Abs(volume-original) > 0.03*original {
+			t.Errorf("weights %d: Taubin smoothing changed the volume from %g to %g", weights, original, volume)
+		}
+	}
+}
+
+func TestBilateralSmoothKeepsEdges(t *testing.T) {
+	m := NewMesh(boxMesh(0, 0, 0, 1, 1, 1))
+	m.Subdivide(SubdivisionMidpoint, 3, nil)
+	rng := rand.New(rand.NewSource(1))
+	for _, p := range m.Vertices {
+		for i := 0; i < 3; i++ {
+			p.SetVec(i, p.AtVec(i)+0.01*(2*rng.Float64()-1))
+		}
+	}
+
+	m.BilateralSmooth(10, 20, 0.35, nil)
+	// 平滑后的面应与最近的立方体表面平行，且体积基本不变
+	flat := 0
+	for i := range m.Faces {
+		n := m.faceNormal(i)
+		if math.Max(math.Abs(n.AtVec(0)), math.Max(math.Abs(n.AtVec(1)), math.Abs(n.AtVec(2)))) > 0.99 {
+			flat++
+		}
+	}
+	if flat < 9*len(m.Faces)/10 {
+		t.Errorf("%d of %d faces aligned with the box", flat, len(m.Faces))
+	}
+	if volume := Volume(m.Triangles()); math.Abs(volume-1) > 0.03 {
+		t.Errorf("volume %g", volume)
+	}
+}
+
+func TestSmoothMarchingCubes(t *testing.T) {
+	// MarchingCubes的输出在相邻立方体之间有舍入误差造成的裂缝，平滑前需要焊接
+	original := math.Abs(Volume(marchingSphere(16).Triangles()))
+	for name, smooth := range map[string]func(m *Mesh){
+		"laplacian": func(m *Mesh) { m.LaplacianSmooth(UniformWeights, 0.5, 5, nil) },
+		"taubin":    func(m *Mesh) { m.TaubinSmooth(UniformWeights, 0.33, -0.34, 30, nil) },
+		"bilateral": func(m *Mesh) { m.BilateralSmooth(5, 10, 0.4, nil) },
+	} {
+		m := marchingSphere(16)
+		smooth(m)
+		if r := m.Validate(); !r.Watertight || r.SelfIntersections != 0 {
+			t.Errorf("%s: watertight %v, %d self-intersections", name, r.Watertight, r.SelfIntersections)
+		}
+		if volume := math.Abs(Volume(m.Triangles())); name == "taubin" && math.Abs(volume-original) > 0.03*original {
+			t.Errorf("taubin: volume %g, want %g", volume, original)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// SubdivisionScheme 三角网格细分方法
+type SubdivisionScheme int
+
+const (
+	SubdivisionLoop     SubdivisionScheme = iota // Loop细分，每个面分为4个，逼近光滑曲面
+	SubdivisionMidpoint                          // 中点细分，每个面分为4个，不改变曲面形状
+	SubdivisionSqrt3                             // √3细分，每个面分为3个并翻转原有的边
+)
+
+// Subdivide 对网格进行iterations次细分
+// creases为需要保持尖锐的边(顶点下标对)，边界边与非流形边也按尖锐边处理：尖锐边上的点只由尖锐边上的点计算，
+// 恰有两条尖锐边经过的顶点沿尖锐边按曲线规则移动，三条以上尖锐边经过的顶点(角点)保持不动
+func (m *Mesh) Subdivide(scheme SubdivisionScheme, iterations int, creases [][2]int) {
+	sharp := make(map[[2]int]bool, len(creases))
+	for _, e := range creases {
+		sharp[edgeKey(e[0], e[1])] = true
+	}
+	for it := 0; it < iterations; it++ {
+		switch scheme {
+		case SubdivisionLoop, SubdivisionMidpoint:
+			sharp = m.loopStep(sharp, scheme == SubdivisionLoop)
+		case SubdivisionSqrt3:
+			// 尖锐边每两次细分三等分一次
+			sharp = m.sqrt3Step(sharp, it%2 == 1)
+		}
+	}
+}
+
+// subdivisionTopology 细分时使用的边与尖锐边信息
+type subdivisionTopology struct {
+	edges     map[[2]int][]int
+	sharp     map[[2]int]bool
+	neighbors [][]int // 每个顶点的相邻顶点
+	creases   [][]int // 每个顶点经过尖锐边相邻的顶点
+}
+
+// newSubdivisionTopology 统计网格的边，只保留网格中存在的尖锐边，并将边界边与非流形边加入尖锐边
+func (m *Mesh) newSubdivisionTopology(sharp map[[2]int]bool) *subdivisionTopology {
+	t := &subdivisionTopology{
+		edges:     m.edgeFaces(),
+		sharp:     make(map[[2]int]bool),
+		neighbors: make([][]int, len(m.Vertices)),
+		creases:   make([][]int, len(m.Vertices)),
+	}
+	for key, list := range t.edges {
+		t.neighbors[key[0]] = append(t.neighbors[key[0]], key[1])
+		t.neighbors[key[1]] = append(t.neighbors[key[1]], key[0])
+		if sharp[key] || len(list) != 2 {
+			t.sharp[key] = true
+			t.creases[key[0]] = append(t.creases[key[0]], key[1])
+			t.creases[key[1]] = append(t.creases[key[1]], key[0])
+		}
+	}
+	return t
+}
+
+// oppositeVertex 返回面f中不在边key上的顶点
+func oppositeVertex(f [3]int, key [2]int) int {
+	for _, v := range f {
+		if v != key[0] && v != key[1] {
+			return v
+		}
+	}
+	return f[0]
+}
+
+// weightedSum 计算 Σ weights[i]·points[i]
+func weightedSum(weights []float64, points ...*mat.VecDense) *mat.VecDense {
+	res := mat.NewVecDense(3, nil)
+	for i, p := range points {
+		res.AddScaledVec(res, weights[i], p)
+	}
+	return res
+}
+
+// loopStep 进行一次Loop细分(smooth为false时为中点细分)，返回细分后网格中的尖锐边
+func (m *Mesh) loopStep(sharp map[[2]int]bool, smooth bool) map[[2]int]bool {
+	t := m.newSubdivisionTopology(sharp)
+	vertices := make([]*mat.VecDense, len(m.Vertices), len(m.Vertices)+len(t.edges))
+	for v, p := range m.Vertices {
+		vertices[v] = p
+		if !smooth {
+			continue
+		}
+		switch n := len(t.neighbors[v]); {
+		case len(t.creases[v]) == 2:
+			a, b := m.Vertices[t.creases[v][0]], m.Vertices[t.creases[v][1]]
+			vertices[v] = weightedSum([]float64{0.75, 0.125, 0.125}, p, a, b)
+		case len(t.creases[v]) < 2 && n > 0:
+			// Loop的权重 β = (5/8 - (3/8 + cos(2π/n)/4)²)/n
+			c := 0.375 + math.Cos(2*math.Pi/float64(n))/4
+			beta := (0.625 - c*c) / float64(n)
+			q := ScaleVec2(1-float64(n)*beta, p)
+			for _, w := range t.neighbors[v] {
+				q.AddScaledVec(q, beta, m.Vertices[w])
+			}
+			vertices[v] = q
+		}
+	}
+
+	mid := make(map[[2]int]int, len(t.edges))
+	for key, list := range t.edges {
+		a, b := m.Vertices[key[0]], m.Vertices[key[1]]
+		var q *mat.VecDense
+		if !smooth || t.sharp[key] {
+			q = weightedSum([]float64{0.5, 0.5}, a, b)
+		} else {
+			c, d := m.Vertices[oppositeVertex(m.Faces[list[0]], key)], m.Vertices[oppositeVertex(m.Faces[list[1]], key)]
+			q = weightedSum([]float64{0.375, 0.375, 0.125, 0.125}, a, b, c, d)
+		}
+		mid[key] = len(vertices)
+		vertices = append(vertices, q)
+	}
+
+	faces := make([][3]int, 0, 4*len(m.Faces))
+	for _, f := range m.Faces {
+		ab, bc, ca := mid[edgeKey(f[0], f[1])], mid[edgeKey(f[1], f[2])], mid[edgeKey(f[2], f[0])]
+		faces = append(faces, [3]int{f[0], ab, ca}, [3]int{f[1], bc, ab}, [3]int{f[2], ca, bc}, [3]int{ab, bc, ca})
+	}
+
+	res := make(map[[2]int]bool, 2*len(t.sharp))
+	for key := range t.sharp {
+		res[edgeKey(key[0], mid[key])] = true
+		res[edgeKey(mid[key], key[1])] = true
+	}
+	m.Vertices, m.Faces = vertices, faces
+	return res
+}
+
+// sqrt3Step 进行一次√3细分(Kobbelt)：在每个面的重心插入顶点，翻转原有的非尖锐边；
+// trisect为true时将尖锐边三等分并用三次B样条的三分规则计算边上的点，否则尖锐边保持不变
+func (m *Mesh) sqrt3Step(sharp map[[2]int]bool, trisect bool) map[[2]int]bool {
+	t := m.newSubdivisionTopology(sharp)
+	vertices := make([]*mat.VecDense, len(m.Vertices), len(m.Vertices)+len(m.Faces))
+	for v, p := range m.Vertices {
+		vertices[v] = p
+		switch n := len(t.neighbors[v]); {
+		case len(t.creases[v]) == 2:
+			if trisect {
+				a, b := m.Vertices[t.creases[v][0]], m.Vertices[t.creases[v][1]]
+				vertices[v] = weightedSum([]float64{19.0 / 27, 4.0 / 27, 4.0 / 27}, p, a, b)
+			}
+		case len(t.creases[v]) < 2 && n > 0:
+			// α = (4 - 2cos(2π/n))/9
+			alpha := (4 - 2*math.Cos(2*math.Pi/float64(n))) / 9
+			q := ScaleVec2(1-alpha, p)
+			for _, w := range t.neighbors[v] {
+				q.AddScaledVec(q, alpha/float64(n), m.Vertices[w])
+			}
+			vertices[v] = q
+		}
+	}
+
+	center := make([]int, len(m.Faces))
+	for i, f := range m.Faces {
+		center[i] = len(vertices)
+		vertices = append(vertices, weightedSum([]float64{1.0 / 3, 1.0 / 3, 1.0 / 3}, m.Vertices[f[0]], m.Vertices[f[1]], m.Vertices[f[2]]))
+	}
+
+	// 尖锐边key上靠近key[0]与靠近key[1]的两个新顶点
+	thirds := make(map[[2]int][2]int)
+	if trisect {
+		// 顶点不是恰好经过两条尖锐边时用线性外推代替尖锐边上的前一个点
+		previous := func(v, next int) *mat.VecDense {
+			if len(t.creases[v]) == 2 {
+				w := t.creases[v][0]
+				if w == next {
+					w = t.creases[v][1]
+				}
+				return m.Vertices[w]
+			}
+			return weightedSum([]float64{2, -1}, m.Vertices[v], m.Vertices[next])
+		}
+		for key := range t.sharp {
+			a, b := m.Vertices[key[0]], m.Vertices[key[1]]
+			near := weightedSum([]float64{1.0 / 27, 16.0 / 27, 10.0 / 27}, previous(key[0], key[1]), a, b)
+			far := weightedSum([]float64{10.0 / 27, 16.0 / 27, 1.0 / 27}, a, b, previous(key[1], key[0]))
+			thirds[key] = [2]int{len(vertices), len(vertices) + 1}
+			vertices = append(vertices, near, far)
+		}
+	}
+
+	faces := make([][3]int, 0, 3*len(m.Faces))
+	for i, f := range m.Faces {
+		for e := 0; e < 3; e++ {
+			x, y := f[e], f[(e+1)%3]
+			key := edgeKey(x, y)
+			switch {
+			case !t.sharp[key]:
+				// 翻转后的边连接两侧面的重心
+				other := t.edges[key][0]
+				if other == i {
+					other = t.edges[key][1]
+				}
+				faces = append(faces, [3]int{x, center[other], center[i]})
+			case trisect:
+				p, q := thirds[key][0], thirds[key][1]
+				if x != key[0] {
+					p, q = q, p
+				}
+				faces = append(faces, [3]int{x, p, center[i]}, [3]int{p, q, center[i]}, [3]int{q, y, center[i]})
+			default:
+				faces = append(faces, [3]int{x, y, center[i]})
+			}
+		}
+	}
+
+	res := t.sharp
+	if trisect {
+		res = make(map[[2]int]bool, 3*len(t.sharp))
+		for key := range t.sharp {
+			p, q := thirds[key][0], thirds[key][1]
+			res[edgeKey(key[0], p)] = true
+			res[edgeKey(p, q)] = true
+			res[edgeKey(q, key[1])] = true
+		}
+	}
+	m.Vertices, m.Faces = vertices, faces
+	return res
+}
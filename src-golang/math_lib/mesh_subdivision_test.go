@@ -0,0 +1,79 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSubdivide(t *testing.T) {
+	for _, c := range []struct {
+		scheme SubdivisionScheme
+		faces  int
+	}{
+		{SubdivisionLoop, 8 * 16},
+		{SubdivisionMidpoint, 8 * 16},
+		{SubdivisionSqrt3, 8 * 9},
+	} {
+		m := unitSphere(0)
+		m.Subdivide(c.scheme, 2, nil)
+		if len(m.Faces) != c.faces {
+			t.Errorf("scheme %d: %d faces, want %d", c.scheme, len(m.Faces), c.faces)
+		}
+		if r := m.Validate(); !r.Watertight || !r.Manifold || !r.ConsistentlyOriented || r.Genus != 0 {
+			t.Errorf("scheme %d: report %+v", c.scheme, r)
+		}
+		volume := Volume(m.Triangles())
+		switch c.scheme {
+		case SubdivisionMidpoint:
+			if math.Abs(volume-4.0/3) > 1e-12 {
+				t.Errorf("midpoint subdivision changed the volume to %g", volume)
+			}
+		default:
+			// 逼近型细分使凸多面体向内收缩
+			if volume >= 4.0/3 || volume < 0.2 {
+				t.Errorf("scheme %d: volume %g", c.scheme, volume)
+			}
+		}
+	}
+}
+
+func TestSubdivideCreases(t *testing.T) {
+	m := NewMesh(boxMesh(0, 0, 0, 1, 1, 1))
+	var creases [][2]int
+	for e := range m.edgeFaces() {
+		p, q := m.Vertices[e[0]], m.Vertices[e[1]]
+		differ := 0
+		for i := 0; i < 3; i++ {
+			if p.AtVec(i) != q.AtVec(i) {
+				differ++
+			}
+		}
+		if differ == 1 {
+			creases = append(creases, e)
+		}
+	}
+	if len(creases) != 12 {
+		t.Fatalf("found %d box edges", len(creases))
+	}
+
+	m.Subdivide(SubdivisionLoop, 2, creases)
+	// 角点有三条尖锐边经过，保持不动；尖锐边上的点只沿边移动
+	corners := 0
+	for _, p := range m.Vertices {
+		onEdge := 0
+		for i := 0; i < 3; i++ {
+			if p.AtVec(i) == 0 || p.AtVec(i) == 1 {
+				onEdge++
+			}
+		}
+		if onEdge == 3 {
+			corners++
+		}
+	}
+	if corners != 8 {
+		t.Errorf("%d corners kept, want 8", corners)
+	}
+	if r := m.Validate(); !r.Watertight || !r.ConsistentlyOriented {
+		t.Errorf("report %+v", r)
+	}
+}
@@ -0,0 +1,102 @@
+package math_lib
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// boxMesh 生成轴对齐长方体的封闭三角网格，法向量朝外
+func boxMesh(x0, y0, z0, x1, y1, z1 float64) []*Triangle {
+	corner := func(i int) [3]float64 {
+		p := [3]float64{x0, y0, z0}
+		if i&1 != 0 {
+			p[0] = x1
+		}
+		if i&2 != 0 {
+			p[1] = y1
+		}
+		if i&4 != 0 {
+			p[2] = z1
+		}
+		return p
+	}
+	quads := [][4]int{{0, 2, 3, 1}, {4, 5, 7, 6}, {0, 1, 5, 4}, {2, 6, 7, 3}, {0, 4, 6, 2}, {1, 3, 7, 5}}
+	var tris []*Triangle
+	for _, q := range quads {
+		for _, f := range [][3]int{{q[0], q[1], q[2]}, {q[0], q[2], q[3]}} {
+			var tri Triangle
+			for i, c := range f {
+				p := corner(c)
+				tri.P[i] = vec3(p[0], p[1], p[2])
+			}
+			tris = append(tris, &tri)
+		}
+	}
+	return tris
+}
+
+// planeGrid 返回[0,2]×[0,1]上nx×ny个矩形沿对角线剖分得到的平面网格
+func planeGrid(nx, ny int) *Mesh {
+	m := &Mesh{}
+	for j := 0; j <= ny; j++ {
+		for i := 0; i <= nx; i++ {
+			m.Vertices = append(m.Vertices, mat.NewVecDense(3, []float64{2 * float64(i) / float64(nx), float64(j) / float64(ny), 0}))
+		}
+	}
+	id := func(i, j int) int { return j*(nx+1) + i }
+	for j := 0; j < ny; j++ {
+		for i := 0; i < nx; i++ {
+			m.Faces = append(m.Faces, [3]int{id(i, j), id(i+1, j), id(i+1, j+1)}, [3]int{id(i, j), id(i+1, j+1), id(i, j+1)})
+		}
+	}
+	return m
+}
+
+// unitSphere 返回正八面体细分levels次并投影到单位球面得到的网格
+func unitSphere(levels int) *Mesh {
+	m := &Mesh{}
+	for _, p := range [][]float64{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}} {
+		m.Vertices = append(m.Vertices, mat.NewVecDense(3, p))
+	}
+	m.Faces = [][3]int{{0, 2, 4}, {2, 1, 4}, {1, 3, 4}, {3, 0, 4}, {2, 0, 5}, {1, 2, 5}, {3, 1, 5}, {0, 3, 5}}
+	for ; levels > 0; levels-- {
+		middle := make(map[[2]int]int)
+		split := func(a, b int) int {
+			if v, ok := middle[edgeKey(a, b)]; ok {
+				return v
+			}
+			p := AddVec(mat.NewVecDense(3, nil), m.Vertices[a], m.Vertices[b])
+			p.ScaleVec(1/mat.Norm(p, 2), p)
+			m.Vertices = append(m.Vertices, p)
+			middle[edgeKey(a, b)] = len(m.Vertices) - 1
+			return len(m.Vertices) - 1
+		}
+		faces := make([][3]int, 0, 4*len(m.Faces))
+		for _, f := range m.Faces {
+			ab, bc, ca := split(f[0], f[1]), split(f[1], f[2]), split(f[2], f[0])
+			faces = append(faces, [3]int{f[0], ab, ca}, [3]int{ab, f[1], bc}, [3]int{ca, bc, f[2]}, [3]int{ab, bc, ca})
+		}
+		m.Faces = faces
+	}
+	return m
+}
+
+func TestNewMesh(t *testing.T) {
+	tris := boxMesh(0, 0, 0, 1, 2, 3)
+	m := NewMesh(tris)
+	if len(m.Vertices) != 8 || len(m.Faces) != 12 {
+		t.Fatalf("got %d vertices and %d faces, want 8 and 12", len(m.Vertices), len(m.Faces))
+	}
+	if b := m.BoundaryEdges(); len(b) != 0 {
+		t.Errorf("closed box has %d boundary edges", len(b))
+	}
+	if v := enclosedVolume(m.Triangles()); v < 6-1e-12 || v > 6+1e-12 {
+		t.Errorf("volume %g, want 6", v)
+	}
+
+	m = NewMesh(tris[2:])
+	if b := m.BoundaryEdges(); len(b) != 4 {
+		t.Errorf("box without its bottom has %d boundary edges, want 4", len(b))
+	}
+}
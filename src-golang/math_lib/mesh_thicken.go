@@ -0,0 +1,96 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// Thicken 将开放的曲面网格沿顶点法向量挤出厚度thickness成为封闭实体：法向量一侧的一层保持原方向，另一层翻转，边界边处用侧壁连接两层
+// normals为每个顶点的单位法向量，为nil时使用相邻面法向量的面积加权平均；thickness为负时向法向量的反方向挤出，输出三角形的法向量指向实体外侧
+// 几乎重合的顶点先合并(见welded)并忽略退化面，否则舍入误差造成的接缝会生成内部的侧壁；
+// 厚度应小于曲面的曲率半径与相邻部分的间距，否则挤出的一层会自相交，此时可改用Shell由距离场生成实体
+func (m *Mesh) Thicken(thickness float64, normals []*mat.VecDense) []*Triangle {
+	sheet := m.welded()
+	if normals == nil {
+		normals = make([]*mat.VecDense, len(m.Vertices))
+		for i := range normals {
+			normals[i] = mat.NewVecDense(3, nil)
+		}
+		for _, f := range sheet.Faces {
+			n := Cross2(SubVec(mat.NewVecDense(3, nil), m.Vertices[f[1]], m.Vertices[f[0]]), SubVec(mat.NewVecDense(3, nil), m.Vertices[f[2]], m.Vertices[f[0]]))
+			for _, v := range f {
+				normals[v].AddVec(normals[v], n)
+			}
+		}
+		for _, n := range normals {
+			if length := mat.Norm(n, 2); length > 0 {
+				n.ScaleVec(1/length, n)
+			}
+		}
+	}
+
+	// 法向量一侧的一层与另一侧的一层，其中一层与原曲面重合
+	upper := make([]*mat.VecDense, len(m.Vertices))
+	lower := make([]*mat.VecDense, len(m.Vertices))
+	for _, f := range sheet.Faces {
+		for _, v := range f {
+			if upper[v] == nil {
+				upper[v] = AddVec(mat.NewVecDense(3, nil), m.Vertices[v], ScaleVec2(math.Max(thickness, 0), normals[v]))
+				lower[v] = AddVec(mat.NewVecDense(3, nil), m.Vertices[v], ScaleVec2(math.Min(thickness, 0), normals[v]))
+			}
+		}
+	}
+
+	res := make([]*Triangle, 0, 2*len(sheet.Faces))
+	for _, f := range sheet.Faces {
+		res = append(res,
+			&Triangle{[3]*mat.VecDense{upper[f[0]], upper[f[1]], upper[f[2]]}},
+			&Triangle{[3]*mat.VecDense{lower[f[0]], lower[f[2]], lower[f[1]]}})
+	}
+
+	// 边界边a→b的方向与所在面一致，面的内部在其左侧，侧壁的法向量指向右侧
+	for _, e := range sheet.BoundaryEdges() {
+		a, b := e[0], e[1]
+		res = append(res,
+			&Triangle{[3]*mat.VecDense{upper[a], lower[a], lower[b]}},
+			&Triangle{[3]*mat.VecDense{upper[a], lower[b], upper[b]}})
+	}
+	return res
+}
+
+// ThickenParametricEquation 与TriangulateParametricEquation相同地三角化参数曲面，再沿曲面的精确法向量挤出厚度thickness成为封闭实体
+// 参数域边界上重合的顶点(周期曲面的接缝、退化为一点的边)先合并并平均其法向量，只有真正的边界才生成侧壁
+func ThickenParametricEquation(f func(u, v float64) (x, y, z float64), uRange, vRange []float64, divisions []int, thickness float64) []*Triangle {
+	m, normals := (&ParametricSurface{F: f}).Triangulate(uRange, vRange, divisions)
+
+	faces := append([][3]int(nil), m.Faces...)
+	if m.weldVertices(m.weldTolerance()) == 0 {
+		return m.Thicken(thickness, normals)
+	}
+
+	// 合并后的顶点取所有被合并顶点法向量的平均
+	sum := make([]*mat.VecDense, len(m.Vertices))
+	seen := make([]bool, len(m.Vertices))
+	for i, f := range faces {
+		for k, v := range f {
+			target := m.Faces[i][k]
+			if sum[target] == nil {
+				sum[target] = mat.NewVecDense(3, nil)
+			}
+			if !seen[v] {
+				seen[v] = true
+				sum[target].AddVec(sum[target], normals[v])
+			}
+		}
+	}
+	for i, n := range sum {
+		if n == nil {
+			continue
+		}
+		if length := mat.Norm(n, 2); length > 0 {
+			n.ScaleVec(1/length, n)
+		}
+		normals[i] = n
+	}
+	return m.Thicken(thickness, normals)
+}
@@ -0,0 +1,47 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestThickenPlane(t *testing.T) {
+	for _, thickness := range []float64{0.1, -0.1} {
+		tris := planeGrid(4, 2).Thicken(thickness, nil)
+		if v := Volume(tris); math.Abs(v-0.2) > 1e-12 {
+			t.Errorf("thickness %g: volume %g, want 0.2", thickness, v)
+		}
+		if r := NewMesh(tris).Validate(); !r.Watertight || !r.ConsistentlyOriented || r.InvertedComponents != 0 || r.SelfIntersections != 0 {
+			t.Errorf("thickness %g: report %+v", thickness, r)
+		}
+	}
+}
+
+func TestThickenParametricCylinder(t *testing.T) {
+	cylinder := func(u, v float64) (x, y, z float64) { return math.Cos(u), math.Sin(u), v }
+	tris := ThickenParametricEquation(cylinder, []float64{0, 2 * math.Pi}, []float64{0, 1}, []int{64, 4}, 0.1)
+
+	// 周期方向的接缝合并后得到一个圆环状的实体
+	r := NewMesh(tris).Validate()
+	if !r.Watertight || !r.ConsistentlyOriented || r.Components != 1 || r.Genus != 1 || r.InvertedComponents != 0 {
+		t.Errorf("report %+v", r)
+	}
+	if v, want := Volume(tris), math.Pi*(1.1*1.1-1); math.Abs(v-want) > 0.01*want {
+		t.Errorf("volume %g, want %g", v, want)
+	}
+}
+
+func TestThickenUnweldedCylinder(t *testing.T) {
+	// 每个三角形有独立的顶点，周期方向的接缝两侧坐标只在舍入误差意义下相同
+	cylinder := func(u, v float64) (x, y, z float64) { return math.Cos(u), math.Sin(u), v }
+	surface := TriangulateParametricEquation(cylinder, []float64{0, 2 * math.Pi}, []float64{0, 1}, []int{64, 4})
+	tris := NewMesh(surface).Thicken(0.1, nil)
+
+	r := NewMesh(tris).Validate()
+	if !r.Watertight || !r.ConsistentlyOriented || r.Components != 1 || r.Genus != 1 || r.SelfIntersections != 0 {
+		t.Errorf("report %+v", r)
+	}
+	if v, want := math.Abs(Volume(tris)), math.Pi*(1.1*1.1-1); math.Abs(v-want) > 0.01*want {
+		t.Errorf("volume %g, want %g", v, want)
+	}
+}
@@ -0,0 +1,270 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// maxReportedPairs ValidationReport中最多列出的自相交面对数量
+const maxReportedPairs = 1000
+
+// ValidationReport 网格的拓扑与几何检查结果
+type ValidationReport struct {
+	Vertices              int      `json:"vertices"`
+	Edges                 int      `json:"edges"`
+	Faces                 int      `json:"faces"`
+	Components            int      `json:"components"`              // 通过共享边连通的部分数
+	BoundaryEdges         int      `json:"boundary_edges"`          // 只属于一个面的边
+	BoundaryLoops         int      `json:"boundary_loops"`          // 边界边组成的环
+	NonManifoldEdges      int      `json:"non_manifold_edges"`      // 属于三个以上面的边
+	NonManifoldVertices   int      `json:"non_manifold_vertices"`   // 周围的面组成多个扇形的顶点
+	DegenerateFaces       int      `json:"degenerate_faces"`        // 面积为零的面
+	InconsistentEdges     int      `json:"inconsistent_edges"`      // 两侧的面方向相同的边
+	InvertedComponents    int      `json:"inverted_components"`     // 有向体积为负的封闭部分
+	SelfIntersections     int      `json:"self_intersections"`      // 除公共顶点与公共边外还有公共点的面对
+	SelfIntersectingPairs [][2]int `json:"self_intersecting_pairs"` // 自相交的面对，最多列出maxReportedPairs个
+	EulerCharacteristic   int      `json:"euler_characteristic"`    // V - E + F
+	Genus                 int      `json:"genus"`                   // 可定向流形时为各部分亏格之和，否则为-1
+	Watertight            bool     `json:"watertight"`              // 没有边界边与非流形边
+	Manifold              bool     `json:"manifold"`                // 没有非流形边与非流形顶点
+	ConsistentlyOriented  bool     `json:"consistently_oriented"`   // 所有相邻面的方向一致
+}
+
+// Validate 检查网格的封闭性、流形性、方向一致性与自相交，不修改网格
+func (m *Mesh) Validate() ValidationReport {
+	r := ValidationReport{Faces: len(m.Faces), SelfIntersectingPairs: [][2]int{}}
+
+	used := make([]bool, len(m.Vertices))
+	for _, f := range m.Faces {
+		for _, v := range f {
+			used[v] = true
+		}
+		if f[0] == f[1] || f[1] == f[2] || f[2] == f[0] || collinear3D(m.Vertices[f[0]], m.Vertices[f[1]], m.Vertices[f[2]]) {
+			r.DegenerateFaces++
+		}
+	}
+	for _, u := range used {
+		if u {
+			r.Vertices++
+		}
+	}
+
+	edges := m.edgeFaces()
+	r.Edges = len(edges)
+	for key, list := range edges {
+		switch {
+		case len(list) == 1:
+			r.BoundaryEdges++
+		case len(list) > 2:
+			r.NonManifoldEdges++
+		case hasDirectedEdge(m.Faces[list[0]], key[0], key[1]) == hasDirectedEdge(m.Faces[list[1]], key[0], key[1]):
+			r.InconsistentEdges++
+		}
+	}
+	for v, faces := range m.incidentFaces() {
+		roots := m.vertexFans(v, faces)
+		for _, root := range roots {
+			if root != roots[0] {
+				r.NonManifoldVertices++
+				break
+			}
+		}
+	}
+	r.BoundaryLoops = m.boundaryLoops()
+
+	components := m.components()
+	r.Components = len(components)
+	r.EulerCharacteristic = r.Vertices - r.Edges + r.Faces
+	r.Manifold = r.NonManifoldEdges == 0 && r.NonManifoldVertices == 0
+	r.Watertight = r.BoundaryEdges == 0 && r.NonManifoldEdges == 0
+	r.ConsistentlyOriented = r.InconsistentEdges == 0
+
+	// 可定向曲面满足 χ = 2C - 2g - b
+	r.Genus = -1
+	if r.Manifold && r.ConsistentlyOriented {
+		r.Genus = (2*r.Components - r.EulerCharacteristic - r.BoundaryLoops) / 2
+	}
+	if r.ConsistentlyOriented {
+		for _, component := range components {
+			if m.closedVolume(component) < 0 {
+				r.InvertedComponents++
+			}
+		}
+	}
+
+	m.selfIntersections(func(i, j int) {
+		r.SelfIntersections++
+		if len(r.SelfIntersectingPairs) < maxReportedPairs {
+			r.SelfIntersectingPairs = append(r.SelfIntersectingPairs, [2]int{i, j})
+		}
+	})
+	return r
+}
+
+// boundaryLoops 统计边界边组成的环数，经过非流形顶点的边界按边的连接关系计数
+func (m *Mesh) boundaryLoops() int {
+	next := make(map[int][]int)
+	boundary := m.BoundaryEdges()
+	for _, e := range boundary {
+		next[e[0]] = append(next[e[0]], e[1])
+	}
+
+	loops := 0
+	visited := make(map[[2]int]bool, len(boundary))
+	for _, e := range boundary {
+		if visited[e] {
+			continue
+		}
+		loops++
+		for cur := e; !visited[cur]; {
+			visited[cur] = true
+			for _, w := range next[cur[1]] {
+				if !visited[[2]int{cur[1], w}] {
+					cur = [2]int{cur[1], w}
+					break
+				}
+			}
+		}
+	}
+	return loops
+}
+
+// closedVolume 返回连通部分的有向体积，部分不封闭时返回0
+func (m *Mesh) closedVolume(component []int) float64 {
+	count := make(map[[2]int]int)
+	volume := 0.0
+	for _, i := range component {
+		f := m.Faces[i]
+		volume += mat.Dot(m.Vertices[f[0]], Cross2(m.Vertices[f[1]], m.Vertices[f[2]])) / 6
+		for e := 0; e < 3; e++ {
+			count[edgeKey(f[e], f[(e+1)%3])]++
+		}
+	}
+	for _, c := range count {
+		if c != 2 {
+			return 0
+		}
+	}
+	return volume
+}
+
+// selfIntersections 用包围盒树找出包围盒相交的面对，对每个自相交的面对(i < j)调用visit
+// 相邻面只在公共顶点或公共边以外还有公共点时才算相交，所有判断使用精确谓词
+func (m *Mesh) selfIntersections(visit func(i, j int)) {
+	boxes := make([]aabb, len(m.Faces))
+	for i, f := range m.Faces {
+		boxes[i] = pointsBox(m.Vertices[f[0]], m.Vertices[f[1]], m.Vertices[f[2]])
+	}
+	tree := newBoxTree(boxes)
+	for i, f := range m.Faces {
+		if f[0] == f[1] || f[1] == f[2] || f[2] == f[0] {
+			continue
+		}
+		tree.query(boxes[i], func(j int) {
+			g := m.Faces[j]
+			if j > i && g[0] != g[1] && g[1] != g[2] && g[2] != g[0] && m.facesIntersect(f, g) {
+				visit(i, j)
+			}
+		})
+	}
+}
+
+// facesIntersect 判断两个面除公共顶点与公共边外是否还有公共点
+func (m *Mesh) facesIntersect(f, g [3]int) bool {
+	var shared [][2]int // 公共顶点在f与g中的位置
+	for a := 0; a < 3; a++ {
+		for b := 0; b < 3; b++ {
+			if f[a] == g[b] {
+				shared = append(shared, [2]int{a, b})
+			}
+		}
+	}
+	p := [3]*mat.VecDense{m.Vertices[f[0]], m.Vertices[f[1]], m.Vertices[f[2]]}
+	q := [3]*mat.VecDense{m.Vertices[g[0]], m.Vertices[g[1]], m.Vertices[g[2]]}
+
+	switch len(shared) {
+	case 0:
+		for e := 0; e < 3; e++ {
+			if segmentTriangleIntersect(p[e], p[(e+1)%3], q[0], q[1], q[2]) ||
+				segmentTriangleIntersect(q[e], q[(e+1)%3], p[0], p[1], p[2]) {
+				return true
+			}
+		}
+		return false
+	case 1:
+		// 两个面的交集是经过公共顶点的线段，超出公共顶点时必然与对边相交
+		a, b := shared[0][0], shared[0][1]
+		return segmentTriangleIntersect(p[(a+1)%3], p[(a+2)%3], q[0], q[1], q[2]) ||
+			segmentTriangleIntersect(q[(b+1)%3], q[(b+2)%3], p[0], p[1], p[2])
+	case 2:
+		// 共享一条边时只有两个面共面且第三个顶点位于公共边同侧才会重叠
+		u, v := p[shared[0][0]], p[shared[1][0]]
+		x, y := p[3-shared[0][0]-shared[1][0]], q[3-shared[0][1]-shared[1][1]]
+		if Orient3D(u, v, x, y) != 0 {
+			return false
+		}
+		i, j := dominantAxes(u, v, x)
+		return sign(Orient2D(project2D(u, i, j), project2D(v, i, j), project2D(x, i, j))) ==
+			sign(Orient2D(project2D(u, i, j), project2D(v, i, j), project2D(y, i, j)))
+	default:
+		return true
+	}
+}
+
+// segmentTriangleIntersect 精确判断闭线段pq与非退化的闭三角形abc是否有公共点
+func segmentTriangleIntersect(p, q, a, b, c *mat.VecDense) bool {
+	sp, sq := sign(Orient3D(a, b, c, p)), sign(Orient3D(a, b, c, q))
+	if sp == sq && sp != 0 {
+		return false
+	}
+	if sp == 0 && sq == 0 {
+		// 共面时在去掉法向量最大分量的坐标平面上判断
+		i, j := dominantAxes(a, b, c)
+		p2, q2 := project2D(p, i, j), project2D(q, i, j)
+		a2, b2, c2 := project2D(a, i, j), project2D(b, i, j), project2D(c, i, j)
+		return pointInTriangle2D(p2, a2, b2, c2) || pointInTriangle2D(q2, a2, b2, c2) ||
+			segmentsTouch2D(p2, q2, a2, b2) || segmentsTouch2D(p2, q2, b2, c2) || segmentsTouch2D(p2, q2, c2, a2)
+	}
+
+	// 直线pq穿过平面的点位于三角形内当且仅当三个有向体积不异号
+	s := [3]int{sign(Orient3D(p, q, a, b)), sign(Orient3D(p, q, b, c)), sign(Orient3D(p, q, c, a))}
+	return !(min(s[0], s[1], s[2]) < 0 && max(s[0], s[1], s[2]) > 0)
+}
+
+// dominantAxes 返回去掉三角形abc法向量绝对值最大分量后剩余的两个坐标轴
+func dominantAxes(a, b, c *mat.VecDense) (int, int) {
+	n := Cross2(SubVec(mat.NewVecDense(3, nil), b, a), SubVec(mat.NewVecDense(3, nil), c, a))
+	k := 0
+	for d := 1; d < 3; d++ {
+		if math.Abs(n.AtVec(d)) > math.Abs(n.AtVec(k)) {
+			k = d
+		}
+	}
+	return (k + 1) % 3, (k + 2) % 3
+}
+
+// project2D 取点的第i、j个坐标作为二维点
+func project2D(p *mat.VecDense, i, j int) *mat.VecDense {
+	return mat.NewVecDense(2, []float64{p.AtVec(i), p.AtVec(j)})
+}
+
+// pointInTriangle2D 判断点p是否在闭三角形abc内，三角形方向任意
+func pointInTriangle2D(p, a, b, c *mat.VecDense) bool {
+	s := [3]int{sign(Orient2D(a, b, p)), sign(Orient2D(b, c, p)), sign(Orient2D(c, a, p))}
+	return !(min(s[0], s[1], s[2]) < 0 && max(s[0], s[1], s[2]) > 0)
+}
+
+// segmentsTouch2D 判断两条闭线段是否有公共点
+func segmentsTouch2D(p1, q1, p2, q2 *mat.VecDense) bool {
+	o1, o2 := sign(Orient2D(p1, q1, p2)), sign(Orient2D(p1, q1, q2))
+	o3, o4 := sign(Orient2D(p2, q2, p1)), sign(Orient2D(p2, q2, q1))
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+	within := func(p, q, r *mat.VecDense) bool {
+		return math.Min(p.AtVec(0), q.AtVec(0)) <= r.AtVec(0) && r.AtVec(0) <= math.Max(p.AtVec(0), q.AtVec(0)) &&
+			math.Min(p.AtVec(1), q.AtVec(1)) <= r.AtVec(1) && r.AtVec(1) <= math.Max(p.AtVec(1), q.AtVec(1))
+	}
+	return (o1 == 0 && within(p1, q1, p2)) || (o2 == 0 && within(p1, q1, q2)) ||
+		(o3 == 0 && within(p2, q2, p1)) || (o4 == 0 && within(p2, q2, q1))
+}
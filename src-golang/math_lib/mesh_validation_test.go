@@ -0,0 +1,33 @@
+package math_lib
+
+import (
+	"testing"
+)
+
+func TestValidateClosedBox(t *testing.T) {
+	r := NewMesh(boxMesh(0, 0, 0, 1, 1, 1)).Validate()
+	if r.Vertices != 8 || r.Edges != 18 || r.Faces != 12 || r.EulerCharacteristic != 2 || r.Genus != 0 {
+		t.Errorf("report %+v", r)
+	}
+	if !r.Watertight || !r.Manifold || !r.ConsistentlyOriented || r.InvertedComponents != 0 || r.SelfIntersections != 0 {
+		t.Errorf("report %+v", r)
+	}
+}
+
+func TestValidateOpenBox(t *testing.T) {
+	r := NewMesh(boxMesh(0, 0, 0, 1, 1, 1)[2:]).Validate()
+	if r.Watertight || r.BoundaryEdges != 4 || r.BoundaryLoops != 1 || r.Genus != 0 {
+		t.Errorf("report %+v", r)
+	}
+}
+
+func TestValidateIntersectingSoup(t *testing.T) {
+	tris := append(boxMesh(0, 0, 0, 2, 2, 2), boxMesh(1, 1, 1, 3, 3, 3)...)
+	r := NewMesh(tris).Validate()
+	if r.Components != 2 || r.SelfIntersections == 0 || len(r.SelfIntersectingPairs) != r.SelfIntersections {
+		t.Errorf("report %+v", r)
+	}
+	if !r.Watertight {
+		t.Errorf("two closed boxes are not watertight")
+	}
+}
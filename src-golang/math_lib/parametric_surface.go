@@ -0,0 +1,221 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// ParametricSurface 参数曲面 F(u, v)，与TriangulateParametricEquation使用相同形式的函数
+// 偏导数为nil时用中心差分计算：一阶导数由F差分，二阶导数优先由给出的一阶导数差分，否则由F二次差分
+type ParametricSurface struct {
+	F             func(u, v float64) (x, y, z float64)
+	Du, Dv        func(u, v float64) (x, y, z float64) // 可选的解析一阶偏导数
+	Duu, Duv, Dvv func(u, v float64) (x, y, z float64) // 可选的解析二阶偏导数
+	Step          float64                              // 差分步长，0表示按参数的大小自动选取
+}
+
+// SurfaceCurvature 参数曲面上一点的曲率，法向量为 Fu×Fv 的方向，符号与Mesh.Curvature一致：曲面背离法向量弯曲时为正(如法向量朝外的球面)
+type SurfaceCurvature struct {
+	Mean, Gaussian float64
+	K1, K2         float64       // 主曲率，K1 ≥ K2
+	Dir1, Dir2     *mat.VecDense // 主方向(单位切向量)，Dir1对应K1
+}
+
+// gaussLegendre3 区间[-1, 1]上三点Gauss-Legendre求积的节点与权重
+var gaussLegendre3 = [3][2]float64{{-0.7745966692414834, 5.0 / 9}, {0, 8.0 / 9}, {0.7745966692414834, 5.0 / 9}}
+
+// evalParametric 计算向量值函数并转换为向量
+func evalParametric(f func(u, v float64) (x, y, z float64), u, v float64) *mat.VecDense {
+	x, y, z := f(u, v)
+	return mat.NewVecDense(3, []float64{x, y, z})
+}
+
+// step 返回参数t处的差分步长
+func (s *ParametricSurface) step(t, scale float64) float64 {
+	if s.Step > 0 {
+		return s.Step
+	}
+	return scale * math.Max(1, math.Abs(t))
+}
+
+// Point 返回曲面上参数(u, v)对应的点
+func (s *ParametricSurface) Point(u, v float64) *mat.VecDense {
+	return evalParametric(s.F, u, v)
+}
+
+// Derivatives 返回一阶偏导数 Fu, Fv
+func (s *ParametricSurface) Derivatives(u, v float64) (fu, fv *mat.VecDense) {
+	if s.Du != nil {
+		fu = evalParametric(s.Du, u, v)
+	} else {
+		h := s.step(u, 1e-5)
+		fu = SubVec(mat.NewVecDense(3, nil), evalParametric(s.F, u+h, v), evalParametric(s.F, u-h, v))
+		fu.ScaleVec(1/(2*h), fu)
+	}
+	if s.Dv != nil {
+		fv = evalParametric(s.Dv, u, v)
+	} else {
+		h := s.step(v, 1e-5)
+		fv = SubVec(mat.NewVecDense(3, nil), evalParametric(s.F, u, v+h), evalParametric(s.F, u, v-h))
+		fv.ScaleVec(1/(2*h), fv)
+	}
+	return fu, fv
+}
+
+// SecondDerivatives 返回二阶偏导数 Fuu, Fuv, Fvv
+func (s *ParametricSurface) SecondDerivatives(u, v float64) (fuu, fuv, fvv *mat.VecDense) {
+	hu, hv := s.step(u, 1e-4), s.step(v, 1e-4)
+	diff := func(a, b *mat.VecDense, h float64) *mat.VecDense {
+		d := SubVec(mat.NewVecDense(3, nil), a, b)
+		d.ScaleVec(1/(2*h), d)
+		return d
+	}
+
+	switch {
+	case s.Duu != nil:
+		fuu = evalParametric(s.Duu, u, v)
+	case s.Du != nil:
+		fuu = diff(evalParametric(s.Du, u+hu, v), evalParametric(s.Du, u-hu, v), hu)
+	default:
+		fuu = AddVecs(mat.NewVecDense(3, nil), evalParametric(s.F, u+hu, v), evalParametric(s.F, u-hu, v), ScaleVec2(-2, evalParametric(s.F, u, v)))
+		fuu.ScaleVec(1/(hu*hu), fuu)
+	}
+	switch {
+	case s.Dvv != nil:
+		fvv = evalParametric(s.Dvv, u, v)
+	case s.Dv != nil:
+		fvv = diff(evalParametric(s.Dv, u, v+hv), evalParametric(s.Dv, u, v-hv), hv)
+	default:
+		fvv = AddVecs(mat.NewVecDense(3, nil), evalParametric(s.F, u, v+hv), evalParametric(s.F, u, v-hv), ScaleVec2(-2, evalParametric(s.F, u, v)))
+		fvv.ScaleVec(1/(hv*hv), fvv)
+	}
+	switch {
+	case s.Duv != nil:
+		fuv = evalParametric(s.Duv, u, v)
+	case s.Du != nil:
+		fuv = diff(evalParametric(s.Du, u, v+hv), evalParametric(s.Du, u, v-hv), hv)
+	default:
+		fuv = AddVecs(mat.NewVecDense(3, nil), evalParametric(s.F, u+hu, v+hv), evalParametric(s.F, u-hu, v-hv),
+			ScaleVec2(-1, evalParametric(s.F, u+hu, v-hv)), ScaleVec2(-1, evalParametric(s.F, u-hu, v+hv)))
+		fuv.ScaleVec(1/(4*hu*hv), fuv)
+	}
+	return fuu, fuv, fvv
+}
+
+// FirstFundamentalForm 返回第一基本形式的系数 E = Fu·Fu, F = Fu·Fv, G = Fv·Fv
+func (s *ParametricSurface) FirstFundamentalForm(u, v float64) (e, f, g float64) {
+	fu, fv := s.Derivatives(u, v)
+	return mat.Dot(fu, fu), mat.Dot(fu, fv), mat.Dot(fv, fv)
+}
+
+// Normal 返回单位法向量 Fu×Fv/|Fu×Fv|，奇异点处返回零向量
+func (s *ParametricSurface) Normal(u, v float64) *mat.VecDense {
+	fu, fv := s.Derivatives(u, v)
+	n := Cross2(fu, fv)
+	if length := mat.Norm(n, 2); length > 0 {
+		n.ScaleVec(1/length, n)
+	}
+	return n
+}
+
+// SecondFundamentalForm 返回第二基本形式的系数 L = Fuu·n, M = Fuv·n, N = Fvv·n
+func (s *ParametricSurface) SecondFundamentalForm(u, v float64) (l, m, n float64) {
+	normal := s.Normal(u, v)
+	fuu, fuv, fvv := s.SecondDerivatives(u, v)
+	return mat.Dot(fuu, normal), mat.Dot(fuv, normal), mat.Dot(fvv, normal)
+}
+
+// Curvature 由形状算子 W = -I⁻¹·II 计算曲率：K = det W，H = tr W / 2，主方向为W的特征向量(a, b)对应的切向量 a·Fu + b·Fv
+// 奇异点(Fu×Fv = 0)处返回零曲率与零方向
+func (s *ParametricSurface) Curvature(u, v float64) SurfaceCurvature {
+	res := SurfaceCurvature{Dir1: mat.NewVecDense(3, nil), Dir2: mat.NewVecDense(3, nil)}
+	fu, fv := s.Derivatives(u, v)
+	e, f, g := mat.Dot(fu, fu), mat.Dot(fu, fv), mat.Dot(fv, fv)
+	det := e*g - f*f
+	if det <= 0 {
+		return res
+	}
+	l, m, n := s.SecondFundamentalForm(u, v)
+	l, m, n = -l, -m, -n
+
+	// W = 1/det·[[G, -F], [-F, E]]·[[-L, -M], [-M, -N]]
+	w := [2][2]float64{
+		{(g*l - f*m) / det, (g*m - f*n) / det},
+		{(e*m - f*l) / det, (e*n - f*m) / det},
+	}
+	res.Gaussian = (l*n - m*m) / det
+	res.Mean = (w[0][0] + w[1][1]) / 2
+	d := math.Sqrt(math.Max(res.Mean*res.Mean-res.Gaussian, 0))
+	res.K1, res.K2 = res.Mean+d, res.Mean-d
+
+	// (W - kI)x = 0 的非零解，脐点处任取一组正交方向
+	direction := func(k float64) *mat.VecDense {
+		a, b := w[0][1], k-w[0][0]
+		if math.Abs(w[1][0])+math.Abs(k-w[1][1]) > math.Abs(a)+math.Abs(b) {
+			a, b = k-w[1][1], w[1][0]
+		}
+		t := AddVec(mat.NewVecDense(3, nil), ScaleVec2(a, fu), ScaleVec2(b, fv))
+		if length := mat.Norm(t, 2); length > 0 {
+			t.ScaleVec(1/length, t)
+		}
+		return t
+	}
+	if d > 1e-9*math.Max(1, math.Abs(res.Mean)) {
+		res.Dir1, res.Dir2 = direction(res.K1), direction(res.K2)
+	} else {
+		frame := newPlaneFrame(s.Point(u, v), s.Normal(u, v))
+		res.Dir1, res.Dir2 = frame.u, frame.v
+	}
+	return res
+}
+
+// Area 在参数域上用复合三点Gauss-Legendre求积计算曲面面积 ∫∫|Fu×Fv|dudv，divisions为两个方向的分段数
+func (s *ParametricSurface) Area(uRange, vRange []float64, divisions []int) float64 {
+	du := (uRange[1] - uRange[0]) / float64(divisions[0])
+	dv := (vRange[1] - vRange[0]) / float64(divisions[1])
+	area := 0.0
+	for i := 0; i < divisions[0]; i++ {
+		for j := 0; j < divisions[1]; j++ {
+			for _, a := range gaussLegendre3 {
+				for _, b := range gaussLegendre3 {
+					u := uRange[0] + du*(float64(i)+(a[0]+1)/2)
+					v := vRange[0] + dv*(float64(j)+(b[0]+1)/2)
+					fu, fv := s.Derivatives(u, v)
+					area += a[1] * b[1] * mat.Norm(Cross2(fu, fv), 2)
+				}
+			}
+		}
+	}
+	return area * du * dv / 4
+}
+
+// Triangulate 与TriangulateParametricEquation相同地三角化曲面，返回以参数网格点为顶点的网格(顶点(i, j)的下标为 i·(divisions[1]+1) + j)
+// 及每个顶点的精确单位法向量；奇异点处沿参数域中心方向稍作偏移后计算法向量
+func (s *ParametricSurface) Triangulate(uRange, vRange []float64, divisions []int) (*Mesh, []*mat.VecDense) {
+	nu, nv := divisions[0], divisions[1]
+	uStep := (uRange[1] - uRange[0]) / float64(nu)
+	vStep := (vRange[1] - vRange[0]) / float64(nv)
+	uMid, vMid := (uRange[0]+uRange[1])/2, (vRange[0]+vRange[1])/2
+
+	m := &Mesh{Vertices: make([]*mat.VecDense, 0, (nu+1)*(nv+1))}
+	normals := make([]*mat.VecDense, 0, (nu+1)*(nv+1))
+	for i := 0; i <= nu; i++ {
+		for j := 0; j <= nv; j++ {
+			u, v := uRange[0]+float64(i)*uStep, vRange[0]+float64(j)*vStep
+			m.Vertices = append(m.Vertices, s.Point(u, v))
+			n := s.Normal(u, v)
+			if mat.Norm(n, 2) == 0 {
+				n = s.Normal(u+1e-6*(uMid-u), v+1e-6*(vMid-v))
+			}
+			normals = append(normals, n)
+		}
+	}
+
+	id := func(i, j int) int { return i*(nv+1) + j }
+	for i := 0; i < nu; i++ {
+		for j := 0; j < nv; j++ {
+			m.Faces = append(m.Faces, [3]int{id(i, j), id(i+1, j), id(i, j+1)}, [3]int{id(i+1, j), id(i+1, j+1), id(i, j+1)})
+		}
+	}
+	return m, normals
+}
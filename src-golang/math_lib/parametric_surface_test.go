@@ -0,0 +1,70 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestParametricSphere(t *testing.T) {
+	const r = 2.0
+	s := &ParametricSurface{F: func(u, v float64) (x, y, z float64) {
+		return r * math.Sin(u) * math.Cos(v), r * math.Sin(u) * math.Sin(v), r * math.Cos(u)
+	}}
+	for _, uv := range [][2]float64{{0.3, 0.2}, {math.Pi / 2, 1}, {2.5, 4}} {
+		c := s.Curvature(uv[0], uv[1])
+		if math.Abs(c.Mean-1/r) > 1e-5 || math.Abs(c.Gaussian-1/r/r) > 1e-5 || math.Abs(c.K1-c.K2) > 1e-4 {
+			t.Errorf("(%g, %g): curvature %+v", uv[0], uv[1], c)
+		}
+		n := s.Normal(uv[0], uv[1])
+		p := s.Point(uv[0], uv[1])
+		if math.Abs(mat.Dot(n, p)-r) > 1e-6 {
+			t.Errorf("(%g, %g): normal %v is not outward", uv[0], uv[1], n.RawVector().Data)
+		}
+	}
+	if a := s.Area([]float64{0, math.Pi}, []float64{0, 2 * math.Pi}, []int{16, 16}); math.Abs(a-4*math.Pi*r*r) > 1e-6 {
+		t.Errorf("area %g, want %g", a, 4*math.Pi*r*r)
+	}
+}
+
+func TestParametricTorus(t *testing.T) {
+	const R, r = 2.0, 1.0
+	s := &ParametricSurface{
+		F: func(u, v float64) (x, y, z float64) {
+			return (R + r*math.Cos(v)) * math.Cos(u), (R + r*math.Cos(v)) * math.Sin(u), r * math.Sin(v)
+		},
+		Du: func(u, v float64) (x, y, z float64) {
+			return -(R + r*math.Cos(v)) * math.Sin(u), (R + r*math.Cos(v)) * math.Cos(u), 0
+		},
+		Dv: func(u, v float64) (x, y, z float64) {
+			return -r * math.Sin(v) * math.Cos(u), -r * math.Sin(v) * math.Sin(u), r * math.Cos(v)
+		},
+	}
+	for _, v := range []float64{0, 1, math.Pi / 2, math.Pi} {
+		c := s.Curvature(0.7, v)
+		// 主曲率为 1/r 与 cos v/(R + r cos v)
+		k1, k2 := 1/r, math.Cos(v)/(R+r*math.Cos(v))
+		if math.Abs(c.K1-k1) > 1e-5 || math.Abs(c.K2-k2) > 1e-5 || math.Abs(c.Gaussian-k1*k2) > 1e-5 {
+			t.Errorf("v = %g: curvature %+v, want k1 = %g, k2 = %g", v, c, k1, k2)
+		}
+		// K2对应沿纬线(u方向)的主方向
+		fu, _ := s.Derivatives(0.7, v)
+		if v != math.Pi/2 && math.Abs(mat.Dot(c.Dir2, fu))/mat.Norm(fu, 2) < 1-1e-6 {
+			t.Errorf("v = %g: second direction %v", v, c.Dir2.RawVector().Data)
+		}
+	}
+	if a := s.Area([]float64{0, 2 * math.Pi}, []float64{0, 2 * math.Pi}, []int{16, 16}); math.Abs(a-4*math.Pi*math.Pi*R*r) > 1e-6 {
+		t.Errorf("area %g, want %g", a, 4*math.Pi*math.Pi*R*r)
+	}
+
+	m, normals := s.Triangulate([]float64{0, 2 * math.Pi}, []float64{0, 2 * math.Pi}, []int{8, 4})
+	if len(m.Vertices) != 9*5 || len(m.Faces) != 2*8*4 || len(normals) != len(m.Vertices) {
+		t.Fatalf("got %d vertices, %d faces and %d normals", len(m.Vertices), len(m.Faces), len(normals))
+	}
+	for i, n := range normals {
+		if math.Abs(mat.Norm(n, 2)-1) > 1e-12 {
+			t.Fatalf("normal %d has length %g", i, mat.Norm(n, 2))
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// planeFrame 平面上的局部坐标系，u、v、n两两正交且满足 u×v = n
+type planeFrame struct {
+	origin, u, v, n *mat.VecDense
+}
+
+// BestFitPlane 用主成分分析计算点集的最小二乘拟合平面，返回平面经过的质心与单位法向量
+// 法向量取协方差矩阵最小特征值对应的特征向量，方向规定为z分量为正(z为零时依次看y、x)
+func BestFitPlane(points []*mat.VecDense) (center, normal *mat.VecDense) {
+	center = mat.NewVecDense(3, nil)
+	if len(points) == 0 {
+		return center, mat.NewVecDense(3, []float64{0, 0, 1})
+	}
+	for _, p := range points {
+		center.AddVec(center, p)
+	}
+	center.ScaleVec(1/float64(len(points)), center)
+
+	cov := mat.NewSymDense(3, nil)
+	d := mat.NewVecDense(3, nil)
+	for _, p := range points {
+		d.SubVec(p, center)
+		cov.SymRankOne(cov, 1, d)
+	}
+
+	var eig mat.EigenSym
+	if !eig.Factorize(cov, true) {
+		return center, mat.NewVecDense(3, []float64{0, 0, 1})
+	}
+	var vectors mat.Dense
+	eig.VectorsTo(&vectors)
+	normal = mat.VecDenseCopyOf(vectors.ColView(0)) // 特征值按升序排列
+
+	for i := 2; i >= 0; i-- {
+		if c := normal.AtVec(i); c != 0 {
+			if c < 0 {
+				normal.ScaleVec(-1, normal)
+			}
+			break
+		}
+	}
+	return center, Normalize(normal)
+}
+
+// newellNormal 用Newell方法计算多边形环的法向量，方向与环的绕向满足右手法则，长度为投影面积的两倍
+func newellNormal(ring []*mat.VecDense) *mat.VecDense {
+	var nx, ny, nz float64
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		nx += (p.AtVec(1) - q.AtVec(1)) * (p.AtVec(2) + q.AtVec(2))
+		ny += (p.AtVec(2) - q.AtVec(2)) * (p.AtVec(0) + q.AtVec(0))
+		nz += (p.AtVec(0) - q.AtVec(0)) * (p.AtVec(1) + q.AtVec(1))
+	}
+	return mat.NewVecDense(3, []float64{nx, ny, nz})
+}
+
+// newPlaneFrame 以单位法向量n建立平面坐标系，u取与n最不平行的坐标轴叉乘n的方向
+func newPlaneFrame(origin, n *mat.VecDense) *planeFrame {
+	axis := mat.NewVecDense(3, nil)
+	k := 0
+	for i := 1; i < 3; i++ {
+		if math.Abs(n.AtVec(i)) < math.Abs(n.AtVec(k)) {
+			k = i
+		}
+	}
+	axis.SetVec(k, 1)
+
+	u := Normalize(Cross2(axis, n))
+	return &planeFrame{origin: origin, u: u, v: Cross2(n, u), n: n}
+}
+
+// project 将点变换到平面坐标系，x/y为平面内坐标，z为到平面的有向距离
+func (f *planeFrame) project(p *mat.VecDense) *mat.VecDense {
+	d := SubVec(mat.NewVecDense(3, nil), p, f.origin)
+	return mat.NewVecDense(3, []float64{mat.Dot(d, f.u), mat.Dot(d, f.v), mat.Dot(d, f.n)})
+}
+
+// projectAll 将一组点变换到平面坐标系，并记录投影点到原始点的映射
+func (f *planeFrame) projectAll(points []*mat.VecDense, origin map[*mat.VecDense]*mat.VecDense) []*mat.VecDense {
+	res := make([]*mat.VecDense, len(points))
+	for i, p := range points {
+		res[i] = f.project(p)
+		origin[res[i]] = p
+	}
+	return res
+}
+
+// restoreTriangles 将平面坐标系中的三角形还原为原始顶点
+func restoreTriangles(tris []Triangle, origin map[*mat.VecDense]*mat.VecDense) []Triangle {
+	for i := range tris {
+		for j := range tris[i].P {
+			tris[i].P[j] = origin[tris[i].P[j]]
+		}
+	}
+	return tris
+}
+
+// PlanarEarClippingTriangulation 对三维空间中的平面多边形进行耳切法三角剖分
+// 在外边界的Newell法向量所确定的平面内剖分后映射回原始顶点，三角形的法向量与外边界绕向满足右手法则；不严格共面时按投影处理
+func PlanarEarClippingTriangulation(polygon []*mat.VecDense, holes ...[]*mat.VecDense) ([]Triangle, error) {
+	normal := newellNormal(polygon)
+	center, fitted := BestFitPlane(polygon)
+	if mat.Norm(normal, 2) == 0 {
+		normal = fitted
+	}
+	frame := newPlaneFrame(center, Normalize(normal))
+
+	origin := make(map[*mat.VecDense]*mat.VecDense)
+	projected := frame.projectAll(polygon, origin)
+	projectedHoles := make([][]*mat.VecDense, len(holes))
+	for i, hole := range holes {
+		projectedHoles[i] = frame.projectAll(hole, origin)
+	}
+
+	tris, err := EarClippingTriangulation(projected, projectedHoles...)
+	if err != nil {
+		return nil, err
+	}
+	return restoreTriangles(tris, origin), nil
+}
+
+// PlanarDelaunay 对三维空间中近似共面的点集进行Delaunay三角剖分
+// 在BestFitPlane拟合的平面内剖分后映射回原始顶点，三角形的法向量与拟合平面的法向量同向
+func PlanarDelaunay(points []*mat.VecDense) []Triangle {
+	center, normal := BestFitPlane(points)
+	frame := newPlaneFrame(center, normal)
+
+	origin := make(map[*mat.VecDense]*mat.VecDense, len(points))
+	return restoreTriangles(Delaunay(frame.projectAll(points, origin)), origin)
+}
@@ -0,0 +1,73 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// rotateRing 将环绕过原点、方向为axis的轴旋转theta弧度后平移offset
+func rotateRing(ring []*mat.VecDense, axis *mat.VecDense, theta float64, offset *mat.VecDense) []*mat.VecDense {
+	m := RotateMatrix(axis, vec3(0, 0, 0), theta)
+	res := make([]*mat.VecDense, len(ring))
+	for i, p := range ring {
+		res[i] = AddVec(mat.NewVecDense(3, nil), TransformPoint(m, p), offset)
+	}
+	return res
+}
+
+func TestPlanarEarClippingTriangulation(t *testing.T) {
+	// 位于竖直平面内的多边形，直接按xy投影会退化
+	axis, offset := vec3(1, 1, 0), vec3(1, 2, 3)
+	outer := rotateRing(circleRing(0, 0, 4, 0.6, 12, false), axis, math.Pi/2, offset)
+	hole := rotateRing(circleRing(0, 0, 1, 1, 6, true), axis, math.Pi/2, offset)
+	tris, err := PlanarEarClippingTriangulation(outer, hole)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := make(map[*mat.VecDense]bool)
+	for _, p := range append(append([]*mat.VecDense{}, outer...), hole...) {
+		input[p] = true
+	}
+	normal := Normalize(newellNormal(outer))
+	area := 0.0
+	for _, tri := range tris {
+		for _, p := range tri.P {
+			if !input[p] {
+				t.Fatalf("triangle vertex %v is not an input vertex", p.RawVector().Data)
+			}
+		}
+		n := Cross2(SubVec(mat.NewVecDense(3, nil), tri.P[1], tri.P[0]), SubVec(mat.NewVecDense(3, nil), tri.P[2], tri.P[0]))
+		if mat.Dot(n, normal) <= 0 {
+			t.Fatalf("triangle normal %v opposes the polygon normal", n.RawVector().Data)
+		}
+		area += mat.Norm(n, 2) / 2
+	}
+	want := math.Abs(polygonArea2D(circleRing(0, 0, 4, 0.6, 12, false))) - math.Abs(polygonArea2D(circleRing(0, 0, 1, 1, 6, true)))
+	if math.Abs(area-want) > 1e-9 {
+		t.Errorf("area %g, want %g", area, want)
+	}
+}
+
+func TestPlanarDelaunay(t *testing.T) {
+	var flat []*mat.VecDense
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 4; j++ {
+			flat = append(flat, vec3(float64(i)+0.1*float64(j*j), float64(j), 0))
+		}
+	}
+	points := rotateRing(flat, vec3(0, 1, 0), 1, vec3(0, 0, 5))
+	_, normal := BestFitPlane(points)
+	tris := PlanarDelaunay(points)
+	if want := len(Delaunay(flat)); len(tris) != want {
+		t.Errorf("got %d triangles, want %d", len(tris), want)
+	}
+	for _, tri := range tris {
+		n := Cross2(SubVec(mat.NewVecDense(3, nil), tri.P[1], tri.P[0]), SubVec(mat.NewVecDense(3, nil), tri.P[2], tri.P[0]))
+		if mat.Dot(n, normal) <= 0 {
+			t.Fatalf("triangle normal %v opposes the fitted normal", n.RawVector().Data)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// 自适应精度几何谓词 (Shewchuk): 先用浮点数计算并估计误差上界，
+// 结果符号不可靠时退化为浮点展开式(expansion)的精确运算
+
+var (
+	epsilon      = math.Ldexp(1, -53)
+	ccwErrBoundA = (3 + 16*epsilon) * epsilon
+	iccErrBoundA = (10 + 96*epsilon) * epsilon
+)
+
+// Orient2D 判断点c相对于有向直线ab的位置: 正值表示abc逆时针，负值表示顺时针，零表示共线，只使用x/y分量
+func Orient2D(a, b, c *mat.VecDense) float64 {
+	ax, ay := a.AtVec(0), a.AtVec(1)
+	bx, by := b.AtVec(0), b.AtVec(1)
+	cx, cy := c.AtVec(0), c.AtVec(1)
+
+	detLeft := (ax - cx) * (by - cy)
+	detRight := (ay - cy) * (bx - cx)
+	det := detLeft - detRight
+	errBound := ccwErrBoundA * (math.Abs(detLeft) + math.Abs(detRight))
+	if det > errBound || -det > errBound {
+		return det
+	}
+
+	// 精确计算 ax*by - ax*cy - ay*bx + ay*cx + bx*cy - by*cx
+	e := sumExpansions(
+		productExpansion(ax, by), productExpansion(-ax, cy),
+		productExpansion(-ay, bx), productExpansion(ay, cx),
+		productExpansion(bx, cy), productExpansion(-by, cx),
+	)
+	return expansionEstimate(e)
+}
+
+// InCircle 判断点d是否在逆时针三角形abc的外接圆内: 正值表示在圆内，负值表示在圆外，零表示四点共圆，只使用x/y分量
+func InCircle(a, b, c, d *mat.VecDense) float64 {
+	adx, ady := a.AtVec(0)-d.AtVec(0), a.AtVec(1)-d.AtVec(1)
+	bdx, bdy := b.AtVec(0)-d.AtVec(0), b.AtVec(1)-d.AtVec(1)
+	cdx, cdy := c.AtVec(0)-d.AtVec(0), c.AtVec(1)-d.AtVec(1)
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	aLift := adx*adx + ady*ady
+	bLift := bdx*bdx + bdy*bdy
+	cLift := cdx*cdx + cdy*cdy
+
+	det := aLift*(bdxcdy-cdxbdy) + bLift*(cdxady-adxcdy) + cLift*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*aLift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*bLift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*cLift
+	errBound := iccErrBoundA * permanent
+	if det > errBound || -det > errBound {
+		return det
+	}
+
+	// 精确计算: 坐标差以两项展开式表示，之后全部使用展开式乘法
+	eadx, eady := diffExpansion(a.AtVec(0), d.AtVec(0)), diffExpansion(a.AtVec(1), d.AtVec(1))
+	ebdx, ebdy := diffExpansion(b.AtVec(0), d.AtVec(0)), diffExpansion(b.AtVec(1), d.AtVec(1))
+	ecdx, ecdy := diffExpansion(c.AtVec(0), d.AtVec(0)), diffExpansion(c.AtVec(1), d.AtVec(1))
+
+	eaLift := sumExpansions(mulExpansion(eadx, eadx), mulExpansion(eady, eady))
+	ebLift := sumExpansions(mulExpansion(ebdx, ebdx), mulExpansion(ebdy, ebdy))
+	ecLift := sumExpansions(mulExpansion(ecdx, ecdx), mulExpansion(ecdy, ecdy))
+
+	bc := sumExpansions(mulExpansion(ebdx, ecdy), negateExpansion(mulExpansion(ecdx, ebdy)))
+	ca := sumExpansions(mulExpansion(ecdx, eady), negateExpansion(mulExpansion(eadx, ecdy)))
+	ab := sumExpansions(mulExpansion(eadx, ebdy), negateExpansion(mulExpansion(ebdx, eady)))
+
+	e := sumExpansions(mulExpansion(eaLift, bc), mulExpansion(ebLift, ca), mulExpansion(ecLift, ab))
+	return expansionEstimate(e)
+}
+
+// twoSum 计算a+b，x为浮点结果，y为舍入误差，满足 a+b = x+y
+func twoSum(a, b float64) (x, y float64) {
+	x = a + b
+	bv := x - a
+	av := x - bv
+	y = (a - av) + (b - bv)
+	return x, y
+}
+
+// twoProduct 计算a*b，x为浮点结果，y为舍入误差，满足 a*b = x+y
+func twoProduct(a, b float64) (x, y float64) {
+	x = a * b
+	y = math.FMA(a, b, -x)
+	return x, y
+}
+
+// productExpansion 以展开式表示a*b
+func productExpansion(a, b float64) []float64 {
+	x, y := twoProduct(a, b)
+	return compactExpansion(y, x)
+}
+
+// diffExpansion 以展开式表示a-b
+func diffExpansion(a, b float64) []float64 {
+	x, y := twoSum(a, -b)
+	return compactExpansion(y, x)
+}
+
+// compactExpansion 按量级由小到大组成展开式并去掉零分量
+func compactExpansion(components ...float64) []float64 {
+	res := make([]float64, 0, len(components))
+	for _, c := range components {
+		if c != 0 {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// growExpansion 将标量b加到展开式e上 (Shewchuk grow_expansion_zeroelim)
+func growExpansion(e []float64, b float64) []float64 {
+	res := make([]float64, 0, len(e)+1)
+	q := b
+	for _, c := range e {
+		var h float64
+		q, h = twoSum(q, c)
+		if h != 0 {
+			res = append(res, h)
+		}
+	}
+	if q != 0 {
+		res = append(res, q)
+	}
+	return res
+}
+
+// sumExpansions 计算若干展开式之和
+func sumExpansions(es ...[]float64) []float64 {
+	var res []float64
+	for _, e := range es {
+		for _, c := range e {
+			res = growExpansion(res, c)
+		}
+	}
+	return res
+}
+
+// scaleExpansion 计算展开式e与标量b之积 (Shewchuk scale_expansion_zeroelim)
+func scaleExpansion(e []float64, b float64) []float64 {
+	if len(e) == 0 || b == 0 {
+		return nil
+	}
+
+	res := make([]float64, 0, 2*len(e))
+	q, h := twoProduct(e[0], b)
+	if h != 0 {
+		res = append(res, h)
+	}
+	for _, c := range e[1:] {
+		p1, p0 := twoProduct(c, b)
+		sum, h := twoSum(q, p0)
+		if h != 0 {
+			res = append(res, h)
+		}
+		q, h = twoSum(p1, sum)
+		if h != 0 {
+			res = append(res, h)
+		}
+	}
+	if q != 0 {
+		res = append(res, q)
+	}
+	return res
+}
+
+// mulExpansion 计算两个展开式之积
+func mulExpansion(e, f []float64) []float64 {
+	var res []float64
+	for _, c := range f {
+		res = sumExpansions(res, scaleExpansion(e, c))
+	}
+	return res
+}
+
+// negateExpansion 返回展开式的相反数
+func negateExpansion(e []float64) []float64 {
+	res := make([]float64, len(e))
+	for i, c := range e {
+		res[i] = -c
+	}
+	return res
+}
+
+// expansionEstimate 返回展开式的近似值，其符号与精确值一致
+func expansionEstimate(e []float64) float64 {
+	sum := 0.0
+	for _, c := range e {
+		sum += c
+	}
+	return sum
+}
+
+var (
+	o3dErrBoundA = (7 + 56*epsilon) * epsilon
+	ispErrBoundA = (16 + 224*epsilon) * epsilon
+)
+
+// Orient3D 判断点d相对于平面abc的位置: 正值表示d位于(b-a)×(c-a)所指的一侧，负值表示另一侧，零表示四点共面
+func Orient3D(a, b, c, d *mat.VecDense) float64 {
+	adx, ady, adz := a.AtVec(0)-d.AtVec(0), a.AtVec(1)-d.AtVec(1), a.AtVec(2)-d.AtVec(2)
+	bdx, bdy, bdz := b.AtVec(0)-d.AtVec(0), b.AtVec(1)-d.AtVec(1), b.AtVec(2)-d.AtVec(2)
+	cdx, cdy, cdz := c.AtVec(0)-d.AtVec(0), c.AtVec(1)-d.AtVec(1), c.AtVec(2)-d.AtVec(2)
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+
+	// det为Shewchuk的orient3d，与本函数的符号相反
+	det := adz*(bdxcdy-cdxbdy) + bdz*(cdxady-adxcdy) + cdz*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*math.Abs(adz) +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*math.Abs(bdz) +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*math.Abs(cdz)
+	errBound := o3dErrBoundA * permanent
+	if det > errBound || -det > errBound {
+		return -det
+	}
+
+	rows := [3][3][]float64{differenceRow(a, d), differenceRow(b, d), differenceRow(c, d)}
+	return -expansionEstimate(det3Expansion(rows))
+}
+
+// InSphere 判断点e是否在Orient3D(a, b, c, d) > 0的四面体abcd的外接球内: 正值表示在球内，负值表示在球外，零表示五点共球
+func InSphere(a, b, c, d, e *mat.VecDense) float64 {
+	aex, aey, aez := a.AtVec(0)-e.AtVec(0), a.AtVec(1)-e.AtVec(1), a.AtVec(2)-e.AtVec(2)
+	bex, bey, bez := b.AtVec(0)-e.AtVec(0), b.AtVec(1)-e.AtVec(1), b.AtVec(2)-e.AtVec(2)
+	cex, cey, cez := c.AtVec(0)-e.AtVec(0), c.AtVec(1)-e.AtVec(1), c.AtVec(2)-e.AtVec(2)
+	dex, dey, dez := d.AtVec(0)-e.AtVec(0), d.AtVec(1)-e.AtVec(1), d.AtVec(2)-e.AtVec(2)
+
+	aexbey, bexaey := aex*bey, bex*aey
+	bexcey, cexbey := bex*cey, cex*bey
+	cexdey, dexcey := cex*dey, dex*cey
+	dexaey, aexdey := dex*aey, aex*dey
+	aexcey, cexaey := aex*cey, cex*aey
+	bexdey, dexbey := bex*dey, dex*bey
+
+	ab, bc, cd, da := aexbey-bexaey, bexcey-cexbey, cexdey-dexcey, dexaey-aexdey
+	ac, bd := aexcey-cexaey, bexdey-dexbey
+
+	abc := aez*bc - bez*ac + cez*ab
+	bcd := bez*cd - cez*bd + dez*bc
+	cda := cez*da + dez*ac + aez*cd
+	dab := dez*ab + aez*bd + bez*da
+
+	aLift := aex*aex + aey*aey + aez*aez
+	bLift := bex*bex + bey*bey + bez*bez
+	cLift := cex*cex + cey*cey + cez*cez
+	dLift := dex*dex + dey*dey + dez*dez
+
+	// det为Shewchuk的insphere，以其orient3d为正的四面体为准，与本函数的符号相反
+	det := (dLift*abc - cLift*dab) + (bLift*cda - aLift*bcd)
+
+	aez, bez, cez, dez = math.Abs(aez), math.Abs(bez), math.Abs(cez), math.Abs(dez)
+	aexbey, bexaey = math.Abs(aexbey), math.Abs(bexaey)
+	bexcey, cexbey = math.Abs(bexcey), math.Abs(cexbey)
+	cexdey, dexcey = math.Abs(cexdey), math.Abs(dexcey)
+	dexaey, aexdey = math.Abs(dexaey), math.Abs(aexdey)
+	aexcey, cexaey = math.Abs(aexcey), math.Abs(cexaey)
+	bexdey, dexbey = math.Abs(bexdey), math.Abs(dexbey)
+	permanent := ((cexdey+dexcey)*bez+(dexbey+bexdey)*cez+(bexcey+cexbey)*dez)*aLift +
+		((dexaey+aexdey)*cez+(aexcey+cexaey)*dez+(cexdey+dexcey)*aez)*bLift +
+		((aexbey+bexaey)*dez+(bexdey+dexbey)*aez+(dexaey+aexdey)*bez)*cLift +
+		((bexcey+cexbey)*aez+(cexaey+aexcey)*bez+(aexbey+bexaey)*cez)*dLift
+	errBound := ispErrBoundA * permanent
+	if det > errBound || -det > errBound {
+		return -det
+	}
+
+	// 精确计算: 按第四列(提升坐标)展开4x4行列式
+	rows := [4][3][]float64{differenceRow(a, e), differenceRow(b, e), differenceRow(c, e), differenceRow(d, e)}
+	var lifts [4][]float64
+	for i, r := range rows {
+		lifts[i] = sumExpansions(mulExpansion(r[0], r[0]), mulExpansion(r[1], r[1]), mulExpansion(r[2], r[2]))
+	}
+	exact := sumExpansions(
+		negateExpansion(mulExpansion(lifts[0], det3Expansion([3][3][]float64{rows[1], rows[2], rows[3]}))),
+		mulExpansion(lifts[1], det3Expansion([3][3][]float64{rows[0], rows[2], rows[3]})),
+		negateExpansion(mulExpansion(lifts[2], det3Expansion([3][3][]float64{rows[0], rows[1], rows[3]}))),
+		mulExpansion(lifts[3], det3Expansion([3][3][]float64{rows[0], rows[1], rows[2]})),
+	)
+	return -expansionEstimate(exact)
+}
+
+// differenceRow 以展开式表示向量p-q的三个分量
+func differenceRow(p, q *mat.VecDense) [3][]float64 {
+	return [3][]float64{
+		diffExpansion(p.AtVec(0), q.AtVec(0)),
+		diffExpansion(p.AtVec(1), q.AtVec(1)),
+		diffExpansion(p.AtVec(2), q.AtVec(2)),
+	}
+}
+
+// det3Expansion 精确计算元素为展开式的3x3行列式
+func det3Expansion(m [3][3][]float64) []float64 {
+	minor := func(i, j int) []float64 {
+		return sumExpansions(mulExpansion(m[1][i], m[2][j]), negateExpansion(mulExpansion(m[1][j], m[2][i])))
+	}
+	return sumExpansions(
+		mulExpansion(m[0][0], minor(1, 2)),
+		negateExpansion(mulExpansion(m[0][1], minor(0, 2))),
+		mulExpansion(m[0][2], minor(0, 1)),
+	)
+}
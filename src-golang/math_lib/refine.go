@@ -0,0 +1,302 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// maxSteinerPoints 网格加密时插入Steiner点数量的上限，防止输入中存在很小的夹角时无法终止
+const maxSteinerPoints = 1 << 20
+
+// RefineDelaunay 对多边形区域进行Ruppert网格加密，参数含义与ConstrainedDelaunay相同
+// 不断插入坏三角形的外心并分割被侵占的约束边，直到所有内部三角形的最小角不小于minAngle(弧度)、面积不大于maxArea
+// minAngle不超过约0.59(约33.8°)时通常可以终止；maxArea<=0表示不限制面积；没有任何环时凸包边视为边界约束边
+func RefineDelaunay(boundaries, holes [][]*mat.VecDense, segments []Edge, minAngle, maxArea float64) []Triangle {
+	t := newConstrainedTriangulation(boundaries, holes, segments)
+	if t == nil {
+		return []Triangle{}
+	}
+	t.refine(minAngle, maxArea)
+	return t.insideTriangles()
+}
+
+// refiner 网格加密的状态: 待检查的约束边与三角形队列
+type refiner struct {
+	*triangulation
+	minAngle, maxArea float64
+	inputVertices     int
+	segments          [][3]int       // 约束边的两个端点，第三项非零表示无论是否被侵占都要分割
+	triangles         [][4]int       // 三角形下标及其顶点，用于判断出队时三角形是否已被替换
+	inputSegments     [][2]int       // 加密前的约束边
+	subsegmentOf      map[[2]int]int // 约束边所属的输入约束边
+	vertexOn          map[int]int    // 分割约束边产生的顶点所属的输入约束边
+}
+
+// refine 执行Ruppert加密，优先分割被侵占的约束边，其次分割坏三角形
+func (t *triangulation) refine(minAngle, maxArea float64) {
+	r := &refiner{
+		triangulation: t,
+		minAngle:      minAngle,
+		maxArea:       maxArea,
+		inputVertices: len(t.points),
+		subsegmentOf:  make(map[[2]int]int, len(t.constraints)),
+		vertexOn:      make(map[int]int),
+	}
+
+	// 没有环边界时区域为凸包，凸包边同样作为约束边，落在凸包外的外心改为分割被侵占的凸包边
+	hasRing := false
+	for _, kind := range t.constraints {
+		hasRing = hasRing || kind == constraintRing
+	}
+	if !hasRing {
+		for idx, tri := range t.tris {
+			if !tri.dead && t.isGhost(idx) {
+				t.addConstraint(tri.v[0], tri.v[1], constraintRing)
+			}
+		}
+	}
+
+	for key := range t.constraints {
+		r.subsegmentOf[key] = len(r.inputSegments)
+		r.inputSegments = append(r.inputSegments, key)
+	}
+	for idx := range t.tris {
+		if !t.tris[idx].dead {
+			r.enqueue(idx)
+		}
+	}
+
+	for steiner := 0; steiner < maxSteinerPoints; steiner++ {
+		if len(r.segments) > 0 {
+			seg := r.segments[len(r.segments)-1]
+			r.segments = r.segments[:len(r.segments)-1]
+			if r.isConstrained(seg[0], seg[1]) && (seg[2] != 0 || r.isEncroached(seg[0], seg[1])) {
+				r.splitSegment(seg[0], seg[1])
+			} else {
+				steiner--
+			}
+			continue
+		}
+
+		if len(r.triangles) == 0 {
+			return
+		}
+		item := r.triangles[0]
+		r.triangles = r.triangles[1:]
+		idx := item[0]
+		if r.tris[idx].dead || r.tris[idx].v != [3]int{item[1], item[2], item[3]} || !r.isBad(idx) {
+			steiner--
+			continue
+		}
+		r.splitTriangle(idx)
+	}
+}
+
+// enqueue 检查三角形的约束边是否被其对顶点侵占，以及三角形本身是否需要分割
+func (r *refiner) enqueue(idx int) {
+	v := r.tris[idx].v
+	for e := 0; e < 3; e++ {
+		a, b := v[(e+1)%3], v[(e+2)%3]
+		if v[e] != ghostVertex && r.isConstrained(a, b) && r.encroaches(v[e], a, b) {
+			r.segments = append(r.segments, [3]int{a, b, 0})
+		}
+	}
+	if r.isInside(idx) && r.isBad(idx) {
+		r.triangles = append(r.triangles, [4]int{idx, v[0], v[1], v[2]})
+	}
+}
+
+// encroaches 判断点p是否位于线段ab的直径圆内
+func (r *refiner) encroaches(p, a, b int) bool {
+	pp, pa, pb := r.points[p], r.points[a], r.points[b]
+	return (pa.AtVec(0)-pp.AtVec(0))*(pb.AtVec(0)-pp.AtVec(0))+(pa.AtVec(1)-pp.AtVec(1))*(pb.AtVec(1)-pp.AtVec(1)) < 0
+}
+
+// isEncroached 判断约束边ab是否被两侧三角形的对顶点侵占
+func (r *refiner) isEncroached(a, b int) bool {
+	for _, idx := range r.trianglesAround(a) {
+		v := r.tris[idx].v
+		if vertexSlot(v, b) < 0 {
+			continue
+		}
+		if o := oppositeSlotVertex(v, a, b); o != ghostVertex && r.encroaches(o, a, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBad 判断三角形是否面积过大或最小角过小
+func (r *refiner) isBad(idx int) bool {
+	v := r.tris[idx].v
+	a, b, c := r.points[v[0]], r.points[v[1]], r.points[v[2]]
+	area := Orient2D(a, b, c) / 2
+	if r.maxArea > 0 && area > r.maxArea {
+		return true
+	}
+
+	// 三个顶点都位于同一条输入约束边上时，三角形只是分割点舍入误差造成的狭长三角形，无法通过加密消除
+	if s, ok := r.vertexOn[v[0]]; ok && r.onInputSegment(v[1], s) && r.onInputSegment(v[2], s) {
+		return false
+	}
+	if s, ok := r.vertexOn[v[1]]; ok && r.onInputSegment(v[0], s) && r.onInputSegment(v[2], s) {
+		return false
+	}
+
+	// 最小角θ满足 sinθ = 最短边 / (2R)，R为外接圆半径
+	_, radius := circumcircle(a, b, c)
+	shortest, e := distance2D(b, c), 0
+	if l := distance2D(c, a); l < shortest {
+		shortest, e = l, 1
+	}
+	if l := distance2D(a, b); l < shortest {
+		shortest, e = l, 2
+	}
+	if shortest >= 2*radius*math.Sin(r.minAngle) {
+		return false
+	}
+
+	// 最短边两端分别位于两条共享端点的输入约束边上时，小角来自输入本身的小夹角，不再加密 (Shewchuk)
+	return !r.atSmallInputAngle(v[(e+1)%3], v[(e+2)%3])
+}
+
+// atSmallInputAngle 判断分割点p、q是否分别位于两条共享端点的不同输入约束边上，且到该端点的距离相等(位于同一同心圆壳上)
+func (r *refiner) atSmallInputAngle(p, q int) bool {
+	s1, ok1 := r.vertexOn[p]
+	s2, ok2 := r.vertexOn[q]
+	if !ok1 || !ok2 || s1 == s2 {
+		return false
+	}
+
+	for _, apex := range r.inputSegments[s1] {
+		if apex == r.inputSegments[s2][0] || apex == r.inputSegments[s2][1] {
+			dp, dq := distance2D(r.points[p], r.points[apex]), distance2D(r.points[q], r.points[apex])
+			if math.Abs(dp-dq) <= 1e-3*math.Max(dp, dq) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// onInputSegment 判断顶点是否位于第s条输入约束边上
+func (r *refiner) onInputSegment(p, s int) bool {
+	if on, ok := r.vertexOn[p]; ok {
+		return on == s
+	}
+	return p == r.inputSegments[s][0] || p == r.inputSegments[s][1]
+}
+
+// distance2D 计算两点在xy平面上的距离
+func distance2D(a, b *mat.VecDense) float64 {
+	return math.Hypot(a.AtVec(0)-b.AtVec(0), a.AtVec(1)-b.AtVec(1))
+}
+
+// splitSegment 分割约束边ab；端点之一为输入顶点时按同心圆壳(2的幂距离)选取分割点，避免小夹角处无限加密
+func (r *refiner) splitSegment(a, b int) {
+	if b < r.inputVertices && a >= r.inputVertices {
+		a, b = b, a
+	}
+	pa, pb := r.points[a], r.points[b]
+	length := distance2D(pa, pb)
+
+	ratio := 0.5
+	if (a < r.inputVertices) != (b < r.inputVertices) {
+		shell := math.Exp2(math.Round(math.Log2(length / 2)))
+		for shell > 2*length/3 {
+			shell /= 2
+		}
+		for shell < length/3 {
+			shell *= 2
+		}
+		ratio = shell / length
+	}
+
+	m := mat.NewVecDense(3, nil)
+	m.AddScaledVec(pa, ratio, SubVec(mat.NewVecDense(3, nil), pb, pa))
+
+	origin := r.subsegmentOf[edgeKey(a, b)]
+	delete(r.subsegmentOf, edgeKey(a, b))
+
+	idx := r.addPointOnEdge(m, a, b)
+	r.vertexOn[idx] = origin
+	r.subsegmentOf[edgeKey(a, idx)] = origin
+	r.subsegmentOf[edgeKey(idx, b)] = origin
+	r.enqueueAround(idx)
+}
+
+// splitTriangle 插入坏三角形的外心；外心侵占约束边、越过约束边或落在凸包外时改为分割相应的边，三角形稍后重新检查
+func (r *refiner) splitTriangle(idx int) {
+	v := r.tris[idx].v
+	center, radius := circumcircle(r.points[v[0]], r.points[v[1]], r.points[v[2]])
+	if math.IsInf(radius, 0) || math.IsNaN(radius) {
+		return
+	}
+	center.SetVec(2, (r.points[v[0]].AtVec(2)+r.points[v[1]].AtVec(2)+r.points[v[2]].AtVec(2))/3)
+
+	// 从坏三角形出发向外心行走，越过的第一条约束边即被外心侵占
+	cur := idx
+	for steps := 0; steps < len(r.tris); steps++ {
+		tv := r.tris[cur].v
+		next, a, b := -1, 0, 0
+		for e := 0; e < 3; e++ {
+			a, b = tv[(e+1)%3], tv[(e+2)%3]
+			if Orient2D(r.points[a], r.points[b], center) < 0 {
+				if r.isConstrained(a, b) {
+					r.deferTriangle(idx, a, b)
+					return
+				}
+				next = r.tris[cur].n[e]
+				break
+			}
+		}
+		if next < 0 {
+			break
+		}
+		if r.isGhost(next) {
+			// 外心位于凸包外，越过的凸包边作为约束边分割
+			key := edgeKey(a, b)
+			r.addConstraint(a, b, constraintSegment)
+			r.subsegmentOf[key] = len(r.inputSegments)
+			r.inputSegments = append(r.inputSegments, key)
+			r.deferTriangle(idx, a, b)
+			return
+		}
+		cur = next
+	}
+
+	// 外心所在空腔边界上的约束边若被外心侵占，同样改为分割约束边
+	r.points = append(r.points, center)
+	p := len(r.points) - 1
+	r.vertexTri = append(r.vertexTri, cur)
+	cavity := r.cavity(cur, p)
+	for _, c := range cavity {
+		tri := r.tris[c]
+		for e := 0; e < 3; e++ {
+			a, b := tri.v[(e+1)%3], tri.v[(e+2)%3]
+			if r.isConstrained(a, b) && r.encroaches(p, a, b) {
+				r.points = r.points[:p]
+				r.vertexTri = r.vertexTri[:p]
+				r.deferTriangle(idx, a, b)
+				return
+			}
+		}
+	}
+
+	r.insertFlip(p)
+	r.enqueueAround(p)
+}
+
+// deferTriangle 将约束边ab加入分割队列，并将坏三角形重新入队
+func (r *refiner) deferTriangle(idx, a, b int) {
+	r.segments = append(r.segments, [3]int{a, b, 1})
+	v := r.tris[idx].v
+	r.triangles = append(r.triangles, [4]int{idx, v[0], v[1], v[2]})
+}
+
+// enqueueAround 检查新顶点周围的所有三角形
+func (r *refiner) enqueueAround(p int) {
+	for _, idx := range r.trianglesAround(p) {
+		r.enqueue(idx)
+	}
+}
@@ -0,0 +1,69 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// smallestAngle 返回三角形集合中最小的内角(弧度)
+func smallestAngle(tris []Triangle) float64 {
+	res := math.Pi
+	for _, tri := range tris {
+		for k := 0; k < 3; k++ {
+			p, q, r := tri.P[k], tri.P[(k+1)%3], tri.P[(k+2)%3]
+			ux, uy := q.AtVec(0)-p.AtVec(0), q.AtVec(1)-p.AtVec(1)
+			vx, vy := r.AtVec(0)-p.AtVec(0), r.AtVec(1)-p.AtVec(1)
+			res = math.Min(res, math.Abs(math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)))
+		}
+	}
+	return res
+}
+
+func TestRefineDelaunay(t *testing.T) {
+	outer := []*mat.VecDense{vec3(0, 0, 0), vec3(6, 0, 0), vec3(6, 1, 0), vec3(3, 4, 0), vec3(0, 1, 0)}
+	hole := []*mat.VecDense{vec3(2, 0.5, 0), vec3(4, 0.5, 0), vec3(3, 1.5, 0)}
+	minAngle := 25 * math.Pi / 180
+	tris := RefineDelaunay([][]*mat.VecDense{outer}, [][]*mat.VecDense{hole}, nil, minAngle, 0.2)
+
+	if area := triangleArea2D(tris); math.Abs(area-14) > 1e-9 {
+		t.Errorf("area %g, want 14", area)
+	}
+	if angle := smallestAngle(tris); angle < minAngle-1e-9 {
+		t.Errorf("smallest angle %g°, want at least 25°", angle*180/math.Pi)
+	}
+	for _, tri := range tris {
+		if a := Orient2D(tri.P[0], tri.P[1], tri.P[2]) / 2; a > 0.2+1e-12 {
+			t.Fatalf("triangle area %g exceeds 0.2", a)
+		}
+	}
+}
+
+// 外心落在凸包外且越过的凸包边不是约束边时，分割该凸包边而不是丢弃坏三角形
+func TestRefineCircumcenterOutsideHull(t *testing.T) {
+	tr := newConstrainedTriangulation(nil, nil, []Edge{{vec3(0, 0, 0), vec3(2, 0.3, 0)}, {vec3(4, 0, 0), vec3(2, 3, 0)}})
+	r := &refiner{triangulation: tr, minAngle: 25 * math.Pi / 180, inputVertices: len(tr.points), subsegmentOf: map[[2]int]int{}, vertexOn: map[int]int{}}
+	for key := range tr.constraints {
+		r.subsegmentOf[key] = len(r.inputSegments)
+		r.inputSegments = append(r.inputSegments, key)
+	}
+
+	flat := -1
+	for idx, tri := range tr.tris {
+		if !tri.dead && !tr.isGhost(idx) && math.Abs(tr.orient(tri.v[0], tri.v[1], tri.v[2])) < 1.3 {
+			flat = idx
+		}
+	}
+	if flat < 0 || !r.isBad(flat) {
+		t.Fatalf("flat triangle not found")
+	}
+	r.splitTriangle(flat)
+	if len(r.segments) != 1 || len(r.triangles) != 1 || r.triangles[0][0] != flat {
+		t.Fatalf("got %d segments and %d triangles queued", len(r.segments), len(r.triangles))
+	}
+	a, b := r.points[r.segments[0][0]], r.points[r.segments[0][1]]
+	if a.AtVec(1) != 0 || b.AtVec(1) != 0 || !r.isConstrained(r.segments[0][0], r.segments[0][1]) {
+		t.Errorf("queued edge %v-%v is not the bottom hull edge", a.RawVector().Data, b.RawVector().Data)
+	}
+}
@@ -0,0 +1,325 @@
+package math_lib
+
+import (
+	"fmt"
+	"math"
+)
+
+// SparseMatrix 按行保存非零元素的n×n稀疏矩阵，适用于网格上每行只有少量非零元素的算子
+type SparseMatrix struct {
+	rows [][]sparseEntry
+}
+
+// sparseEntry 一行中的一个非零元素
+type sparseEntry struct {
+	col   int
+	value float64
+}
+
+// NewSparseMatrix 创建n×n的零矩阵
+func NewSparseMatrix(n int) *SparseMatrix {
+	return &SparseMatrix{rows: make([][]sparseEntry, n)}
+}
+
+// Size 返回矩阵的阶数
+func (a *SparseMatrix) Size() int {
+	return len(a.rows)
+}
+
+// Add 将v累加到第i行第j列
+func (a *SparseMatrix) Add(i, j int, v float64) {
+	for k := range a.rows[i] {
+		if a.rows[i][k].col == j {
+			a.rows[i][k].value += v
+			return
+		}
+	}
+	a.rows[i] = append(a.rows[i], sparseEntry{col: j, value: v})
+}
+
+// At 返回第i行第j列的元素
+func (a *SparseMatrix) At(i, j int) float64 {
+	for _, e := range a.rows[i] {
+		if e.col == j {
+			return e.value
+		}
+	}
+	return 0
+}
+
+// MulVec 计算矩阵与向量x的乘积
+func (a *SparseMatrix) MulVec(x []float64) []float64 {
+	res := make([]float64, len(a.rows))
+	for i, row := range a.rows {
+		for _, e := range row {
+			res[i] += e.value * x[e.col]
+		}
+	}
+	return res
+}
+
+// SolveCG 用Jacobi预处理的共轭梯度法求解对称(半)正定方程组 Ax = b，残差的2范数不超过tolerance·|b|时返回
+// 半正定的奇异方程组在b属于值域时收敛到其中一个解
+func (a *SparseMatrix) SolveCG(b []float64, tolerance float64, maxIterations int) ([]float64, error) {
+	n := len(a.rows)
+	x := make([]float64, n)
+	dot := func(p, q []float64) float64 {
+		res := 0.0
+		for i := range p {
+			res += p[i] * q[i]
+		}
+		return res
+	}
+	limit := tolerance * math.Sqrt(dot(b, b))
+	if limit == 0 {
+		return x, nil
+	}
+
+	inv := make([]float64, n)
+	for i := range inv {
+		inv[i] = 1
+		if d := a.At(i, i); d > 0 {
+			inv[i] = 1 / d
+		}
+	}
+	r := append([]float64(nil), b...)
+	z := make([]float64, n)
+	for i := range z {
+		z[i] = inv[i] * r[i]
+	}
+	p := append([]float64(nil), z...)
+	rz := dot(r, z)
+	for it := 0; it < maxIterations; it++ {
+		if math.Sqrt(dot(r, r)) <= limit {
+			return x, nil
+		}
+		ap := a.MulVec(p)
+		pap := dot(p, ap)
+		if pap <= 0 {
+			break
+		}
+		alpha := rz / pap
+		for i := range x {
+			x[i] += alpha * p[i]
+			r[i] -= alpha * ap[i]
+			z[i] = inv[i] * r[i]
+		}
+		next := dot(r, z)
+		beta := next / rz
+		rz = next
+		for i := range p {
+			p[i] = z[i] + beta*p[i]
+		}
+	}
+	if residual := math.Sqrt(dot(r, r)); residual > limit {
+		return x, fmt.Errorf("conjugate gradient: residual %g after %d iterations", residual, maxIterations)
+	}
+	return x, nil
+}
+
+// SparseCholesky 对称正定稀疏矩阵的 LDLᵀ 分解，按嵌套剖分重排序以减少填充；L按列保存严格下三角部分
+type SparseCholesky struct {
+	perm   []int // 第k个消去的原始行号
+	colPtr []int
+	rowIdx []int
+	values []float64
+	diag   []float64
+}
+
+// Cholesky 对对称正定矩阵做 LDLᵀ 分解(Davis的up-looking LDL算法)，每行只读取按消去顺序不晚于该行的列
+// 对M矩阵(如锐角网格上的 A - tL)回代过程没有相消，解中很小的分量也有较高的相对精度，这是迭代法做不到的；矩阵不正定时返回错误
+func (a *SparseMatrix) Cholesky() (*SparseCholesky, error) {
+	n := len(a.rows)
+	perm := a.nestedDissection()
+	inv := make([]int, n)
+	for k, i := range perm {
+		inv[i] = k
+	}
+
+	// 符号分解：消去树与L每列的非零元数
+	parent, flag, count := make([]int, n), make([]int, n), make([]int, n)
+	for k := 0; k < n; k++ {
+		parent[k], flag[k] = -1, k
+		for _, e := range a.rows[perm[k]] {
+			for i := inv[e.col]; i < k && flag[i] != k; i = parent[i] {
+				if parent[i] == -1 {
+					parent[i] = k
+				}
+				count[i]++
+				flag[i] = k
+			}
+		}
+	}
+	c := &SparseCholesky{perm: perm, colPtr: make([]int, n+1), diag: make([]float64, n)}
+	for k := 0; k < n; k++ {
+		c.colPtr[k+1] = c.colPtr[k] + count[k]
+	}
+	c.rowIdx = make([]int, c.colPtr[n])
+	c.values = make([]float64, c.colPtr[n])
+
+	// 数值分解：第k行 L[k,:] 由 L[:k,:k] 的稀疏三角求解得到，非零位置为消去树上的路径
+	y, pattern, filled := make([]float64, n), make([]int, n), make([]int, n)
+	for k := 0; k < n; k++ {
+		top := n
+		flag[k] = k
+		for _, e := range a.rows[perm[k]] {
+			i := inv[e.col]
+			if i > k {
+				continue
+			}
+			y[i] += e.value
+			length := 0
+			for ; flag[i] != k; i = parent[i] {
+				pattern[length] = i
+				length++
+				flag[i] = k
+			}
+			for length > 0 {
+				top--
+				length--
+				pattern[top] = pattern[length]
+			}
+		}
+
+		d := y[k]
+		y[k] = 0
+		for ; top < n; top++ {
+			i := pattern[top]
+			yi := y[i]
+			y[i] = 0
+			end := c.colPtr[i] + filled[i]
+			for p := c.colPtr[i]; p < end; p++ {
+				y[c.rowIdx[p]] -= c.values[p] * yi
+			}
+			l := yi / c.diag[i]
+			d -= l * yi
+			c.rowIdx[end], c.values[end] = k, l
+			filled[i]++
+		}
+		if !(d > 0) {
+			return nil, fmt.Errorf("cholesky: matrix is not positive definite (pivot %g at row %d)", d, perm[k])
+		}
+		c.diag[k] = d
+	}
+	return c, nil
+}
+
+// Solve 用分解求解 Ax = b
+func (c *SparseCholesky) Solve(b []float64) []float64 {
+	n := len(c.perm)
+	x := make([]float64, n)
+	for k, i := range c.perm {
+		x[k] = b[i]
+	}
+	for j := 0; j < n; j++ {
+		for p := c.colPtr[j]; p < c.colPtr[j+1]; p++ {
+			x[c.rowIdx[p]] -= c.values[p] * x[j]
+		}
+	}
+	for j := range x {
+		x[j] /= c.diag[j]
+	}
+	for j := n - 1; j >= 0; j-- {
+		for p := c.colPtr[j]; p < c.colPtr[j+1]; p++ {
+			x[j] -= c.values[p] * x[c.rowIdx[p]]
+		}
+	}
+
+	res := make([]float64, n)
+	for k, i := range c.perm {
+		res[i] = x[k]
+	}
+	return res
+}
+
+// nestedDissection 返回矩阵非零结构对应的图的嵌套剖分消去顺序：
+// 从伪外围顶点出发广度优先分层，取中间一层为分隔集，两侧分别递归排序后再排分隔集，网格类矩阵的填充为 O(n log n)
+func (a *SparseMatrix) nestedDissection() []int {
+	n := len(a.rows)
+	order := make([]int, 0, n)
+	part := make([]int, n) // 顶点当前所在子集的编号
+	level := make([]int, n)
+	next := 1
+
+	// bfs 在子集id内从start出发分层，返回按层次排列的可达顶点
+	bfs := func(id, start int) []int {
+		visited := []int{start}
+		level[start] = 0
+		part[start] = -id
+		for k := 0; k < len(visited); k++ {
+			v := visited[k]
+			for _, e := range a.rows[v] {
+				if part[e.col] == id {
+					part[e.col] = -id
+					level[e.col] = level[v] + 1
+					visited = append(visited, e.col)
+				}
+			}
+		}
+		for _, v := range visited {
+			part[v] = id
+		}
+		return visited
+	}
+
+	var dissect func(vertices []int)
+	dissect = func(vertices []int) {
+		for len(vertices) > 0 {
+			if len(vertices) <= 32 {
+				order = append(order, vertices...)
+				return
+			}
+			id := next
+			next++
+			for _, v := range vertices {
+				part[v] = id
+			}
+
+			// 两次广度优先搜索得到伪外围顶点，不连通时先处理起点所在的连通分量
+			reached := bfs(id, vertices[0])
+			reached = bfs(id, reached[len(reached)-1])
+			if len(reached) < len(vertices) {
+				rest := make([]int, 0, len(vertices)-len(reached))
+				for _, v := range reached {
+					part[v] = 0
+				}
+				for _, v := range vertices {
+					if part[v] == id {
+						rest = append(rest, v)
+					}
+				}
+				dissect(reached)
+				vertices = rest
+				continue
+			}
+
+			middle := level[reached[len(reached)-1]] / 2
+			if middle == 0 {
+				order = append(order, vertices...)
+				return
+			}
+			var low, high, separator []int
+			for _, v := range reached {
+				switch {
+				case level[v] < middle:
+					low = append(low, v)
+				case level[v] > middle:
+					high = append(high, v)
+				default:
+					separator = append(separator, v)
+				}
+			}
+			dissect(low)
+			dissect(high)
+			order = append(order, separator...)
+			return
+		}
+	}
+
+	all := make([]int, n)
+	for i := range all {
+		all[i] = i
+	}
+	dissect(all)
+	return order
+}
@@ -0,0 +1,189 @@
+package math_lib
+
+import (
+	"container/heap"
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+const (
+	normalNeighbors   = 12 // 估计法向量时使用的近邻数
+	distanceNeighbors = 8  // 计算带符号距离时使用的近邻数
+	maskSpacings      = 3  // 重建结果中离最近采样点超过该倍数平均点距的三角形被删除
+)
+
+// EstimateNormals 用每个点k个近邻的最小二乘平面估计单位法向量，再沿近邻图的最小生成树传播使相邻法向量方向一致 (Hoppe)
+// 每个连通部分从z坐标最大的点开始传播，该点的法向量朝向+z，因此封闭曲面的法向量指向外侧
+func EstimateNormals(points []*mat.VecDense, k int) []*mat.VecDense {
+	normals := make([]*mat.VecDense, len(points))
+	if len(points) == 0 {
+		return normals
+	}
+	k = min(k, len(points))
+
+	tree := newKDTree(points)
+	neighbors := make([][]int, len(points))
+	for i, p := range points {
+		ids, _ := tree.nearest(p, k)
+		neighbors[i] = ids
+		local := make([]*mat.VecDense, len(ids))
+		for j, id := range ids {
+			local[j] = points[id]
+		}
+		_, normals[i] = BestFitPlane(local)
+	}
+
+	// 近邻关系不对称，将其补为无向图
+	graph := make([][]int, len(points))
+	for i, ids := range neighbors {
+		for _, j := range ids {
+			if i != j {
+				graph[i] = append(graph[i], j)
+				graph[j] = append(graph[j], i)
+			}
+		}
+	}
+
+	visited := make([]bool, len(points))
+	for {
+		start := -1
+		for i, p := range points {
+			if !visited[i] && (start < 0 || p.AtVec(2) > points[start].AtVec(2)) {
+				start = i
+			}
+		}
+		if start < 0 {
+			return normals
+		}
+		if normals[start].AtVec(2) < 0 {
+			normals[start].ScaleVec(-1, normals[start])
+		}
+		propagateNormals(start, graph, normals, visited)
+	}
+}
+
+// propagateNormals 以1-|ni·nj|为边权用Prim算法遍历最小生成树，使每个点的法向量与其父节点同向
+func propagateNormals(start int, graph [][]int, normals []*mat.VecDense, visited []bool) {
+	queue := &normalQueue{{to: start, from: start}}
+	for queue.Len() > 0 {
+		e := heap.Pop(queue).(normalEdge)
+		if visited[e.to] {
+			continue
+		}
+		visited[e.to] = true
+		if mat.Dot(normals[e.from], normals[e.to]) < 0 {
+			normals[e.to].ScaleVec(-1, normals[e.to])
+		}
+
+		for _, j := range graph[e.to] {
+			if !visited[j] {
+				heap.Push(queue, normalEdge{to: j, from: e.to, weight: 1 - math.Abs(mat.Dot(normals[e.to], normals[j]))})
+			}
+		}
+	}
+}
+
+// normalEdge 最小生成树的候选边
+type normalEdge struct {
+	to, from int
+	weight   float64
+}
+
+// normalQueue 按边权排序的小根堆
+type normalQueue []normalEdge
+
+func (q normalQueue) Len() int           { return len(q) }
+func (q normalQueue) Less(i, j int) bool { return q[i].weight < q[j].weight }
+func (q normalQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *normalQueue) Push(x any)        { *q = append(*q, x.(normalEdge)) }
+func (q *normalQueue) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+// SignedDistance 由带法向量的点云构造带符号距离函数，法向量所指的外侧为正
+// 取k个最近点的切平面距离，以到第k个近邻的距离为带宽按高斯权重平均，减少最近点切换时的跳变；
+// 远离采样点处的值是切平面的外推，开放点云的边缘之外会出现虚假的零等值面，ReconstructSurface会删除这些部分
+func SignedDistance(points, normals []*mat.VecDense, k int) func(*mat.VecDense) float64 {
+	tree := newKDTree(points)
+	k = min(k, len(points))
+	return func(x *mat.VecDense) float64 {
+		ids, dists := tree.nearest(x, k)
+		h := dists[len(dists)-1] + 1e-12
+
+		d := mat.NewVecDense(3, nil)
+		sum, weights := 0.0, 0.0
+		for j, id := range ids {
+			w := math.Exp(-dists[j] / h)
+			d.SubVec(x, points[id])
+			sum += w * mat.Dot(d, normals[id])
+			weights += w
+		}
+		return sum / weights
+	}
+}
+
+// ReconstructSurface 从点云重建三角网格: 估计法向量(normals为nil时)，构造带符号距离函数并用MarchingCubes提取零等值面
+// resolution为包围盒最长边上的体素数，输出三角形的法向量指向外侧
+func ReconstructSurface(points, normals []*mat.VecDense, resolution int) []*Triangle {
+	if len(points) < 3 || resolution < 1 {
+		return []*Triangle{}
+	}
+	if normals == nil {
+		normals = EstimateNormals(points, normalNeighbors)
+	}
+
+	lo, hi := mat.VecDenseCopyOf(points[0]), mat.VecDenseCopyOf(points[0])
+	for _, p := range points[1:] {
+		lo, hi = MinVec(lo, p), MaxVec(hi, p)
+	}
+	size := math.Max(hi.AtVec(0)-lo.AtVec(0), math.Max(hi.AtVec(1)-lo.AtVec(1), hi.AtVec(2)-lo.AtVec(2)))
+	if size == 0 {
+		return []*Triangle{}
+	}
+
+	// 包围盒每侧留出两个体素，保证曲面在网格内闭合
+	cell := size / float64(resolution)
+	st, ed, N := make([]float64, 3), make([]float64, 3), make([]int, 3)
+	for d := 0; d < 3; d++ {
+		N[d] = int(math.Ceil((hi.AtVec(d)-lo.AtVec(d))/cell)) + 4
+		st[d] = lo.AtVec(d) - 2*cell
+		ed[d] = st[d] + float64(N[d])*cell
+	}
+
+	// MarchingCubes生成的三角形法向量指向函数值为负的内侧，交换两个顶点使其指向外侧
+	tris := MarchingCubes(SignedDistance(points, normals, distanceNeighbors), st, ed, N)
+	for _, tri := range tris {
+		tri.P[1], tri.P[2] = tri.P[2], tri.P[1]
+	}
+	return maskDistantTriangles(tris, points, maskSpacings)
+}
+
+// maskDistantTriangles 删除有顶点离最近采样点超过spacings倍平均点距的三角形，
+// 即开放点云边缘之外由距离函数外推产生的部分；封闭点云的重建结果不受影响
+func maskDistantTriangles(tris []*Triangle, points []*mat.VecDense, spacings float64) []*Triangle {
+	tree := newKDTree(points)
+	spacing := 0.0
+	for _, p := range points {
+		_, dists := tree.nearest(p, 2)
+		spacing += math.Sqrt(dists[1])
+	}
+	limit := spacings * spacing / float64(len(points))
+
+	res := tris[:0]
+	for _, tri := range tris {
+		keep := true
+		for _, p := range tri.P {
+			if _, dists := tree.nearest(p, 1); math.Sqrt(dists[0]) > limit {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			res = append(res, tri)
+		}
+	}
+	return res
+}
@@ -0,0 +1,81 @@
+package math_lib
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// fibonacciSphere 返回半径为r的球面上n个近似均匀分布的点
+func fibonacciSphere(n int, r float64) []*mat.VecDense {
+	points := make([]*mat.VecDense, n)
+	golden := math.Pi * (3 - math.Sqrt(5))
+	for i := range points {
+		z := 1 - (2*float64(i)+1)/float64(n)
+		rho := math.Sqrt(1 - z*z)
+		points[i] = vec3(r*rho*math.Cos(golden*float64(i)), r*rho*math.Sin(golden*float64(i)), r*z)
+	}
+	return points
+}
+
+// enclosedVolume 用散度定理计算三角形集合围成的有向体积，法向量朝外时为正
+func enclosedVolume(tris []*Triangle) float64 {
+	volume := 0.0
+	for _, tri := range tris {
+		n := Cross2(SubVec(mat.NewVecDense(3, nil), tri.P[1], tri.P[0]), SubVec(mat.NewVecDense(3, nil), tri.P[2], tri.P[0]))
+		volume += mat.Dot(tri.P[0], n) / 6
+	}
+	return volume
+}
+
+func TestEstimateNormals(t *testing.T) {
+	points := fibonacciSphere(400, 2)
+	for i, n := range EstimateNormals(points, normalNeighbors) {
+		if c := mat.Dot(n, points[i]) / 2; c < 0.95 {
+			t.Fatalf("normal at %v deviates from the outward radial direction (cos %g)", points[i].RawVector().Data, c)
+		}
+	}
+}
+
+func TestReconstructSurface(t *testing.T) {
+	points := fibonacciSphere(800, 1)
+	tris := ReconstructSurface(points, nil, 12)
+	if len(tris) == 0 {
+		t.Fatal("empty reconstruction")
+	}
+	if volume, want := enclosedVolume(tris), 4*math.Pi/3; math.Abs(volume-want) > 0.05*want {
+		t.Errorf("enclosed volume %g, want %g", volume, want)
+	}
+	for _, tri := range tris {
+		for _, p := range tri.P {
+			if r := mat.Norm(p, 2); math.Abs(r-1) > 0.05 {
+				t.Fatalf("vertex %v is %g away from the sphere", p.RawVector().Data, r-1)
+			}
+		}
+	}
+}
+
+func TestReconstructOpenSurface(t *testing.T) {
+	// 边长为2的正方形点阵，法向量朝+z；距离函数的零等值面会外推到包围盒的边缘
+	var points, normals []*mat.VecDense
+	for i := 0; i <= 40; i++ {
+		for j := 0; j <= 40; j++ {
+			points = append(points, vec3(float64(i)/20, float64(j)/20, 0))
+			normals = append(normals, vec3(0, 0, 1))
+		}
+	}
+	tris := ReconstructSurface(points, normals, 8)
+	area := 0.0
+	for _, tri := range tris {
+		for _, p := range tri.P {
+			if p.AtVec(0) < -0.15-1e-9 || p.AtVec(0) > 2.15+1e-9 || p.AtVec(1) < -0.15-1e-9 || p.AtVec(1) > 2.15+1e-9 {
+				t.Fatalf("vertex %v is far from the samples", p.RawVector().Data)
+			}
+		}
+		area += SurfaceArea([]*Triangle{tri})
+	}
+	if math.Abs(area-4) > 1e-9 {
+		t.Errorf("area %g, want 4", area)
+	}
+}
@@ -0,0 +1,312 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"sort"
+)
+
+// dtTetra 四面体剖分中的四面体，满足Orient3D(v[0], v[1], v[2], v[3]) > 0，n[i]为与顶点v[i]相对的面另一侧的四面体
+// 幽灵四面体的无穷远点固定在v[3]，其有限面的正侧为凸包外部
+type dtTetra struct {
+	v    [4]int
+	n    [4]int
+	dead bool
+	mark int
+}
+
+// tetrahedralization 基于幽灵四面体的三维增量Delaunay四面体剖分
+type tetrahedralization struct {
+	points []*mat.VecDense
+	tets   []dtTetra
+	free   []int
+	last   int
+	stamp  int
+}
+
+// DelaunayTetrahedralization 使用Bowyer-Watson方法对点集进行三维Delaunay四面体剖分
+// 重复点只保留第一个，全部共面时返回空
+func DelaunayTetrahedralization(points []*mat.VecDense) []Tetrahedron {
+	t := newTetrahedralization(points)
+	if t == nil {
+		return []Tetrahedron{}
+	}
+	return t.finiteTetrahedra()
+}
+
+// newTetrahedralization 对去重后的点集进行四面体剖分，点数不足或全部共面时返回nil
+func newTetrahedralization(points []*mat.VecDense) *tetrahedralization {
+	t := &tetrahedralization{points: uniquePoints3D(points)}
+	n := len(t.points)
+	if n < 4 {
+		return nil
+	}
+
+	// 按字典序插入，相邻插入的点在空间上也相邻，定位时的行走路径较短
+	k := 2
+	for k < n && collinear3D(t.points[0], t.points[1], t.points[k]) {
+		k++
+	}
+	l := k + 1
+	for l < n && Orient3D(t.points[0], t.points[1], t.points[k], t.points[l]) == 0 {
+		l++
+	}
+	if l >= n {
+		return nil
+	}
+	t.initTetrahedron(0, 1, k, l)
+
+	for i := 2; i < n; i++ {
+		if i != k && i != l {
+			t.insert(i)
+		}
+	}
+	return t
+}
+
+// uniquePoints3D 按字典序排序并移除坐标相同的点，不修改输入切片
+func uniquePoints3D(points []*mat.VecDense) []*mat.VecDense {
+	sorted := make([]*mat.VecDense, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		for d := 0; d < 3; d++ {
+			if sorted[i].AtVec(d) != sorted[j].AtVec(d) {
+				return sorted[i].AtVec(d) < sorted[j].AtVec(d)
+			}
+		}
+		return false
+	})
+
+	res := make([]*mat.VecDense, 0, len(sorted))
+	for _, p := range sorted {
+		if len(res) > 0 && mat.Equal(res[len(res)-1], p) {
+			continue
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+// collinear3D 判断三点是否共线: 在三个坐标平面上的投影都共线
+func collinear3D(a, b, c *mat.VecDense) bool {
+	for _, axes := range [3][2]int{{0, 1}, {1, 2}, {2, 0}} {
+		project := func(p *mat.VecDense) *mat.VecDense {
+			return mat.NewVecDense(2, []float64{p.AtVec(axes[0]), p.AtVec(axes[1])})
+		}
+		if Orient2D(project(a), project(b), project(c)) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// initTetrahedron 以四个不共面的点建立初始四面体及其四个幽灵四面体
+func (t *tetrahedralization) initTetrahedron(a, b, c, d int) {
+	if Orient3D(t.points[a], t.points[b], t.points[c], t.points[d]) < 0 {
+		a, b = b, a
+	}
+
+	first := t.newTet([4]int{a, b, c, d})
+	created := []int{first}
+	for i := 0; i < 4; i++ {
+		v, f := t.tets[first].v, tetFaces[i]
+		created = append(created, t.newTet([4]int{v[f[0]], v[f[2]], v[f[1]], ghostVertex}))
+	}
+	t.linkTets(created)
+	t.last = first
+}
+
+// newTet 分配一个四面体，优先复用已删除的位置
+func (t *tetrahedralization) newTet(v [4]int) int {
+	tet := dtTetra{v: v, n: [4]int{-1, -1, -1, -1}}
+	if len(t.free) > 0 {
+		idx := t.free[len(t.free)-1]
+		t.free = t.free[:len(t.free)-1]
+		t.tets[idx] = tet
+		return idx
+	}
+	t.tets = append(t.tets, tet)
+	return len(t.tets) - 1
+}
+
+// linkTets 为一组新四面体中尚未设置邻接的面互相建立邻接关系
+func (t *tetrahedralization) linkTets(ids []int) {
+	faces := make(map[[3]int][2]int, 4*len(ids))
+	for _, id := range ids {
+		v := t.tets[id].v
+		for i, f := range tetFaces {
+			if t.tets[id].n[i] != -1 {
+				continue
+			}
+			key := sortedTriple(v[f[0]], v[f[1]], v[f[2]])
+			if other, ok := faces[key]; ok {
+				t.tets[id].n[i] = other[0]
+				t.tets[other[0]].n[other[1]] = id
+				delete(faces, key)
+				continue
+			}
+			faces[key] = [2]int{id, i}
+		}
+	}
+}
+
+// isGhost 判断四面体是否为幽灵四面体
+func (t *tetrahedralization) isGhost(idx int) bool {
+	return t.tets[idx].v[3] == ghostVertex
+}
+
+// faceOrient 计算点p相对于四面体第i个面的位置，正值表示与顶点v[i]同侧
+func (t *tetrahedralization) faceOrient(idx, i, p int) float64 {
+	v, f := t.tets[idx].v, tetFaces[i]
+	return Orient3D(t.points[v[f[0]]], t.points[v[f[1]]], t.points[v[f[2]]], t.points[p])
+}
+
+// inSphere 对有限四面体调用InSphere
+func (t *tetrahedralization) inSphere(idx, p int) float64 {
+	v := t.tets[idx].v
+	return InSphere(t.points[v[0]], t.points[v[1]], t.points[v[2]], t.points[v[3]], t.points[p])
+}
+
+// inConflict 判断点p是否在四面体的外接球内
+// 幽灵四面体的外接球为其有限面正侧的开半空间，p与该面共面时与相邻有限四面体的结果相同(两者的外接球与该平面交于同一个圆)
+func (t *tetrahedralization) inConflict(idx, p int) bool {
+	if !t.isGhost(idx) {
+		return t.inSphere(idx, p) > 0
+	}
+
+	v := t.tets[idx].v
+	if o := Orient3D(t.points[v[0]], t.points[v[1]], t.points[v[2]], t.points[p]); o != 0 {
+		return o > 0
+	}
+	return t.inSphere(t.tets[idx].n[3], p) > 0
+}
+
+// locate 从上次插入的位置出发行走，找到一个外接球包含点p的四面体
+func (t *tetrahedralization) locate(p int) int {
+	cur := t.last
+	if t.tets[cur].dead {
+		cur = t.anyAlive()
+	}
+	if t.isGhost(cur) {
+		cur = t.tets[cur].n[3]
+	}
+
+	for steps := 0; steps < 4*len(t.tets)+16; steps++ {
+		moved := false
+		for k := 0; k < 4; k++ {
+			i := (k + steps) % 4
+			if t.faceOrient(cur, i, p) < 0 {
+				cur = t.tets[cur].n[i]
+				moved = true
+				break
+			}
+		}
+		if !moved || t.isGhost(cur) {
+			return cur
+		}
+	}
+
+	// 行走未收敛时退化为线性查找
+	for idx := range t.tets {
+		if !t.tets[idx].dead && t.inConflict(idx, p) {
+			return idx
+		}
+	}
+	return cur
+}
+
+// anyAlive 返回任意一个未删除的四面体
+func (t *tetrahedralization) anyAlive() int {
+	for idx := range t.tets {
+		if !t.tets[idx].dead {
+			return idx
+		}
+	}
+	return -1
+}
+
+// insert 使用Bowyer-Watson方法插入点p: 删除外接球包含p的四面体，以p连接空腔边界
+func (t *tetrahedralization) insert(p int) {
+	start := t.locate(p)
+
+	t.stamp++
+	t.tets[start].mark = t.stamp
+	cavity := []int{start}
+	for i := 0; i < len(cavity); i++ {
+		for _, nb := range t.tets[cavity[i]].n {
+			if t.tets[nb].mark == t.stamp || !t.inConflict(nb, p) {
+				continue
+			}
+			t.tets[nb].mark = t.stamp
+			cavity = append(cavity, nb)
+		}
+	}
+	t.retriangulateCavity(p, cavity)
+}
+
+// retriangulateCavity 删除空腔内的四面体，并用点p与空腔边界的每个面组成新四面体
+func (t *tetrahedralization) retriangulateCavity(p int, cavity []int) {
+	type boundary struct {
+		v             [4]int
+		outside, slot int // 空腔外的相邻四面体及其指向空腔的面
+	}
+
+	faces := make([]boundary, 0, 2*len(cavity)+2)
+	for _, idx := range cavity {
+		tet := t.tets[idx]
+		for i, nb := range tet.n {
+			if t.tets[nb].mark == t.stamp {
+				continue
+			}
+			f := tetFaces[i]
+			slot := 0
+			for t.tets[nb].n[slot] != idx {
+				slot++
+			}
+			faces = append(faces, boundary{ghostLast([4]int{tet.v[f[0]], tet.v[f[1]], tet.v[f[2]], p}), nb, slot})
+		}
+	}
+
+	for _, idx := range cavity {
+		t.tets[idx].dead = true
+		t.free = append(t.free, idx)
+	}
+
+	created := make([]int, len(faces))
+	for k, f := range faces {
+		idx := t.newTet(f.v)
+		created[k] = idx
+		for i, v := range f.v {
+			if v == p {
+				t.tets[idx].n[i] = f.outside
+			}
+		}
+		t.tets[f.outside].n[f.slot] = idx
+	}
+	t.linkTets(created)
+	t.last = created[0]
+}
+
+// ghostLast 将无穷远点交换到v[3]，同时交换另外两个顶点以保持定向
+func ghostLast(v [4]int) [4]int {
+	for k := 0; k < 3; k++ {
+		if v[k] == ghostVertex {
+			v[k], v[3] = v[3], v[k]
+			o1, o2 := (k+1)%3, (k+2)%3
+			v[o1], v[o2] = v[o2], v[o1]
+			break
+		}
+	}
+	return v
+}
+
+// finiteTetrahedra 返回所有有限四面体
+func (t *tetrahedralization) finiteTetrahedra() []Tetrahedron {
+	res := make([]Tetrahedron, 0, len(t.tets))
+	for _, tet := range t.tets {
+		if tet.dead || tet.v[3] == ghostVertex {
+			continue
+		}
+		res = append(res, Tetrahedron{[4]*mat.VecDense{t.points[tet.v[0]], t.points[tet.v[1]], t.points[tet.v[2]], t.points[tet.v[3]]}})
+	}
+	return res
+}
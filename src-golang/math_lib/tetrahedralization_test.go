@@ -0,0 +1,63 @@
+package math_lib
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDelaunayTetrahedralization(t *testing.T) {
+	// 规则格点上大量五点共球，加上随机内点
+	var points []*mat.VecDense
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				points = append(points, vec3(float64(i), float64(j), float64(k)))
+			}
+		}
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 30; i++ {
+		points = append(points, vec3(2*rng.Float64(), 2*rng.Float64(), 2*rng.Float64()))
+	}
+
+	tets := DelaunayTetrahedralization(points)
+	volume := 0.0
+	for _, tet := range tets {
+		if Orient3D(tet.P[0], tet.P[1], tet.P[2], tet.P[3]) <= 0 {
+			t.Fatalf("tetrahedron %v is not positively oriented", tet)
+		}
+		for _, p := range points {
+			if InSphere(tet.P[0], tet.P[1], tet.P[2], tet.P[3], p) > 0 {
+				t.Fatalf("point %v lies inside the circumsphere of %v", p.RawVector().Data, tet)
+			}
+		}
+		volume += tet.GetVolume()
+	}
+	if math.Abs(volume-8) > 1e-9 {
+		t.Errorf("tetrahedra volume %g, want 8", volume)
+	}
+
+	// 边界面覆盖立方体表面，法向量朝外
+	area, enclosed := 0.0, 0.0
+	for _, tri := range BoundaryTriangles(tets) {
+		n := Cross2(SubVec(mat.NewVecDense(3, nil), tri.P[1], tri.P[0]), SubVec(mat.NewVecDense(3, nil), tri.P[2], tri.P[0]))
+		area += mat.Norm(n, 2) / 2
+		enclosed += mat.Dot(tri.P[0], n) / 6
+	}
+	if math.Abs(area-24) > 1e-9 || math.Abs(enclosed-8) > 1e-9 {
+		t.Errorf("boundary area %g and enclosed volume %g, want 24 and 8", area, enclosed)
+	}
+}
+
+func TestOrient3DNearlyCoplanar(t *testing.T) {
+	a, b, c := vec3(0, 0, 0), vec3(1, 0, 1), vec3(0, 1, 1)
+	if s := Orient3D(a, b, c, vec3(0.375, 0.125, 0.5)); s != 0 {
+		t.Errorf("point on the plane: got %g", s)
+	}
+	if s := Orient3D(a, b, c, vec3(0.375, 0.125, math.Nextafter(0.5, 1))); s == 0 {
+		t.Error("point off the plane reported as coplanar")
+	}
+}
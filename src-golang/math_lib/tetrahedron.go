@@ -0,0 +1,74 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// Tetrahedron 四面体，顶点满足Orient3D(P[0], P[1], P[2], P[3]) > 0
+type Tetrahedron struct {
+	P [4]*mat.VecDense `json:"p"`
+}
+
+// tetFaces 与顶点i相对的面，按该顺序排列时顶点i位于面的正侧(见Orient3D)
+var tetFaces = [4][3]int{{1, 3, 2}, {0, 2, 3}, {0, 3, 1}, {0, 1, 2}}
+
+// GetVolume 计算四面体的有向体积
+func (t *Tetrahedron) GetVolume() float64 {
+	e1 := SubVec(mat.NewVecDense(3, nil), t.P[1], t.P[0])
+	e2 := SubVec(mat.NewVecDense(3, nil), t.P[2], t.P[0])
+	e3 := SubVec(mat.NewVecDense(3, nil), t.P[3], t.P[0])
+	return mat.Dot(Cross2(e1, e2), e3) / 6
+}
+
+// BoundaryTriangles 提取一组四面体的边界面: 只属于一个四面体的面，法向量指向外侧
+func BoundaryTriangles(tets []Tetrahedron) []Triangle {
+	index := make(map[*mat.VecDense]int)
+	id := func(p *mat.VecDense) int {
+		if i, ok := index[p]; ok {
+			return i
+		}
+		index[p] = len(index)
+		return index[p]
+	}
+
+	type face struct {
+		tri   Triangle
+		count int
+	}
+	faces := make(map[[3]int]*face, 2*len(tets))
+	var order [][3]int
+	for _, t := range tets {
+		for _, f := range tetFaces {
+			a, b, c := t.P[f[0]], t.P[f[2]], t.P[f[1]] // 反转面的顺序使法向量指向外侧
+			key := sortedTriple(id(a), id(b), id(c))
+			if existing, ok := faces[key]; ok {
+				existing.count++
+				continue
+			}
+			faces[key] = &face{Triangle{[3]*mat.VecDense{a, b, c}}, 1}
+			order = append(order, key)
+		}
+	}
+
+	res := make([]Triangle, 0, len(order))
+	for _, key := range order {
+		if f := faces[key]; f.count == 1 {
+			res = append(res, f.tri)
+		}
+	}
+	return res
+}
+
+// sortedTriple 将三个下标按升序排列，用作面的键
+func sortedTriple(a, b, c int) [3]int {
+	if a > b {
+		a, b = b, a
+	}
+	if b > c {
+		b, c = c, b
+	}
+	if a > b {
+		a, b = b, a
+	}
+	return [3]int{a, b, c}
+}
@@ -1 +1,182 @@
 package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// maxRejections 拒绝采样时每个采样点的最大尝试次数，防止区域为空时无法终止
+const maxRejections = 1000
+
+// SamplePoint 采样点及其单位法向量
+type SamplePoint struct {
+	Position *mat.VecDense
+	Normal   *mat.VecDense
+}
+
+// SampleTriangles 在三角网格表面按面积加权均匀采样n个点，法向量取所在三角形的法向量；rng为nil时使用随机种子
+func SampleTriangles(tris []*Triangle, n int, rng *rand.Rand) []SamplePoint {
+	rng = samplingRand(rng)
+	cumulative, total := cumulativeAreas(tris)
+	if total == 0 {
+		return []SamplePoint{}
+	}
+
+	res := make([]SamplePoint, n)
+	for i := range res {
+		res[i] = sampleOnTriangles(tris, cumulative, total, rng)
+	}
+	return res
+}
+
+// PoissonDiskSampleTriangles 在三角网格表面生成蓝噪声采样: 任意两点的欧氏距离不小于radius
+// 先按面积均匀生成远多于所需数量的候选点，再按随机顺序逐个接受与已接受点距离足够远的候选点
+func PoissonDiskSampleTriangles(tris []*Triangle, radius float64, rng *rand.Rand) []SamplePoint {
+	rng = samplingRand(rng)
+	cumulative, total := cumulativeAreas(tris)
+	if total == 0 || radius <= 0 {
+		return []SamplePoint{}
+	}
+
+	// 六边形密铺时每个点占据约(√3/2)r²的面积，候选点数取其10倍
+	candidates := int(math.Ceil(10 * total / (math.Sqrt(3) / 2 * radius * radius)))
+	grid := make(map[[3]int][]int)
+	cellOf := func(p *mat.VecDense) [3]int {
+		return [3]int{int(math.Floor(p.AtVec(0) / radius)), int(math.Floor(p.AtVec(1) / radius)), int(math.Floor(p.AtVec(2) / radius))}
+	}
+
+	var res []SamplePoint
+	for i := 0; i < candidates; i++ {
+		s := sampleOnTriangles(tris, cumulative, total, rng)
+		cell := cellOf(s.Position)
+		if !hasNeighborWithin(res, grid, cell, s.Position, radius) {
+			grid[cell] = append(grid[cell], len(res))
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// hasNeighborWithin 在网格相邻的27个单元中查找与p距离小于radius的已接受点
+func hasNeighborWithin(samples []SamplePoint, grid map[[3]int][]int, cell [3]int, p *mat.VecDense, radius float64) bool {
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			for dz := -1; dz <= 1; dz++ {
+				for _, i := range grid[[3]int{cell[0] + dx, cell[1] + dy, cell[2] + dz}] {
+					if squaredDistance(samples[i].Position, p) < radius*radius {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// SampleImplicitVolume 在[st, ed]范围内对隐函数f<0的区域均匀采样n个点，法向量取f的中心差分梯度方向
+// 区域过小时返回的点数可能少于n
+func SampleImplicitVolume(f func(*mat.VecDense) float64, st, ed []float64, n int, rng *rand.Rand) []SamplePoint {
+	rng = samplingRand(rng)
+	h := 1e-6 * math.Max(ed[0]-st[0], math.Max(ed[1]-st[1], ed[2]-st[2]))
+
+	res := make([]SamplePoint, 0, n)
+	for attempts := 0; len(res) < n && attempts < n*maxRejections; attempts++ {
+		p := mat.NewVecDense(3, []float64{
+			st[0] + rng.Float64()*(ed[0]-st[0]),
+			st[1] + rng.Float64()*(ed[1]-st[1]),
+			st[2] + rng.Float64()*(ed[2]-st[2]),
+		})
+		if f(p) >= 0 {
+			continue
+		}
+		normal := mat.NewVecDense(3, nil)
+		for d := 0; d < 3; d++ {
+			q := mat.VecDenseCopyOf(p)
+			q.SetVec(d, p.AtVec(d)+h)
+			forward := f(q)
+			q.SetVec(d, p.AtVec(d)-h)
+			normal.SetVec(d, (forward-f(q))/(2*h))
+		}
+		res = append(res, SamplePoint{p, Normalize(normal)})
+	}
+	return res
+}
+
+// SampleParametricSurface 在参数曲面上按面积均匀采样n个点，法向量取 Fu×Fv 的方向
+// 在参数域内均匀取点，再按面积元|Fu×Fv|与其最大值之比接受
+func SampleParametricSurface(f func(u, v float64) (x, y, z float64), uRange, vRange []float64, n int, rng *rand.Rand) []SamplePoint {
+	rng = samplingRand(rng)
+	s := &ParametricSurface{F: f}
+	jacobian := func(u, v float64) float64 {
+		fu, fv := s.Derivatives(u, v)
+		return mat.Norm(Cross2(fu, fv), 2)
+	}
+
+	// 在参数网格上估计面积元的最大值，留出余量
+	const divisions = 64
+	maxJacobian := 0.0
+	for i := 0; i <= divisions; i++ {
+		for j := 0; j <= divisions; j++ {
+			u := uRange[0] + (uRange[1]-uRange[0])*float64(i)/divisions
+			v := vRange[0] + (vRange[1]-vRange[0])*float64(j)/divisions
+			maxJacobian = math.Max(maxJacobian, jacobian(u, v))
+		}
+	}
+	if maxJacobian == 0 {
+		return []SamplePoint{}
+	}
+	maxJacobian *= 1.2
+
+	res := make([]SamplePoint, 0, n)
+	for attempts := 0; len(res) < n && attempts < n*maxRejections; attempts++ {
+		u := uRange[0] + rng.Float64()*(uRange[1]-uRange[0])
+		v := vRange[0] + rng.Float64()*(vRange[1]-vRange[0])
+		if rng.Float64()*maxJacobian >= jacobian(u, v) {
+			continue
+		}
+		res = append(res, SamplePoint{s.Point(u, v), s.Normal(u, v)})
+	}
+	return res
+}
+
+// cumulativeAreas 计算三角形面积的前缀和
+func cumulativeAreas(tris []*Triangle) ([]float64, float64) {
+	cumulative := make([]float64, len(tris))
+	total := 0.0
+	for i, tri := range tris {
+		total += triangleArea(tri)
+		cumulative[i] = total
+	}
+	return cumulative, total
+}
+
+// triangleArea 计算三角形面积
+func triangleArea(tri *Triangle) float64 {
+	e1 := SubVec(mat.NewVecDense(3, nil), tri.P[1], tri.P[0])
+	e2 := SubVec(mat.NewVecDense(3, nil), tri.P[2], tri.P[0])
+	return mat.Norm(Cross2(e1, e2), 2) / 2
+}
+
+// sampleOnTriangles 按面积选取三角形，再在三角形内均匀选取一点
+func sampleOnTriangles(tris []*Triangle, cumulative []float64, total float64, rng *rand.Rand) SamplePoint {
+	x := rng.Float64() * total
+	tri := tris[sort.Search(len(cumulative), func(i int) bool { return cumulative[i] > x })]
+
+	// 重心坐标 (1-√r1, √r1(1-r2), √r1·r2) 在三角形内均匀分布
+	r1, r2 := math.Sqrt(rng.Float64()), rng.Float64()
+	p := mat.NewVecDense(3, nil)
+	p.AddScaledVec(p, 1-r1, tri.P[0])
+	p.AddScaledVec(p, r1*(1-r2), tri.P[1])
+	p.AddScaledVec(p, r1*r2, tri.P[2])
+	return SamplePoint{p, tri.GetNormal()}
+}
+
+// samplingRand rng为nil时返回以随机种子初始化的随机数生成器
+func samplingRand(rng *rand.Rand) *rand.Rand {
+	if rng != nil {
+		return rng
+	}
+	return rand.New(rand.NewSource(rand.Int63()))
+}
@@ -0,0 +1,102 @@
+package math_lib
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSampleTriangles(t *testing.T) {
+	// 两个三角形面积之比为3:1，法向量分别为+z与+x
+	tris := []*Triangle{
+		{[3]*mat.VecDense{vec3(0, 0, 0), vec3(3, 0, 0), vec3(0, 1, 0)}},
+		{[3]*mat.VecDense{vec3(5, 0, 0), vec3(5, 1, 0), vec3(5, 0, 1)}},
+	}
+	samples := SampleTriangles(tris, 4000, rand.New(rand.NewSource(1)))
+	if len(samples) != 4000 {
+		t.Fatalf("got %d samples, want 4000", len(samples))
+	}
+	large := 0
+	for _, s := range samples {
+		p := s.Position
+		switch {
+		case math.Abs(p.AtVec(2)) < 1e-12 && p.AtVec(0) >= 0 && p.AtVec(1) >= 0 && p.AtVec(0)/3+p.AtVec(1) <= 1+1e-12:
+			large++
+			if !sameVec(s.Normal, vec3(0, 0, 1), 1e-12) {
+				t.Fatalf("normal %v on the first triangle", s.Normal.RawVector().Data)
+			}
+		case math.Abs(p.AtVec(0)-5) < 1e-12 && p.AtVec(1) >= 0 && p.AtVec(2) >= 0 && p.AtVec(1)+p.AtVec(2) <= 1+1e-12:
+			if !sameVec(s.Normal, vec3(1, 0, 0), 1e-12) {
+				t.Fatalf("normal %v on the second triangle", s.Normal.RawVector().Data)
+			}
+		default:
+			t.Fatalf("sample %v is not on the mesh", p.RawVector().Data)
+		}
+	}
+	if f := float64(large) / 4000; math.Abs(f-0.75) > 0.03 {
+		t.Errorf("%.3f of the samples on the triangle with 3/4 of the area", f)
+	}
+}
+
+func TestPoissonDiskSampleTriangles(t *testing.T) {
+	tris := []*Triangle{
+		{[3]*mat.VecDense{vec3(0, 0, 0), vec3(1, 0, 0), vec3(1, 1, 0)}},
+		{[3]*mat.VecDense{vec3(0, 0, 0), vec3(1, 1, 0), vec3(0, 1, 0)}},
+	}
+	const radius = 0.05
+	samples := PoissonDiskSampleTriangles(tris, radius, rand.New(rand.NewSource(1)))
+	// 随机顺序接受的最大采样密度约为六边形密铺的一半以上
+	if len(samples) < 150 {
+		t.Errorf("only %d samples", len(samples))
+	}
+	for i := range samples {
+		for j := i + 1; j < len(samples); j++ {
+			if d := math.Sqrt(squaredDistance(samples[i].Position, samples[j].Position)); d < radius {
+				t.Fatalf("samples %d and %d are %g apart", i, j, d)
+			}
+		}
+	}
+}
+
+func TestSampleImplicitVolume(t *testing.T) {
+	sphere := func(p *mat.VecDense) float64 { return mat.Norm(p, 2) - 1 }
+	samples := SampleImplicitVolume(sphere, []float64{-2, -2, -2}, []float64{2, 2, 2}, 500, rand.New(rand.NewSource(1)))
+	if len(samples) != 500 {
+		t.Fatalf("got %d samples, want 500", len(samples))
+	}
+	for _, s := range samples {
+		r := mat.Norm(s.Position, 2)
+		if r >= 1 {
+			t.Fatalf("sample %v is outside the volume", s.Position.RawVector().Data)
+		}
+		if r > 0.1 && mat.Dot(s.Normal, s.Position)/r < 0.99 {
+			t.Fatalf("normal %v at %v is not radial", s.Normal.RawVector().Data, s.Position.RawVector().Data)
+		}
+	}
+}
+
+func TestSampleParametricSurface(t *testing.T) {
+	sphere := func(u, v float64) (x, y, z float64) {
+		return math.Sin(v) * math.Cos(u), math.Sin(v) * math.Sin(u), math.Cos(v)
+	}
+	samples := SampleParametricSurface(sphere, []float64{0, 2 * math.Pi}, []float64{0, math.Pi}, 4000, rand.New(rand.NewSource(1)))
+	if len(samples) != 4000 {
+		t.Fatalf("got %d samples, want 4000", len(samples))
+	}
+
+	// 球面上按面积均匀分布时z坐标在[-1, 1]上均匀分布
+	cap := 0
+	for _, s := range samples {
+		if math.Abs(mat.Norm(s.Position, 2)-1) > 1e-12 || math.Abs(mat.Dot(s.Normal, s.Position)) < 0.999 {
+			t.Fatalf("sample %v with normal %v", s.Position.RawVector().Data, s.Normal.RawVector().Data)
+		}
+		if s.Position.AtVec(2) > 0.5 {
+			cap++
+		}
+	}
+	if f := float64(cap) / 4000; math.Abs(f-0.25) > 0.03 {
+		t.Errorf("%.3f of the samples above z = 0.5, want 0.25", f)
+	}
+}
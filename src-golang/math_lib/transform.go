@@ -0,0 +1,97 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// TranslateMatrix 生成平移变换的4x4齐次矩阵
+func TranslateMatrix(delta *mat.VecDense) *mat.Dense {
+	m := identity4()
+	for i := 0; i < 3; i++ {
+		m.Set(i, 3, delta.AtVec(i))
+	}
+	return m
+}
+
+// RotateMatrix 生成绕过center、方向为axis的轴旋转theta弧度的4x4齐次矩阵 (Rodrigues公式)
+func RotateMatrix(axis, center *mat.VecDense, theta float64) *mat.Dense {
+	k := Normalize(mat.VecDenseCopyOf(axis))
+	x, y, z := k.AtVec(0), k.AtVec(1), k.AtVec(2)
+	c, s := math.Cos(theta), math.Sin(theta)
+	t := 1 - c
+
+	r := mat.NewDense(3, 3, []float64{
+		t*x*x + c, t*x*y - s*z, t*x*z + s*y,
+		t*x*y + s*z, t*y*y + c, t*y*z - s*x,
+		t*x*z - s*y, t*y*z + s*x, t*z*z + c,
+	})
+
+	// p' = R(p - c) + c = Rp + (c - Rc)
+	rc := MulVec(mat.NewVecDense(3, nil), r, center)
+	m := identity4()
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			m.Set(i, j, r.At(i, j))
+		}
+		m.Set(i, 3, center.AtVec(i)-rc.AtVec(i))
+	}
+	return m
+}
+
+// ScaleMatrix 生成以center为中心、各轴缩放ratio倍的4x4齐次矩阵
+func ScaleMatrix(ratio, center *mat.VecDense) *mat.Dense {
+	m := identity4()
+	for i := 0; i < 3; i++ {
+		m.Set(i, i, ratio.AtVec(i))
+		m.Set(i, 3, center.AtVec(i)*(1-ratio.AtVec(i)))
+	}
+	return m
+}
+
+// TransformPoint 对三维点施加4x4齐次变换
+func TransformPoint(m *mat.Dense, p *mat.VecDense) *mat.VecDense {
+	h := mat.NewVecDense(4, []float64{p.AtVec(0), p.AtVec(1), p.AtVec(2), 1})
+	h.MulVec(m, h)
+	w := h.AtVec(3)
+	if w == 0 {
+		w = 1
+	}
+	return mat.NewVecDense(3, []float64{h.AtVec(0) / w, h.AtVec(1) / w, h.AtVec(2) / w})
+}
+
+// TransformTriangles 对三角形集合施加4x4齐次变换，返回新的三角形，共享的顶点变换后仍然共享
+// 线性部分的行列式为负(镜像)时交换每个三角形的两个顶点，保持法向量朝外
+func TransformTriangles(m *mat.Dense, triangles []*Triangle) []*Triangle {
+	var (
+		res    = make([]*Triangle, len(triangles))
+		mapped = make(map[*mat.VecDense]*mat.VecDense)
+		mirror = mat.Det(m.Slice(0, 3, 0, 3)) < 0
+	)
+
+	for i, tri := range triangles {
+		newTri := &Triangle{}
+		for k := 0; k < 3; k++ {
+			p, ok := mapped[tri.P[k]]
+			if !ok {
+				p = TransformPoint(m, tri.P[k])
+				mapped[tri.P[k]] = p
+			}
+			newTri.P[k] = p
+		}
+		if mirror {
+			newTri.P[1], newTri.P[2] = newTri.P[2], newTri.P[1]
+		}
+		res[i] = newTri
+	}
+	return res
+}
+
+// identity4 返回4x4单位矩阵
+func identity4() *mat.Dense {
+	m := mat.NewDense(4, 4, nil)
+	for i := 0; i < 4; i++ {
+		m.Set(i, i, 1)
+	}
+	return m
+}
@@ -0,0 +1,158 @@
+package math_lib
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// VoronoiCell Voronoi图的一个单元，Polygon为裁剪到包围盒后逆时针排列的单元顶点
+type VoronoiCell struct {
+	Site    *mat.VecDense
+	Polygon []*mat.VecDense
+}
+
+// Voronoi 以Delaunay三角剖分的对偶构造Voronoi图，单元顶点为三角形外心，每个单元裁剪到min/max包围盒内
+// 只使用x/y分量，重复点只保留第一个；单元完全位于包围盒外时Polygon为空
+func Voronoi(points []*mat.VecDense, min, max *mat.VecDense) []VoronoiCell {
+	box := []*mat.VecDense{
+		mat.NewVecDense(3, []float64{min.AtVec(0), min.AtVec(1), 0}),
+		mat.NewVecDense(3, []float64{max.AtVec(0), min.AtVec(1), 0}),
+		mat.NewVecDense(3, []float64{max.AtVec(0), max.AtVec(1), 0}),
+		mat.NewVecDense(3, []float64{min.AtVec(0), max.AtVec(1), 0}),
+	}
+
+	t := newTriangulation(points)
+	if t == nil {
+		return collinearVoronoi(uniquePoints(points), box)
+	}
+
+	// 每个有限三角形的外心只计算一次
+	centers := make([]*mat.VecDense, len(t.tris))
+	lo, hi := MinVec(box[0], box[2]), MaxVec(box[0], box[2])
+	for idx, tri := range t.tris {
+		if tri.dead || tri.v[2] == ghostVertex {
+			continue
+		}
+		centers[idx], _ = circumcircle(t.points[tri.v[0]], t.points[tri.v[1]], t.points[tri.v[2]])
+		lo, hi = MinVec(lo, centers[idx]), MaxVec(hi, centers[idx])
+	}
+	for _, p := range t.points {
+		lo, hi = MinVec(lo, p), MaxVec(hi, p)
+	}
+	far := 4 * (math.Hypot(hi.AtVec(0)-lo.AtVec(0), hi.AtVec(1)-lo.AtVec(1)) + 1)
+
+	cells := make([]VoronoiCell, len(t.points))
+	for i, site := range t.points {
+		var polygon []*mat.VecDense
+		around := t.trianglesAround(i)
+		for k, idx := range around {
+			if !t.isGhost(idx) {
+				polygon = append(polygon, centers[idx])
+				continue
+			}
+
+			// 凸包顶点周围恰有两个相邻的幽灵三角形，两条射线之间沿其角平分方向补一个远点，保证截断后的单元覆盖包围盒
+			ray := t.rayDirection(idx)
+			center := centers[t.tris[idx].n[2]]
+			polygon = append(polygon, mat.NewVecDense(3, []float64{center.AtVec(0) + far*ray[0], center.AtVec(1) + far*ray[1], 0}))
+			if next := around[(k+1)%len(around)]; t.isGhost(next) {
+				d := t.rayDirection(next)
+				bx, by := ray[0]+d[0], ray[1]+d[1]
+				l := math.Hypot(bx, by)
+				polygon = append(polygon, mat.NewVecDense(3, []float64{site.AtVec(0) + 2*far*bx/l, site.AtVec(1) + 2*far*by/l, 0}))
+			}
+		}
+
+		for _, edge := range boxEdges(box) {
+			polygon = clipHalfPlane(polygon, edge[0], edge[1])
+		}
+		cells[i] = VoronoiCell{Site: site, Polygon: removeRepeatedVertices(polygon)}
+	}
+	return cells
+}
+
+// rayDirection 凸包边对应的Voronoi边是从相邻有限三角形外心出发的射线，返回其单位方向: 垂直于凸包边并指向凸包外部
+func (t *triangulation) rayDirection(idx int) [2]float64 {
+	v := t.tris[idx].v
+	a, b := t.points[v[0]], t.points[v[1]]
+	dx, dy := b.AtVec(0)-a.AtVec(0), b.AtVec(1)-a.AtVec(1)
+	length := math.Hypot(dx, dy)
+	return [2]float64{-dy / length, dx / length} // 幽灵三角形有限边的左侧为凸包外部
+}
+
+// collinearVoronoi 全部点共线(或不足三个点)时，单元为相邻点的中垂线之间的条带
+func collinearVoronoi(points []*mat.VecDense, box []*mat.VecDense) []VoronoiCell {
+	cells := make([]VoronoiCell, len(points))
+	for i, site := range points {
+		polygon := box
+		for _, j := range []int{i - 1, i + 1} {
+			if j < 0 || j >= len(points) {
+				continue
+			}
+			polygon = clipBisector(polygon, site, points[j])
+		}
+		cells[i] = VoronoiCell{Site: site, Polygon: removeRepeatedVertices(polygon)}
+	}
+	return cells
+}
+
+// clipBisector 保留多边形中离点a比离点b更近的部分
+func clipBisector(polygon []*mat.VecDense, a, b *mat.VecDense) []*mat.VecDense {
+	mx, my := (a.AtVec(0)+b.AtVec(0))/2, (a.AtVec(1)+b.AtVec(1))/2
+	dx, dy := b.AtVec(0)-a.AtVec(0), b.AtVec(1)-a.AtVec(1)
+	return clipHalfPlane(polygon,
+		mat.NewVecDense(3, []float64{mx, my, 0}),
+		mat.NewVecDense(3, []float64{mx - dy, my + dx, 0}))
+}
+
+// boxEdges 返回逆时针包围盒的四条边
+func boxEdges(box []*mat.VecDense) [][2]*mat.VecDense {
+	edges := make([][2]*mat.VecDense, len(box))
+	for i := range box {
+		edges[i] = [2]*mat.VecDense{box[i], box[(i+1)%len(box)]}
+	}
+	return edges
+}
+
+// clipHalfPlane 用有向直线ab左侧的闭半平面裁剪多边形 (Sutherland-Hodgman)
+func clipHalfPlane(polygon []*mat.VecDense, a, b *mat.VecDense) []*mat.VecDense {
+	side := func(p *mat.VecDense) float64 {
+		return (b.AtVec(0)-a.AtVec(0))*(p.AtVec(1)-a.AtVec(1)) - (b.AtVec(1)-a.AtVec(1))*(p.AtVec(0)-a.AtVec(0))
+	}
+
+	res := make([]*mat.VecDense, 0, len(polygon)+1)
+	for i, cur := range polygon {
+		next := polygon[(i+1)%len(polygon)]
+		sc, sn := side(cur), side(next)
+		if sc >= 0 {
+			res = append(res, cur)
+		}
+		if (sc > 0 && sn < 0) || (sc < 0 && sn > 0) {
+			s := sc / (sc - sn)
+			res = append(res, mat.NewVecDense(3, []float64{
+				cur.AtVec(0) + s*(next.AtVec(0)-cur.AtVec(0)),
+				cur.AtVec(1) + s*(next.AtVec(1)-cur.AtVec(1)),
+				0,
+			}))
+		}
+	}
+	return res
+}
+
+// removeRepeatedVertices 移除多边形中相邻的重复顶点(共圆的点会产生相同的外心)，不足三个顶点时返回空
+func removeRepeatedVertices(polygon []*mat.VecDense) []*mat.VecDense {
+	res := make([]*mat.VecDense, 0, len(polygon))
+	for _, p := range polygon {
+		if len(res) > 0 && pointsEqual(res[len(res)-1], p) {
+			continue
+		}
+		res = append(res, p)
+	}
+	for len(res) > 1 && pointsEqual(res[0], res[len(res)-1]) {
+		res = res[:len(res)-1]
+	}
+	if len(res) < 3 {
+		return nil
+	}
+	return res
+}
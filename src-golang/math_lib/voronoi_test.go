@@ -0,0 +1,68 @@
+package math_lib
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// polygonArea2D 返回多边形在xy平面上的有向面积
+func polygonArea2D(polygon []*mat.VecDense) float64 {
+	area := 0.0
+	for i, p := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+		area += p.AtVec(0)*q.AtVec(1) - q.AtVec(0)*p.AtVec(1)
+	}
+	return area / 2
+}
+
+// checkVoronoi 检查单元面积之和等于包围盒面积，且包围盒内的点位于最近站点的单元内
+func checkVoronoi(t *testing.T, points []*mat.VecDense, cells []VoronoiCell, min, max *mat.VecDense) {
+	t.Helper()
+	total := 0.0
+	for _, c := range cells {
+		total += polygonArea2D(c.Polygon)
+	}
+	if want := (max.AtVec(0) - min.AtVec(0)) * (max.AtVec(1) - min.AtVec(1)); math.Abs(total-want) > 1e-9*want {
+		t.Errorf("cells cover area %g, want %g", total, want)
+	}
+
+	rng := rand.New(rand.NewSource(2))
+	for k := 0; k < 200; k++ {
+		p := vec3(min.AtVec(0)+rng.Float64()*(max.AtVec(0)-min.AtVec(0)), min.AtVec(1)+rng.Float64()*(max.AtVec(1)-min.AtVec(1)), 0)
+		best, bestDist := -1, math.Inf(1)
+		for i, c := range cells {
+			if d := math.Hypot(c.Site.AtVec(0)-p.AtVec(0), c.Site.AtVec(1)-p.AtVec(1)); d < bestDist {
+				best, bestDist = i, d
+			}
+		}
+		polygon := cells[best].Polygon
+		for i := range polygon {
+			if Orient2D(polygon[i], polygon[(i+1)%len(polygon)], p) < -1e-9 {
+				t.Fatalf("point %v is outside the cell of its nearest site %v", p.RawVector().Data, cells[best].Site.RawVector().Data)
+			}
+		}
+	}
+}
+
+func TestVoronoi(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var points []*mat.VecDense
+	for i := 0; i < 50; i++ {
+		points = append(points, vec3(rng.Float64(), rng.Float64(), 0))
+	}
+	min, max := vec3(-0.5, -0.5, 0), vec3(1.5, 1.5, 0)
+	cells := Voronoi(points, min, max)
+	if len(cells) != len(points) {
+		t.Fatalf("got %d cells for %d sites", len(cells), len(points))
+	}
+	checkVoronoi(t, points, cells, min, max)
+}
+
+func TestVoronoiCollinear(t *testing.T) {
+	points := []*mat.VecDense{vec3(0, 0, 0), vec3(1, 1, 0), vec3(3, 3, 0)}
+	min, max := vec3(-2, -2, 0), vec3(5, 5, 0)
+	checkVoronoi(t, points, Voronoi(points, min, max), min, max)
+}